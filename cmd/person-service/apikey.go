@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"person-service/internal/audit"
+	"person-service/internal/auth"
+	"person-service/internal/models"
+	"person-service/internal/repository/postgres"
+	"person-service/internal/tenant"
+	"strings"
+	"time"
+)
+
+// runAPIKey выполняет подкоманду apikey create: сохраняет хэш нового ключа API в таблице api_keys
+// и выводит сам ключ в stdout. Ключ не хранится и повторно не выводится.
+func runAPIKey(args []string) error {
+	if len(args) == 0 || args[0] != "create" {
+		fmt.Fprintln(os.Stderr, "Использование: person-service apikey create [флаги]")
+		return fmt.Errorf("неизвестная команда apikey")
+	}
+	flags := flag.NewFlagSet("apikey create", flag.ExitOnError)
+	name := flags.String("name", "", "имя ключа; клиент получает субъект api_key:<имя>")
+	roles := flags.String("roles", "", "роли ключа через запятую, например editor")
+	tenantID := flags.String("tenant", "", "арендатор, к данным которого ограничен ключ; пустой — выбор заголовком X-Tenant-ID")
+	expires := flags.Duration("expires", 0, "срок действия ключа, например 720h; 0 — бессрочный")
+	key := flags.String("key", "", "сохранить указанный ключ вместо случайного, например для локальной разработки")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Использование: person-service apikey create [флаги]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args[1:])
+	if *name == "" {
+		return fmt.Errorf("не задано имя ключа: укажите -name")
+	}
+	if *roles == "" {
+		return fmt.Errorf("не заданы роли ключа: укажите -roles")
+	}
+	if *expires < 0 {
+		return fmt.Errorf("-expires не может быть отрицательным")
+	}
+	if *tenantID != "" {
+		if err := tenant.Validate(*tenantID); err != nil {
+			return err
+		}
+	}
+
+	ctx := audit.WithMeta(context.Background(), audit.Meta{Source: audit.SourceCLI})
+	a, err := newApp(ctx)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	apiKey := &models.APIKey{Name: *name, Roles: strings.Split(*roles, ",")}
+	for i, role := range apiKey.Roles {
+		apiKey.Roles[i] = strings.TrimSpace(role)
+		if !a.policy.HasRole(apiKey.Roles[i]) {
+			return fmt.Errorf("неизвестная роль %q", apiKey.Roles[i])
+		}
+	}
+	if *tenantID != "" {
+		apiKey.TenantID = tenantID
+	}
+	if *expires > 0 {
+		// Столбцы api_keys без часового пояса хранят время в UTC
+		expiresAt := time.Now().UTC().Add(*expires)
+		apiKey.ExpiresAt = &expiresAt
+	}
+	if *key == "" {
+		if *key, err = auth.GenerateAPIKey(); err != nil {
+			return err
+		}
+	}
+	apiKey.KeyHash = auth.HashAPIKey(*key)
+
+	repo, err := postgres.NewAPIKeyRepository(a.db.Pool)
+	if err != nil {
+		return err
+	}
+	if err := repo.Create(ctx, apiKey); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Создан ключ API %s (ID %d); передавайте его в заголовке X-API-Key, повторно он не выводится\n", apiKey.Name, apiKey.ID)
+	fmt.Println(*key)
+	return nil
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"person-service/internal/config"
+)
+
+// runConfig выполняет подкоманду config print: выводит действующую конфигурацию в формате .env
+// со скрытыми паролями, секретами и ключами API. Подключение к базе данных не требуется.
+func runConfig(args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "Использование: person-service config print")
+		return fmt.Errorf("неизвестная команда config")
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки конфигурации: %w", err)
+	}
+	return cfg.Print(os.Stdout)
+}
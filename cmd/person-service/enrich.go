@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"person-service/internal/audit"
+	"person-service/internal/tenant"
+	"time"
+)
+
+// runEnrich выполняет подкоманду enrich: повторно обогащает записи, которые не обогащались
+// дольше stale-days дней, и выводит итог в stdout.
+func runEnrich(args []string) error {
+	flags := flag.NewFlagSet("enrich", flag.ExitOnError)
+	staleDays := flags.Int("stale-days", 30, "обогатить записи, обогащение которых старше указанного числа дней")
+	limit := flags.Int("limit", 0, "обработать не больше указанного числа записей; 0 — все")
+	tenantID := flags.String("tenant", tenant.Default, "арендатор, записи которого обогащаются")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Использование: person-service enrich [флаги]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if *staleDays <= 0 {
+		return fmt.Errorf("-stale-days должен быть положительным")
+	}
+	if *limit < 0 {
+		return fmt.Errorf("-limit не может быть отрицательным")
+	}
+	if err := tenant.Validate(*tenantID); err != nil {
+		return err
+	}
+
+	ctx := audit.WithMeta(context.Background(), audit.Meta{Source: audit.SourceCLI})
+	a, err := newApp(ctx)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	before := time.Now().AddDate(0, 0, -*staleDays)
+	result, err := a.svc.Reenrich(tenant.WithID(ctx, *tenantID), before, *limit)
+	// Итог выводится и при ошибке: записи, обработанные до неё, уже сохранены
+	if result != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if encodeErr := encoder.Encode(result); err == nil {
+			err = encodeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"person-service/internal/exporter"
+	"person-service/internal/models"
+	"person-service/internal/tenant"
+	"strings"
+)
+
+// runExport выполняет подкоманду export: выгружает записи под фильтрами в файл или stdout.
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", string(exporter.FormatCSV), "формат выгрузки (csv, ndjson, parquet)")
+	output := flags.String("o", "", "файл выгрузки; по умолчанию stdout")
+	filter := flags.String("filter", "", "фильтры списка, например gender=female,nationality=RU")
+	includeDeleted := flags.Bool("include-deleted", false, "выгрузить и удалённые записи")
+	tenantID := flags.String("tenant", tenant.Default, "арендатор, записи которого выгружаются")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Использование: person-service export [флаги]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return fmt.Errorf("лишние аргументы: %s", strings.Join(flags.Args(), " "))
+	}
+	exportFormat, err := exporter.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+	filters, err := parseFilters(*filter)
+	if err != nil {
+		return err
+	}
+	if *includeDeleted {
+		filters["include_deleted"] = "true"
+	}
+	if err := tenant.Validate(*tenantID); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	a, err := newApp(ctx)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	writer, err := exporter.NewWriter(exportFormat, out)
+	if err != nil {
+		return err
+	}
+	exported := 0
+	err = a.svc.Export(tenant.WithID(ctx, *tenantID), filters, func(person *models.Person) error {
+		exported++
+		return writer.Write(person)
+	})
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Выгружено записей: %d\n", exported)
+	return nil
+}
+
+// parseFilters разбирает фильтры вида key=value через запятую.
+func parseFilters(value string) (map[string]string, error) {
+	filters := make(map[string]string)
+	if value == "" {
+		return filters, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("некорректный фильтр %q: ожидается key=value", pair)
+		}
+		filters[key] = strings.TrimSpace(val)
+	}
+	return filters, nil
+}
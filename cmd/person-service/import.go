@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"person-service/internal/audit"
+	"person-service/internal/importer"
+	"person-service/internal/tenant"
+)
+
+// runImport выполняет подкоманду import: импортирует записи из файла CSV или XLSX
+// и выводит отчёт в stdout. Если импорт прерван после сохранения части записей, отчёт выводится
+// вместе с ошибкой, и команда завершается с ненулевым кодом.
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", "", "формат файла (csv, xlsx); по умолчанию по расширению")
+	encoding := flags.String("encoding", "auto", "кодировка CSV (auto, utf-8, windows-1251)")
+	mapping := flags.String("mapping", "", "сопоставление колонок, например name=Имя,surname=2")
+	enrich := flags.Bool("enrich", false, "обогатить данные через внешние API")
+	reportFormat := flags.String("report", "json", "формат отчёта (json, csv)")
+	tenantID := flags.String("tenant", tenant.Default, "арендатор, которому принадлежат записи")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Использование: person-service import [флаги] файл")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("не указан импортируемый файл")
+	}
+	if *reportFormat != "json" && *reportFormat != "csv" {
+		return fmt.Errorf("некорректный формат отчёта: %s", *reportFormat)
+	}
+	if err := tenant.Validate(*tenantID); err != nil {
+		return err
+	}
+
+	opts := importer.Options{Encoding: *encoding, Enrich: *enrich}
+	var err error
+	if opts.Mapping, err = importer.ParseMapping(*mapping); err != nil {
+		return err
+	}
+	path := flags.Arg(0)
+	if *format != "" {
+		opts.Format, err = importer.ParseFormat(*format)
+	} else {
+		opts.Format, err = importer.FormatFromFilename(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	ctx := audit.WithMeta(context.Background(), audit.Meta{Source: audit.SourceCLI})
+	a, err := newApp(ctx)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	ctx = tenant.WithID(ctx, *tenantID)
+	report, err := importer.NewImporter(a.svc, a.logr.Logger).Import(ctx, file, opts)
+	if err != nil && report.Rows == 0 {
+		return err
+	}
+	// Отчёт выводится и для прерванного импорта: в нём ID записей, сохранённых до ошибки
+	if err != nil {
+		report.Error = err.Error()
+	}
+	if writeErr := writeImportReport(report, *reportFormat); writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+// writeImportReport выводит отчёт об импорте в stdout в формате json или csv.
+func writeImportReport(report *importer.Report, format string) error {
+	if format == "csv" {
+		return report.WriteCSV(os.Stdout)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
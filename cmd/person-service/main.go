@@ -2,15 +2,27 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"person-service/internal/api"
+	"person-service/internal/auth"
 	"person-service/internal/config"
+	"person-service/internal/grpcapi"
+	"person-service/internal/health"
+	"person-service/internal/metrics"
+	"person-service/internal/ratelimit"
 	"person-service/internal/repository/postgres"
 	"person-service/internal/service"
+	"person-service/internal/tracing"
+	"person-service/migrations"
 	"person-service/pkg/logger"
 	pg "person-service/pkg/postgres"
+	"sync"
 	"syscall"
 	"time"
 
@@ -24,66 +36,275 @@ import (
 // @description API для управления записями о людях с обогащением данных из внешних источников.
 // @host localhost:8081
 // @BasePath /api/v1
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT в виде "Bearer <токен>"
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+	if command == "help" || command == "-h" || command == "--help" {
+		usage(os.Stdout)
+		return
+	}
+	run, ok := commands[command]
+	if !ok {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+	if err := run(args); err != nil {
+		log.Fatalf("Ошибка команды %s: %v", command, err)
+	}
+}
+
+// commands сопоставляет подкоманды CLI с функциями, которые их выполняют.
+var commands = map[string]func(args []string) error{
+	"serve":         runServe,
+	"migrate":       runMigrate,
+	"seed":          runSeed,
+	"import":        runImport,
+	"export":        runExport,
+	"enrich":        runEnrich,
+	"purge-deleted": runPurgeDeleted,
+	"apikey":        runAPIKey,
+	"config":        runConfig,
+}
+
+// usage выводит список подкоманд.
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Использование: person-service [команда] [флаги]
+
+Команды:
+  serve          запустить HTTP- и gRPC-серверы (по умолчанию)
+  migrate        применить, откатить или показать миграции схемы: up|down|status|version
+  seed           создать N записей со случайными данными
+  import         импортировать записи из файла CSV или XLSX
+  export         выгрузить записи в CSV, NDJSON или Parquet
+  enrich         повторно обогатить записи, обогащение которых устарело
+  purge-deleted  физически удалить записи, помеченные удалёнными
+  apikey create  создать ключ API и вывести его один раз
+  config print   вывести действующую конфигурацию без секретов
+
+Флаги команды: person-service <команда> -h
+`)
+}
+
+// app содержит зависимости, общие для сервера и подкоманд CLI.
+type app struct {
+	cfg    *config.Config
+	logr   *logger.Logger
+	db     *pg.Pool
+	repo   *postgres.PersonRepository
+	svc    *service.PersonService
+	policy *auth.Policy
+}
+
+// newApp загружает конфигурацию и инициализирует логгер, подключение к Postgres и сервис.
+func newApp(ctx context.Context) (*app, error) {
 	// Загрузка конфигурации
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		log.Fatalf("Ошибка загрузки конфигурации: %v", err)
+		return nil, fmt.Errorf("ошибка загрузки конфигурации: %w", err)
 	}
 
 	// Инициализация логгера
 	logr, err := logger.NewLogger(cfg)
 	if err != nil {
-		log.Fatalf("Ошибка инициализации логгера: %v", err)
+		return nil, fmt.Errorf("ошибка инициализации логгера: %w", err)
 	}
-	defer logr.Sync()
-	logr.Info("Сервис запущен")
 
 	// Подключение к Postgres
-	ctx := context.Background()
-	db, err := pg.NewPostgres(ctx, &cfg.Database)
+	db, err := pg.NewPostgres(ctx, &cfg.Database, logr.Logger)
 	if err != nil {
-		logr.Fatal("Ошибка подключения к Postgres", logger.ErrorKV("error", err))
+		return nil, fmt.Errorf("ошибка подключения к Postgres: %w", err)
 	}
-	defer db.Close()
 
 	// Инициализация репозитория
 	repo, err := postgres.NewPersonRepository(db.Pool)
 	if err != nil {
-		logr.Fatal("Ошибка инициализации репозитория", logger.ErrorKV("error", err))
+		db.Close()
+		return nil, fmt.Errorf("ошибка инициализации репозитория: %w", err)
+	}
+
+	// Инициализация политики доступа
+	policy, err := auth.NewPolicy(cfg.Auth.Roles, logr.Logger)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка настройки ролей: %w", err)
 	}
 
 	// Инициализация сервиса
-	svc := service.NewPersonService(repo, logr.Logger, &cfg.APIs)
+	svc := service.NewPersonService(repo, logr.Logger, &cfg.APIs, &cfg.Duplicates, &cfg.Enrichment, policy)
+
+	return &app{cfg: cfg, logr: logr, db: db, repo: repo, svc: svc, policy: policy}, nil
+}
+
+// Close освобождает ресурсы приложения.
+func (a *app) Close() {
+	a.db.Close()
+	a.logr.Sync()
+}
+
+// runServe выполняет подкоманду serve.
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Использование: person-service serve")
+	}
+	flags.Parse(args)
+	serve()
+	return nil
+}
+
+// serve запускает HTTP-сервер и ожидает сигнала завершения.
+func serve() {
+	a, err := newApp(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer a.Close()
+	cfg, logr, svc := a.cfg, a.logr, a.svc
+	logr.Info("Сервис запущен")
+
+	// Проверка схемы базы данных; при database.auto_migrate недостающие миграции применяются
+	if err := migrateOnStart(context.Background(), a); err != nil {
+		logr.Fatal("Ошибка проверки схемы базы данных", logger.ErrorKV("error", err))
+	}
+
+	// Инициализация трассировки
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing)
+	if err != nil {
+		logr.Fatal("Ошибка инициализации трассировки", logger.ErrorKV("error", err))
+	}
+
+	// Инициализация хранилища ключей идемпотентности
+	idempotencyRepo, err := postgres.NewIdempotencyRepository(a.db.Pool)
+	if err != nil {
+		logr.Fatal("Ошибка инициализации репозитория", logger.ErrorKV("error", err))
+	}
+	idempotencySvc := service.NewIdempotencyService(idempotencyRepo, logr.Logger, cfg.Idempotency.TTL, cfg.Idempotency.LockTimeout)
+
+	// Инициализация аутентификации
+	var authenticator *auth.Authenticator
+	if cfg.Auth.Disabled {
+		logr.Warn("Аутентификация отключена: API доступен без учётных данных")
+	} else {
+		apiKeyRepo, err := postgres.NewAPIKeyRepository(a.db.Pool)
+		if err != nil {
+			logr.Fatal("Ошибка инициализации репозитория", logger.ErrorKV("error", err))
+		}
+		authenticator, err = auth.NewAuthenticator(context.Background(), &cfg.Auth, apiKeyRepo, logr.Logger)
+		if err != nil {
+			logr.Fatal("Ошибка инициализации аутентификации", logger.ErrorKV("error", err))
+		}
+	}
+
+	// Метрики пула соединений
+	if err := metrics.RegisterPool(a.db.Pool); err != nil {
+		logr.Fatal("Ошибка регистрации метрик", logger.ErrorKV("error", err))
+	}
+
+	// Инициализация ограничения частоты запросов
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		var store ratelimit.Store = ratelimit.NewMemoryStore()
+		if cfg.RateLimit.Backend == config.RateLimitPostgres {
+			store, err = postgres.NewRateLimitRepository(a.db.Pool)
+			if err != nil {
+				logr.Fatal("Ошибка инициализации репозитория", logger.ErrorKV("error", err))
+			}
+		}
+		limiter = ratelimit.NewLimiter(&cfg.RateLimit, store, logr.Logger)
+	}
+
+	// Инициализация проверок готовности
+	schemaRepo, err := postgres.NewSchemaRepository(a.db.Pool)
+	if err != nil {
+		logr.Fatal("Ошибка инициализации репозитория", logger.ErrorKV("error", err))
+	}
+	checker := health.NewChecker(cfg.Health.Timeout)
+	checker.Add("postgres", health.Ping(a.db.Pool))
+	checker.Add("migrations", health.Schema(schemaRepo, migrations.Latest()))
+	if cfg.Health.CheckEnrichment {
+		client := &http.Client{}
+		checker.AddOptional("agify", health.Reachable(client, cfg.APIs.Agify))
+		checker.AddOptional("genderize", health.Reachable(client, cfg.APIs.Genderize))
+		checker.AddOptional("nationalize", health.Reachable(client, cfg.APIs.Nationalize))
+	}
 
 	// Инициализация HTTP-сервера
-	server := api.NewServer(cfg, svc, logr.Logger)
+	server := api.NewServer(cfg, svc, idempotencySvc, authenticator, a.policy, limiter, checker, logr.Logger)
 
 	// Добавление Swagger UI
 	server.Router.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:8081/swagger/doc.json"),
 	))
 
-	// Запуск сервера в отдельной горутине
+	// Инициализация gRPC-сервера
+	grpcServer := grpcapi.NewServer(cfg, svc, authenticator, a.policy, limiter, logr.Logger)
+
+	// Запуск фоновой очистки удалённых записей
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	if cfg.Purge.Retention > 0 {
+		go svc.RunPurge(purgeCtx, cfg.Purge.Interval, cfg.Purge.Retention)
+	}
+	go idempotencySvc.RunCleanup(purgeCtx, cfg.Idempotency.CleanupInterval)
+	if limiter != nil {
+		go limiter.RunCleanup(purgeCtx, time.Minute)
+	}
+
+	// Запуск серверов в отдельных горутинах
 	go func() {
 		if err := server.Start(); err != nil {
 			logr.Fatal("Ошибка запуска сервера", logger.ErrorKV("error", err))
 		}
 	}()
+	go func() {
+		if err := grpcServer.Start(); err != nil {
+			logr.Fatal("Ошибка запуска gRPC-сервера", logger.ErrorKV("error", err))
+		}
+	}()
 
 	// Ожидание сигнала для завершения
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 	logr.Info("Инициируется graceful shutdown")
+	stopPurge()
+
+	// Снятие готовности до остановки серверов, чтобы балансировщик перестал направлять запросы
+	checker.Drain()
+	grpcServer.Drain()
+	if cfg.Health.DrainDelay > 0 {
+		logr.Info("Ожидание снятия трафика", logger.InfoKV("delay", cfg.Health.DrainDelay.String()))
+		time.Sleep(cfg.Health.DrainDelay)
+	}
 
 	// Создание контекста с таймаутом для graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Выполнение graceful shutdown
+	// Выполнение graceful shutdown обоих серверов в пределах общего таймаута
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := grpcServer.Shutdown(ctx); err != nil {
+			logr.Error("Ошибка graceful shutdown gRPC-сервера", logger.ErrorKV("error", err))
+		}
+	}()
 	if err := server.Shutdown(ctx); err != nil {
 		logr.Fatal("Ошибка graceful shutdown", logger.ErrorKV("error", err))
 	}
+	wg.Wait()
+	if err := shutdownTracing(ctx); err != nil {
+		logr.Error("Ошибка отправки трассировки", logger.ErrorKV("error", err))
+	}
 	logr.Info("Сервис успешно остановлен")
 }
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"person-service/pkg/logger"
+	pg "person-service/pkg/postgres"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+// runMigrate выполняет подкоманду migrate: применяет или откатывает встроенные миграции
+// и выводит состояние схемы.
+func runMigrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Использование: person-service migrate up|down|status|version")
+		fmt.Fprintln(flags.Output(), "  up       применить все неприменённые миграции")
+		fmt.Fprintln(flags.Output(), "  down     откатить последнюю применённую миграцию")
+		fmt.Fprintln(flags.Output(), "  status   показать состояние всех миграций")
+		fmt.Fprintln(flags.Output(), "  version  показать версию схемы и последней встроенной миграции")
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("не указана команда миграции")
+	}
+	command := flags.Arg(0)
+	switch command {
+	case "up", "down", "status", "version":
+	default:
+		flags.Usage()
+		return fmt.Errorf("неизвестная команда миграции: %s", command)
+	}
+
+	ctx := context.Background()
+	a, err := newApp(ctx)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+	migrator, err := pg.NewMigrator(a.db)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	switch command {
+	case "up":
+		results, err := migrator.Up(ctx)
+		printResults(results)
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Println("Схема актуальна, миграции не требуются")
+		}
+	case "down":
+		result, err := migrator.Down(ctx)
+		if errors.Is(err, goose.ErrNoNextVersion) {
+			fmt.Println("Нет применённых миграций")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		printResults([]*goose.MigrationResult{result})
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ПРИМЕНЕНА\tМИГРАЦИЯ")
+		for _, s := range status {
+			appliedAt := "ожидает"
+			if s.State == goose.StateApplied {
+				appliedAt = s.AppliedAt.Format(time.DateTime)
+			}
+			fmt.Fprintf(w, "%s\t%s\n", appliedAt, s.Source.Path)
+		}
+		return w.Flush()
+	case "version":
+		current, latest, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Версия схемы: %d\nПоследняя миграция: %d\n", current, latest)
+	}
+	return nil
+}
+
+// printResults выводит результаты применения или отката миграций.
+func printResults(results []*goose.MigrationResult) {
+	for _, result := range results {
+		fmt.Println(result)
+	}
+}
+
+// migrateOnStart применяет недостающие миграции, если включён database.auto_migrate,
+// и возвращает ошибку, если схема старше версии, на которую рассчитан код.
+// Одновременно запущенные экземпляры применяют миграции по очереди под advisory lock.
+func migrateOnStart(ctx context.Context, a *app) error {
+	migrator, err := pg.NewMigrator(a.db)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	if a.cfg.Database.AutoMigrate {
+		results, err := migrator.Up(ctx)
+		for _, result := range results {
+			a.logr.Info("Миграция применена", logger.InfoKV("migration", result.Source.Path),
+				logger.InfoKV("duration", result.Duration.String()))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return migrator.Check(ctx)
+}
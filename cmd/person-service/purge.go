@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"person-service/internal/audit"
+	"time"
+)
+
+// runPurgeDeleted выполняет подкоманду purge-deleted: физически удаляет записи всех арендаторов,
+// помеченные удалёнными дольше retention назад.
+func runPurgeDeleted(args []string) error {
+	flags := flag.NewFlagSet("purge-deleted", flag.ExitOnError)
+	retention := flags.Duration("retention", 0, "срок хранения удалённых записей, например 720h; по умолчанию purge.retention")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Использование: person-service purge-deleted [флаги]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if *retention < 0 {
+		return fmt.Errorf("-retention не может быть отрицательным")
+	}
+
+	ctx := audit.WithMeta(context.Background(), audit.Meta{Source: audit.SourceCLI})
+	a, err := newApp(ctx)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	if *retention == 0 {
+		*retention = a.cfg.Purge.Retention
+	}
+	if *retention == 0 {
+		return fmt.Errorf("не задан срок хранения: укажите -retention или purge.retention")
+	}
+	purged, err := a.svc.PurgeDeleted(ctx, *retention)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Очищено записей: %d (удалены раньше %s)\n", purged, time.Now().Add(-*retention).Format(time.DateTime))
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"person-service/internal/audit"
+	"person-service/internal/models"
+	"person-service/internal/tenant"
+	"strconv"
+	"time"
+)
+
+// seedBatchSize — число записей, создаваемых в одной транзакции.
+const seedBatchSize = 1000
+
+// Данные для случайных записей; фамилии и отчества согласуются с полом.
+var (
+	seedMaleNames       = []string{"Иван", "Пётр", "Алексей", "Дмитрий", "Сергей", "Андрей", "Михаил", "Николай"}
+	seedFemaleNames     = []string{"Анна", "Мария", "Елена", "Ольга", "Наталья", "Татьяна", "Ирина", "Екатерина"}
+	seedSurnames        = []string{"Иванов", "Петров", "Смирнов", "Кузнецов", "Попов", "Соколов", "Лебедев", "Козлов"}
+	seedPatronymicBases = []string{"Иванов", "Петров", "Алексеев", "Дмитриев", "Сергеев", "Андреев", "Михайлов", "Николаев"}
+	seedNationalities   = []string{"RU", "UA", "BY", "KZ", "US", "DE"}
+)
+
+// runSeed выполняет подкоманду seed: создаёт N записей со случайными данными без обращения к API обогащения.
+func runSeed(args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	tenantID := flags.String("tenant", tenant.Default, "арендатор, которому принадлежат записи")
+	seed := flags.Uint64("seed", 0, "начальное значение генератора для воспроизводимых данных; 0 — случайное")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Использование: person-service seed [флаги] N")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("не указано число записей")
+	}
+	count, err := strconv.Atoi(flags.Arg(0))
+	if err != nil || count <= 0 {
+		return fmt.Errorf("число записей должно быть положительным целым: %s", flags.Arg(0))
+	}
+	if err := tenant.Validate(*tenantID); err != nil {
+		return err
+	}
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+	rng := rand.New(rand.NewPCG(*seed, *seed))
+
+	ctx := audit.WithMeta(context.Background(), audit.Meta{Source: audit.SourceCLI})
+	a, err := newApp(ctx)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	ctx = tenant.WithID(ctx, *tenantID)
+	for created := 0; created < count; {
+		persons := make([]*models.Person, min(seedBatchSize, count-created))
+		for i := range persons {
+			persons[i] = fakePerson(rng, time.Now())
+		}
+		itemErrs, err := a.repo.CreateBatch(ctx, persons, true)
+		if err != nil {
+			return errors.Join(append([]error{err}, itemErrs...)...)
+		}
+		created += len(persons)
+		fmt.Fprintf(flags.Output(), "Создано записей: %d из %d\n", created, count)
+	}
+	return nil
+}
+
+// fakePerson возвращает запись со случайными именем, возрастом, полом и национальностью.
+func fakePerson(rng *rand.Rand, createdAt time.Time) *models.Person {
+	gender := models.GenderMale
+	name := seedMaleNames[rng.IntN(len(seedMaleNames))]
+	surname := seedSurnames[rng.IntN(len(seedSurnames))]
+	patronymic := seedPatronymicBases[rng.IntN(len(seedPatronymicBases))]
+	if rng.IntN(2) == 1 {
+		gender = models.GenderFemale
+		name = seedFemaleNames[rng.IntN(len(seedFemaleNames))]
+		surname += "а"
+		patronymic += "на"
+	} else {
+		patronymic += "ич"
+	}
+	age := 18 + rng.IntN(73)
+	nationality := seedNationalities[rng.IntN(len(seedNationalities))]
+	return &models.Person{
+		Name:        name,
+		Surname:     &surname,
+		Patronymic:  &patronymic,
+		Age:         &age,
+		Gender:      &gender,
+		Nationality: &nationality,
+		CreatedAt:   createdAt,
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"person-service/pkg/logger"
+	"regexp"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// requestIDHeader — заголовок с идентификатором запроса.
+const requestIDHeader = "X-Request-ID"
+
+// unmatchedRoute — маршрут в журнале доступа для запросов, не совпавших ни с одним маршрутом.
+const unmatchedRoute = "unmatched"
+
+// validRequestID ограничивает идентификаторы, принимаемые от клиента, чтобы они безопасно попадали в логи и историю.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:/+=-]{1,128}$`)
+
+// probeRoutes — маршруты проверок и метрик, которые вызываются периодически и логируются на уровне Debug.
+var probeRoutes = map[string]bool{"/healthz": true, "/readyz": true, "/metrics": true}
+
+// requestID берёт идентификатор запроса из X-Request-ID или создаёт новый, сохраняет его в контексте
+// (middleware.GetReqID) и возвращает клиенту в X-Request-ID.
+func requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, id)
+		ctx := context.WithValue(r.Context(), middleware.RequestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// accessEntry накапливает сведения для строки журнала доступа, которые становятся известны во внутренних middleware.
+type accessEntry struct {
+	principal string
+}
+
+type accessEntryKey struct{}
+
+// setAccessPrincipal записывает клиента запроса в строку журнала доступа.
+func setAccessPrincipal(ctx context.Context, subject string) {
+	if entry, ok := ctx.Value(accessEntryKey{}).(*accessEntry); ok {
+		entry.principal = subject
+	}
+}
+
+// accessLog сохраняет в контексте логгер запроса с полем request_id, который используют обработчики,
+// PersonService и репозитории, и после ответа пишет строку журнала доступа.
+func accessLog(log *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			entry := &accessEntry{}
+			ctx := logger.WithContext(r.Context(), log.With(zap.String("request_id", middleware.GetReqID(r.Context()))))
+			ctx = context.WithValue(ctx, accessEntryKey{}, entry)
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			route := unmatchedRoute
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			fields := []zap.Field{
+				zap.String("method", r.Method),
+				zap.String("route", route),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", status),
+				zap.Int("bytes", ww.BytesWritten()),
+				zap.Duration("duration", time.Since(start)),
+			}
+			if entry.principal != "" {
+				fields = append(fields, zap.String("principal", entry.principal))
+			}
+
+			l := logger.FromContext(ctx, log)
+			switch {
+			case probeRoutes[route]:
+				l.Debug("HTTP-запрос", fields...)
+			case status >= http.StatusInternalServerError:
+				l.Warn("HTTP-запрос", fields...)
+			default:
+				l.Info("HTTP-запрос", fields...)
+			}
+		})
+	}
+}
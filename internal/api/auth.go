@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"person-service/internal/audit"
+	"person-service/internal/auth"
+	"person-service/pkg/logger"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// apiKeyHeader — заголовок с ключом API.
+const apiKeyHeader = "X-API-Key"
+
+// authenticate пропускает только запросы с действительным JWT в Authorization: Bearer или ключом в X-API-Key.
+// Клиент сохраняется в контексте запроса, записывается в историю изменений как автор и в журнал доступа.
+func authenticate(authenticator *auth.Authenticator, log *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var principal *auth.Principal
+			var err error
+			if token, ok := bearerToken(r); ok {
+				principal, err = authenticator.AuthenticateToken(r.Context(), token)
+			} else if key := r.Header.Get(apiKeyHeader); key != "" {
+				principal, err = authenticator.AuthenticateAPIKey(r.Context(), key)
+			} else {
+				err = auth.ErrUnauthenticated
+			}
+
+			if errors.Is(err, auth.ErrUnauthenticated) {
+				logger.FromContext(r.Context(), log).Info("Запрос отклонён: не пройдена аутентификация",
+					zap.String("path", r.URL.Path), logger.ErrorKV("error", err))
+				w.Header().Set("WWW-Authenticate", `Bearer realm="person-service"`)
+				http.Error(w, fmt.Sprintf(`{"error": "%s"}`, auth.ErrUnauthenticated.Error()), http.StatusUnauthorized)
+				return
+			}
+			if err != nil {
+				logger.FromContext(r.Context(), log).Error("Ошибка аутентификации", logger.ErrorKV("error", err))
+				http.Error(w, `{"error": "Не удалось проверить учётные данные"}`, http.StatusInternalServerError)
+				return
+			}
+
+			setAccessPrincipal(r.Context(), principal.Subject)
+			meta := audit.FromContext(r.Context())
+			meta.Actor = principal.Subject
+			ctx := logger.WithContext(r.Context(), logger.FromContext(r.Context(), log).With(zap.String("principal", principal.Subject)))
+			ctx = audit.WithMeta(auth.WithPrincipal(ctx, principal), meta)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// bearerToken извлекает токен из заголовка Authorization со схемой Bearer.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// authorize пропускает запрос, только если у клиента есть право perm, иначе отвечает 403.
+func authorize(policy *auth.Policy, perm auth.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := policy.Authorize(r.Context(), perm); err != nil {
+				http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
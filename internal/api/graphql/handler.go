@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"person-service/internal/audit"
+	"person-service/internal/service"
+	"person-service/pkg/logger"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+)
+
+// maxQueryDepth ограничивает вложенность запроса.
+const maxQueryDepth = 10
+
+// maxRequestBytes ограничивает размер тела GraphQL-запроса.
+const maxRequestBytes = 1 << 20
+
+//go:embed schema.graphql
+var schemaSDL string
+
+//go:embed playground.html
+var playgroundHTML []byte
+
+// Handler обрабатывает GraphQL-запросы к сервису записей о людях.
+type Handler struct {
+	schema  *graphqlgo.Schema
+	service *service.PersonService
+	logger  *zap.Logger
+}
+
+// NewHandler создаёт обработчик GraphQL; схема разбирается один раз при создании.
+func NewHandler(service *service.PersonService, logger *zap.Logger) *Handler {
+	schema := graphqlgo.MustParseSchema(schemaSDL, &Resolver{service: service, logger: logger},
+		graphqlgo.UseStringDescriptions(),
+		graphqlgo.MaxDepth(maxQueryDepth),
+		// Поля всех записей страницы разрешаются параллельно, чтобы загрузчики собрали их в один пакет
+		graphqlgo.MaxParallelism(maxPageSize),
+	)
+	return &Handler{schema: schema, service: service, logger: logger}
+}
+
+// request представляет тело GraphQL-запроса.
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// ServeHTTP выполняет GraphQL-запрос из тела POST.
+// Ошибки выполнения возвращаются в поле errors ответа со статусом 200.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+		logger.FromContext(r.Context(), h.logger).Error("Ошибка декодирования GraphQL-запроса", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный JSON"}`, http.StatusBadRequest)
+		return
+	}
+	if req.Query == "" {
+		http.Error(w, `{"error": "Не указан query"}`, http.StatusBadRequest)
+		return
+	}
+
+	meta := audit.FromContext(r.Context())
+	meta.Source = audit.SourceGraphQL
+	ctx := audit.WithMeta(r.Context(), meta)
+	ctx = withLoaders(ctx, newLoaders(h.service))
+
+	response := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	if len(response.Errors) > 0 {
+		logger.FromContext(ctx, h.logger).Warn("GraphQL-запрос выполнен с ошибками",
+			zap.String("operation", req.OperationName), zap.Int("errors", len(response.Errors)))
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		logger.FromContext(ctx, h.logger).Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// Playground отдаёт страницу GraphiQL для отладки запросов.
+func (h *Handler) Playground(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(playgroundHTML)
+}
@@ -0,0 +1,124 @@
+package graphql
+
+import (
+	"context"
+	"person-service/internal/models"
+	"person-service/internal/repository"
+	"person-service/internal/service"
+	"time"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// loaderWait задаёт, сколько загрузчик ждёт ключи от параллельных резолверов перед запросом к базе.
+const loaderWait = 2 * time.Millisecond
+
+// personKey идентифицирует запись для загрузчика записей.
+type personKey struct {
+	id             int
+	includeDeleted bool
+}
+
+// loaders объединяет запросы резолверов одного GraphQL-запроса в пакетные запросы к базе, чтобы избежать N+1.
+type loaders struct {
+	person     *dataloader.Loader[personKey, *models.Person]
+	history    *dataloader.Loader[int, []*models.HistoryEntry]
+	enrichment *dataloader.Loader[int, *models.HistoryEntry]
+}
+
+type loadersKey struct{}
+
+// newLoaders создаёт загрузчики на время одного запроса; их кэш не переживает запрос.
+func newLoaders(svc *service.PersonService) *loaders {
+	return &loaders{
+		person: dataloader.NewBatchedLoader(
+			personBatch(svc),
+			dataloader.WithWait[personKey, *models.Person](loaderWait),
+		),
+		history: dataloader.NewBatchedLoader(
+			historyBatch(svc),
+			dataloader.WithWait[int, []*models.HistoryEntry](loaderWait),
+		),
+		enrichment: dataloader.NewBatchedLoader(
+			enrichmentBatch(svc),
+			dataloader.WithWait[int, *models.HistoryEntry](loaderWait),
+		),
+	}
+}
+
+// withLoaders возвращает контекст с загрузчиками.
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// loadersFrom возвращает загрузчики текущего запроса.
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// forget убирает из кэша загрузчиков данные изменённой записи.
+func (l *loaders) forget(ctx context.Context, id int) {
+	l.person.Clear(ctx, personKey{id: id})
+	l.person.Clear(ctx, personKey{id: id, includeDeleted: true})
+	l.history.Clear(ctx, id)
+	l.enrichment.Clear(ctx, id)
+}
+
+// personBatch загружает записи одним запросом на каждое значение includeDeleted.
+func personBatch(svc *service.PersonService) dataloader.BatchFunc[personKey, *models.Person] {
+	return func(ctx context.Context, keys []personKey) []*dataloader.Result[*models.Person] {
+		ids := map[bool][]int{}
+		for _, key := range keys {
+			ids[key.includeDeleted] = append(ids[key.includeDeleted], key.id)
+		}
+		found := map[personKey]*models.Person{}
+		errs := map[bool]error{}
+		for includeDeleted, batch := range ids {
+			persons, err := svc.GetByIDs(ctx, batch, includeDeleted)
+			if err != nil {
+				errs[includeDeleted] = err
+				continue
+			}
+			for id, person := range persons {
+				found[personKey{id: id, includeDeleted: includeDeleted}] = person
+			}
+		}
+
+		results := make([]*dataloader.Result[*models.Person], len(keys))
+		for i, key := range keys {
+			switch person, ok := found[key]; {
+			case errs[key.includeDeleted] != nil:
+				results[i] = &dataloader.Result[*models.Person]{Error: errs[key.includeDeleted]}
+			case !ok:
+				results[i] = &dataloader.Result[*models.Person]{Error: &repository.NotFoundError{ID: key.id}}
+			default:
+				results[i] = &dataloader.Result[*models.Person]{Data: person}
+			}
+		}
+		return results
+	}
+}
+
+// historyBatch загружает последние изменения сразу для всех запрошенных записей.
+func historyBatch(svc *service.PersonService) dataloader.BatchFunc[int, []*models.HistoryEntry] {
+	return func(ctx context.Context, ids []int) []*dataloader.Result[[]*models.HistoryEntry] {
+		results := make([]*dataloader.Result[[]*models.HistoryEntry], len(ids))
+		history, err := svc.RecentHistory(ctx, ids)
+		for i, id := range ids {
+			results[i] = &dataloader.Result[[]*models.HistoryEntry]{Data: history[id], Error: err}
+		}
+		return results
+	}
+}
+
+// enrichmentBatch загружает последнее обогащение сразу для всех запрошенных записей.
+func enrichmentBatch(svc *service.PersonService) dataloader.BatchFunc[int, *models.HistoryEntry] {
+	return func(ctx context.Context, ids []int) []*dataloader.Result[*models.HistoryEntry] {
+		results := make([]*dataloader.Result[*models.HistoryEntry], len(ids))
+		enrichment, err := svc.LatestEnrichment(ctx, ids)
+		for i, id := range ids {
+			results[i] = &dataloader.Result[*models.HistoryEntry]{Data: enrichment[id], Error: err}
+		}
+		return results
+	}
+}
@@ -0,0 +1,234 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"person-service/internal/models"
+	"person-service/internal/service"
+	"strconv"
+	"time"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+// personResolver разрешает поля типа Person.
+type personResolver struct {
+	person *models.Person
+}
+
+func (r *personResolver) ID() graphqlgo.ID {
+	return graphqlgo.ID(strconv.Itoa(r.person.ID))
+}
+
+func (r *personResolver) Name() string {
+	return r.person.Name
+}
+
+func (r *personResolver) Surname() *string {
+	return r.person.Surname
+}
+
+func (r *personResolver) Patronymic() *string {
+	return r.person.Patronymic
+}
+
+func (r *personResolver) Age() *int32 {
+	if r.person.Age == nil {
+		return nil
+	}
+	age := int32(*r.person.Age)
+	return &age
+}
+
+func (r *personResolver) Gender() *string {
+	if r.person.Gender == nil {
+		return nil
+	}
+	gender := string(*r.person.Gender)
+	return &gender
+}
+
+func (r *personResolver) Nationality() *string {
+	return r.person.Nationality
+}
+
+func (r *personResolver) CreatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.person.CreatedAt}
+}
+
+func (r *personResolver) UpdatedAt() *graphqlgo.Time {
+	return optionalTime(r.person.UpdatedAt)
+}
+
+func (r *personResolver) Version() int32 {
+	return int32(r.person.Version)
+}
+
+func (r *personResolver) DeletedAt() *graphqlgo.Time {
+	return optionalTime(r.person.DeletedAt)
+}
+
+// History возвращает последние изменения записи через загрузчик, общий для всех записей запроса.
+func (r *personResolver) History(ctx context.Context) ([]*historyResolver, error) {
+	entries, err := loadersFrom(ctx).history.Load(ctx, r.person.ID)()
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*historyResolver, len(entries))
+	for i, entry := range entries {
+		resolvers[i] = &historyResolver{entry: entry}
+	}
+	return resolvers, nil
+}
+
+// Enrichment возвращает последнее обогащение записи через загрузчик, общий для всех записей запроса.
+func (r *personResolver) Enrichment(ctx context.Context) (*historyResolver, error) {
+	entry, err := loadersFrom(ctx).enrichment.Load(ctx, r.person.ID)()
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	return &historyResolver{entry: entry}, nil
+}
+
+// optionalTime преобразует необязательное время в значение GraphQL.
+func optionalTime(t *time.Time) *graphqlgo.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphqlgo.Time{Time: *t}
+}
+
+// historyResolver разрешает поля типа HistoryEntry.
+type historyResolver struct {
+	entry *models.HistoryEntry
+}
+
+func (r *historyResolver) ID() graphqlgo.ID {
+	return graphqlgo.ID(strconv.FormatInt(r.entry.ID, 10))
+}
+
+func (r *historyResolver) PersonID() graphqlgo.ID {
+	return graphqlgo.ID(strconv.Itoa(r.entry.PersonID))
+}
+
+func (r *historyResolver) Action() string {
+	return string(r.entry.Action)
+}
+
+func (r *historyResolver) Actor() *string {
+	return r.entry.Actor
+}
+
+func (r *historyResolver) RequestID() *string {
+	return r.entry.RequestID
+}
+
+func (r *historyResolver) Source() string {
+	return r.entry.Source
+}
+
+func (r *historyResolver) Before() *string {
+	return rawJSON(r.entry.Before)
+}
+
+func (r *historyResolver) After() *string {
+	return rawJSON(r.entry.After)
+}
+
+func (r *historyResolver) Version() int32 {
+	return int32(r.entry.Version)
+}
+
+func (r *historyResolver) ChangedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.entry.ChangedAt}
+}
+
+// rawJSON возвращает JSON-объект строкой или nil, если значения нет.
+func rawJSON(raw json.RawMessage) *string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	value := string(raw)
+	return &value
+}
+
+// personConnection разрешает поля типа PersonConnection.
+type personConnection struct {
+	service *service.PersonService
+	filters map[string]string
+	persons []*models.Person
+	offset  int
+	hasNext bool
+}
+
+// personEdge разрешает поля типа PersonEdge.
+type personEdge struct {
+	cursor string
+	node   *personResolver
+}
+
+func (e *personEdge) Cursor() string {
+	return e.cursor
+}
+
+func (e *personEdge) Node() *personResolver {
+	return e.node
+}
+
+func (c *personConnection) Edges() []*personEdge {
+	edges := make([]*personEdge, len(c.persons))
+	for i, person := range c.persons {
+		edges[i] = &personEdge{cursor: encodeCursor(c.offset + i), node: &personResolver{person: person}}
+	}
+	return edges
+}
+
+func (c *personConnection) Nodes() []*personResolver {
+	nodes := make([]*personResolver, len(c.persons))
+	for i, person := range c.persons {
+		nodes[i] = &personResolver{person: person}
+	}
+	return nodes
+}
+
+func (c *personConnection) PageInfo() *pageInfo {
+	info := &pageInfo{hasNext: c.hasNext, hasPrevious: c.offset > 0}
+	if len(c.persons) > 0 {
+		start, end := encodeCursor(c.offset), encodeCursor(c.offset+len(c.persons)-1)
+		info.start, info.end = &start, &end
+	}
+	return info
+}
+
+// TotalCount подсчитывает записи под фильтром; запрос к базе выполняется, только если поле запрошено.
+func (c *personConnection) TotalCount(ctx context.Context) (int32, error) {
+	count, err := c.service.Count(ctx, c.filters)
+	if err != nil {
+		return 0, err
+	}
+	return int32(count), nil
+}
+
+// pageInfo разрешает поля типа PageInfo.
+type pageInfo struct {
+	hasNext     bool
+	hasPrevious bool
+	start       *string
+	end         *string
+}
+
+func (p *pageInfo) HasNextPage() bool {
+	return p.hasNext
+}
+
+func (p *pageInfo) HasPreviousPage() bool {
+	return p.hasPrevious
+}
+
+func (p *pageInfo) StartCursor() *string {
+	return p.start
+}
+
+func (p *pageInfo) EndCursor() *string {
+	return p.end
+}
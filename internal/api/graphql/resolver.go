@@ -0,0 +1,347 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"person-service/internal/auth"
+	"person-service/internal/models"
+	"person-service/internal/ratelimit"
+	"person-service/internal/repository"
+	"person-service/internal/service"
+	"person-service/pkg/logger"
+	"strconv"
+	"strings"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+)
+
+// maxPageSize ограничивает число записей на странице списка.
+const maxPageSize = 100
+
+// cursorPrefix отличает курсоры списка от произвольных строк.
+const cursorPrefix = "offset:"
+
+// Resolver — корневой резолвер схемы GraphQL.
+type Resolver struct {
+	service *service.PersonService
+	logger  *zap.Logger
+}
+
+// resolverError добавляет к ошибке код в extensions ответа GraphQL.
+type resolverError struct {
+	err        error
+	extensions map[string]interface{}
+}
+
+func (e *resolverError) Error() string {
+	return e.err.Error()
+}
+
+// Extensions возвращает extensions ошибки в ответе GraphQL.
+func (e *resolverError) Extensions() map[string]interface{} {
+	return e.extensions
+}
+
+// fail логирует ошибку операции и добавляет к ней код NOT_FOUND, DUPLICATE, FORBIDDEN или QUOTA_EXCEEDED, если он известен.
+func (r *Resolver) fail(ctx context.Context, operation string, err error) error {
+	logger.FromContext(ctx, r.logger).Error("Ошибка GraphQL-операции", zap.String("operation", operation), logger.ErrorKV("error", err))
+	var duplicateErr *service.DuplicateError
+	switch {
+	case repository.IsNotFound(err):
+		return &resolverError{err: err, extensions: map[string]interface{}{"code": "NOT_FOUND"}}
+	case errors.As(err, &duplicateErr):
+		return &resolverError{err: err, extensions: map[string]interface{}{"code": "DUPLICATE", "duplicates": duplicateErr.IDs}}
+	case errors.Is(err, auth.ErrForbidden):
+		return &resolverError{err: err, extensions: map[string]interface{}{"code": "FORBIDDEN"}}
+	case errors.Is(err, service.ErrEnrichmentQuotaExceeded):
+		return &resolverError{err: err, extensions: map[string]interface{}{"code": "QUOTA_EXCEEDED"}}
+	default:
+		return err
+	}
+}
+
+// rateLimited возвращает ошибку операции, отклонённой ограничением частоты, с кодом RATE_LIMITED
+// и временем ожидания в секундах в retryAfter.
+func (r *Resolver) rateLimited(ctx context.Context, operation string, result ratelimit.Result) error {
+	retryAfter := int(result.RetryAfter.Seconds())
+	logger.FromContext(ctx, r.logger).Info("GraphQL-операция отклонена: превышен лимит запросов", zap.String("operation", operation))
+	return &resolverError{
+		err:        fmt.Errorf("превышен лимит запросов, повторите через %d с", retryAfter),
+		extensions: map[string]interface{}{"code": "RATE_LIMITED", "retryAfter": retryAfter},
+	}
+}
+
+// badInput возвращает ошибку некорректных аргументов.
+func badInput(format string, args ...any) error {
+	return &resolverError{err: fmt.Errorf(format, args...), extensions: map[string]interface{}{"code": "BAD_USER_INPUT"}}
+}
+
+// parseID преобразует ID GraphQL в ID записи.
+func parseID(id graphqlgo.ID) (int, error) {
+	value, err := strconv.Atoi(string(id))
+	if err != nil || value <= 0 {
+		return 0, badInput("некорректный ID: %s", id)
+	}
+	return value, nil
+}
+
+// Person возвращает запись по ID или null, если записи нет.
+func (r *Resolver) Person(ctx context.Context, args struct {
+	ID             graphqlgo.ID
+	IncludeDeleted bool
+}) (*personResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	key := personKey{id: id, includeDeleted: args.IncludeDeleted}
+	person, err := loadersFrom(ctx).person.Load(ctx, key)()
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, r.fail(ctx, "person", err)
+	}
+	return &personResolver{person: person}, nil
+}
+
+// personFilter соответствует входному типу PersonFilter.
+type personFilter struct {
+	Name           *string
+	Surname        *string
+	Patronymic     *string
+	Age            *int32
+	Gender         *string
+	Nationality    *string
+	IncludeDeleted *bool
+}
+
+// personSort соответствует входному типу PersonSort.
+type personSort struct {
+	Field     string
+	Direction string
+}
+
+// pageInput соответствует входному типу PageInput.
+type pageInput struct {
+	First int32
+	After *string
+}
+
+// listFilters преобразует фильтр и сортировку в фильтры списка сервиса.
+func listFilters(filter *personFilter, sort *[]personSort) map[string]string {
+	filters := map[string]string{}
+	if filter != nil {
+		for key, value := range map[string]*string{
+			"name":        filter.Name,
+			"surname":     filter.Surname,
+			"patronymic":  filter.Patronymic,
+			"gender":      filter.Gender,
+			"nationality": filter.Nationality,
+		} {
+			if value != nil && *value != "" {
+				filters[key] = *value
+			}
+		}
+		if filter.Age != nil {
+			filters["age"] = strconv.Itoa(int(*filter.Age))
+		}
+		if filter.IncludeDeleted != nil && *filter.IncludeDeleted {
+			filters["include_deleted"] = "true"
+		}
+	}
+	if sort != nil && len(*sort) > 0 {
+		terms := make([]string, len(*sort))
+		for i, s := range *sort {
+			terms[i] = strings.ToLower(s.Field)
+			if s.Direction == "DESC" {
+				terms[i] = "-" + terms[i]
+			}
+		}
+		filters["sort"] = strings.Join(terms, ",")
+	}
+	return filters
+}
+
+// encodeCursor возвращает курсор записи с позицией position в списке.
+func encodeCursor(position int) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(position)))
+}
+
+// decodeCursor возвращает позицию записи по курсору.
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, badInput("некорректный курсор: %s", cursor)
+	}
+	position, err := strconv.Atoi(strings.TrimPrefix(string(raw), cursorPrefix))
+	if err != nil || position < 0 {
+		return 0, badInput("некорректный курсор: %s", cursor)
+	}
+	return position, nil
+}
+
+// Persons возвращает страницу записей под фильтром.
+func (r *Resolver) Persons(ctx context.Context, args struct {
+	Filter *personFilter
+	Sort   *[]personSort
+	Page   *pageInput
+}) (*personConnection, error) {
+	first, offset := 10, 0
+	if args.Page != nil {
+		first = int(args.Page.First)
+		if args.Page.After != nil {
+			position, err := decodeCursor(*args.Page.After)
+			if err != nil {
+				return nil, err
+			}
+			offset = position + 1
+		}
+	}
+	if first < 0 || first > maxPageSize {
+		return nil, badInput("page.first должен быть от 0 до %d", maxPageSize)
+	}
+
+	filters := listFilters(args.Filter, args.Sort)
+	// Запрашиваем на одну запись больше, чтобы узнать, есть ли следующая страница
+	persons, err := r.service.List(ctx, first+1, offset, filters, nil)
+	if err != nil {
+		return nil, r.fail(ctx, "persons", err)
+	}
+	hasNext := len(persons) > first
+	if hasNext {
+		persons = persons[:first]
+	}
+
+	// Найденные записи кладём в кэш загрузчика, чтобы запрос person(id) в том же документе не шёл в базу
+	personLoader := loadersFrom(ctx).person
+	for _, person := range persons {
+		personLoader.Prime(ctx, personKey{id: person.ID, includeDeleted: filters["include_deleted"] == "true"}, person)
+	}
+
+	return &personConnection{
+		service: r.service,
+		filters: filters,
+		persons: persons,
+		offset:  offset,
+		hasNext: hasNext,
+	}, nil
+}
+
+// updateInput соответствует входному типу UpdatePersonInput.
+type updateInput struct {
+	Name        string
+	Surname     *string
+	Patronymic  *string
+	Age         *int32
+	Gender      *string
+	Nationality *string
+}
+
+// patchInput соответствует входному типу PatchPersonInput.
+type patchInput struct {
+	Name        *string
+	Surname     *string
+	Patronymic  *string
+	Age         *int32
+	Gender      *string
+	Nationality *string
+}
+
+// toInt преобразует необязательное значение Int из GraphQL.
+func toInt(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	value := int(*v)
+	return &value
+}
+
+// toGender преобразует необязательное значение Gender из GraphQL.
+func toGender(v *string) *models.GenderType {
+	if v == nil {
+		return nil
+	}
+	gender := models.GenderType(*v)
+	return &gender
+}
+
+// CreatePerson создаёт запись с обогащением.
+func (r *Resolver) CreatePerson(ctx context.Context, args struct{ Input models.PersonInput }) (*personResolver, error) {
+	// Создание обращается к платным API обогащения, поэтому каждая мутация, в том числе под псевдонимом,
+	// расходует токен класса create, как POST /api/v1/persons
+	if result := ratelimit.AllowContext(ctx, ratelimit.ClassCreate); !result.Allowed {
+		return nil, r.rateLimited(ctx, "createPerson", result)
+	}
+	person, err := r.service.Create(ctx, &args.Input)
+	if err != nil {
+		return nil, r.fail(ctx, "createPerson", err)
+	}
+	return &personResolver{person: person}, nil
+}
+
+// UpdatePerson полностью заменяет запись.
+func (r *Resolver) UpdatePerson(ctx context.Context, args struct {
+	ID    graphqlgo.ID
+	Input updateInput
+}) (*personResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	input := &models.PersonReplace{
+		Name:        args.Input.Name,
+		Surname:     args.Input.Surname,
+		Patronymic:  args.Input.Patronymic,
+		Age:         toInt(args.Input.Age),
+		Gender:      toGender(args.Input.Gender),
+		Nationality: args.Input.Nationality,
+	}
+	person, _, err := r.service.Replace(ctx, id, input, false)
+	if err != nil {
+		return nil, r.fail(ctx, "updatePerson", err)
+	}
+	loadersFrom(ctx).forget(ctx, id)
+	return &personResolver{person: person}, nil
+}
+
+// PatchPerson частично обновляет запись.
+func (r *Resolver) PatchPerson(ctx context.Context, args struct {
+	ID    graphqlgo.ID
+	Input patchInput
+}) (*personResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	update := &models.PersonUpdate{
+		Name:        args.Input.Name,
+		Surname:     args.Input.Surname,
+		Patronymic:  args.Input.Patronymic,
+		Age:         toInt(args.Input.Age),
+		Gender:      toGender(args.Input.Gender),
+		Nationality: args.Input.Nationality,
+	}
+	person, err := r.service.Patch(ctx, id, update)
+	if err != nil {
+		return nil, r.fail(ctx, "patchPerson", err)
+	}
+	loadersFrom(ctx).forget(ctx, id)
+	return &personResolver{person: person}, nil
+}
+
+// DeletePerson помечает запись удалённой.
+func (r *Resolver) DeletePerson(ctx context.Context, args struct{ ID graphqlgo.ID }) (bool, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	if err := r.service.Delete(ctx, id); err != nil {
+		return false, r.fail(ctx, "deletePerson", err)
+	}
+	loadersFrom(ctx).forget(ctx, id)
+	return true, nil
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"person-service/internal/service"
+	"person-service/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// idempotencyHeader — заголовок, по которому повтор запроса распознаётся как тот же запрос.
+const idempotencyHeader = "Idempotency-Key"
+
+// replayedHeaders перечисляет заголовки ответа, которые сохраняются для повтора.
+var replayedHeaders = []string{"Content-Type", "Location", "ETag", "Content-Disposition"}
+
+// idempotency выполняет запрос с заголовком Idempotency-Key не более одного раза.
+// Повтор с тем же ключом и содержимым получает сохранённый ответ, с другим содержимым — 422.
+// Ответы 5xx не сохраняются, такой запрос можно повторить. Пока запрос выполняется, резервирование ключа
+// продлевается; ключ запроса, экземпляр которого упал, не сохранив ответ, освобождается через idempotency.lock_timeout.
+// Тело запроса читается в память для хэша, поэтому оно ограничено maxBytes; больший запрос получает 413.
+func idempotency(svc *service.IdempotencyService, maxBytes int64, log *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotencyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+			if err != nil {
+				logger.FromContext(r.Context(), log).Error("Ошибка чтения тела запроса", logger.ErrorKV("error", err))
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					http.Error(w, fmt.Sprintf(`{"error": "Тело запроса больше %d байт"}`, maxBytes), http.StatusRequestEntityTooLarge)
+					return
+				}
+				http.Error(w, `{"error": "Не удалось прочитать тело запроса"}`, http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			record, err := svc.Begin(r.Context(), key, requestHash(r, body))
+			if err != nil {
+				logger.FromContext(r.Context(), log).Error("Ошибка проверки Idempotency-Key", logger.ErrorKV("error", err))
+				status := http.StatusBadRequest
+				switch {
+				case errors.Is(err, service.ErrIdempotencyKeyReused):
+					status = http.StatusUnprocessableEntity
+				case errors.Is(err, service.ErrIdempotencyInProgress):
+					status = http.StatusConflict
+				}
+				http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), status)
+				return
+			}
+			if record.Completed() {
+				for name, value := range record.Headers {
+					w.Header().Set(name, value)
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(record.StatusCode)
+				w.Write(record.Body)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			completed := false
+			stop := svc.Hold(r.Context(), record)
+			// Ключ освобождается и при панике обработчика, чтобы запрос можно было повторить
+			defer func() {
+				stop()
+				if completed {
+					return
+				}
+				if err := svc.Release(context.WithoutCancel(r.Context()), record); err != nil {
+					logger.FromContext(r.Context(), log).Error("Ошибка освобождения Idempotency-Key", logger.ErrorKV("error", err))
+				}
+			}()
+			next.ServeHTTP(recorder, r)
+			stop()
+
+			if recorder.status >= http.StatusInternalServerError {
+				return
+			}
+			headers := map[string]string{}
+			for _, name := range replayedHeaders {
+				if value := w.Header().Get(name); value != "" {
+					headers[name] = value
+				}
+			}
+			if err := svc.Complete(context.WithoutCancel(r.Context()), record, recorder.status, headers, recorder.body.Bytes()); err != nil {
+				logger.FromContext(r.Context(), log).Error("Ошибка сохранения ответа для Idempotency-Key", logger.ErrorKV("error", err))
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// requestHash вычисляет хэш метода, пути, параметров и тела запроса.
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n%s\n", r.Method, r.URL.Path, r.URL.RawQuery)
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder передаёт ответ клиенту и сохраняет его статус и тело.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	if !rr.wroteHeader {
+		rr.status = status
+		rr.wroteHeader = true
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(data []byte) (int, error) {
+	rr.wroteHeader = true
+	rr.body.Write(data)
+	return rr.ResponseWriter.Write(data)
+}
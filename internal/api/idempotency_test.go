@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"person-service/internal/models"
+	"person-service/internal/service"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// memoryIdempotency хранит ключи идемпотентности в памяти.
+type memoryIdempotency struct {
+	mu      sync.Mutex
+	records map[string]*memoryIdempotencyRecord
+}
+
+type memoryIdempotencyRecord struct {
+	models.IdempotencyRecord
+	lockedUntil time.Time
+}
+
+func newMemoryIdempotency() *memoryIdempotency {
+	return &memoryIdempotency{records: map[string]*memoryIdempotencyRecord{}}
+}
+
+func (m *memoryIdempotency) Reserve(_ context.Context, key, requestHash string, now, lockedUntil, expiresAt time.Time) (*models.IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.records[key]; ok && existing.ExpiresAt.After(now) && (existing.Completed() || existing.lockedUntil.After(now)) {
+		record := existing.IdempotencyRecord
+		return &record, false, nil
+	}
+	m.records[key] = &memoryIdempotencyRecord{
+		IdempotencyRecord: models.IdempotencyRecord{Key: key, RequestHash: requestHash, CreatedAt: now, ExpiresAt: expiresAt},
+		lockedUntil:       lockedUntil,
+	}
+	return nil, true, nil
+}
+
+func (m *memoryIdempotency) Complete(_ context.Context, key string, reservedAt time.Time, statusCode int, headers map[string]string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.records[key]
+	if !ok || !record.CreatedAt.Equal(reservedAt) {
+		return fmt.Errorf("ключ %s не зарезервирован", key)
+	}
+	record.StatusCode, record.Headers, record.Body = statusCode, headers, body
+	return nil
+}
+
+func (m *memoryIdempotency) Extend(_ context.Context, key string, reservedAt, lockedUntil time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if record, ok := m.records[key]; ok && record.CreatedAt.Equal(reservedAt) && !record.Completed() {
+		record.lockedUntil = lockedUntil
+	}
+	return nil
+}
+
+func (m *memoryIdempotency) Release(_ context.Context, key string, reservedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if record, ok := m.records[key]; ok && record.CreatedAt.Equal(reservedAt) {
+		delete(m.records, key)
+	}
+	return nil
+}
+
+func (m *memoryIdempotency) PurgeExpired(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestIdempotencyBodyLimit(t *testing.T) {
+	svc := service.NewIdempotencyService(newMemoryIdempotency(), zap.NewNop(), time.Hour, time.Minute)
+	called := false
+	handler := idempotency(svc, 16, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/persons", strings.NewReader(`{"name": "Слишком длинное тело"}`))
+	r.Header.Set(idempotencyHeader, "body-limit")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("ожидался статус 413, получен %d: %s", w.Code, w.Body.String())
+	}
+	if called {
+		t.Fatal("обработчик вызван для запроса больше лимита")
+	}
+}
+
+func TestIdempotencyRetryWhileInFlight(t *testing.T) {
+	lockTimeout := 30 * time.Millisecond
+	svc := service.NewIdempotencyService(newMemoryIdempotency(), zap.NewNop(), time.Hour, lockTimeout)
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	var calls atomic.Int32
+	handler := idempotency(svc, 1<<20, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-finish
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 1}`)
+	}))
+	send := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/persons:batch", strings.NewReader(`[{"name": "Иван"}]`))
+		r.Header.Set(idempotencyHeader, "in-flight")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() { first <- send() }()
+	<-started
+
+	// Первый запрос выполняется дольше нескольких lock_timeout, но ключ остаётся за ним
+	time.Sleep(5 * lockTimeout)
+	if w := send(); w.Code != http.StatusConflict {
+		t.Fatalf("повтор во время выполнения: ожидался статус 409, получен %d: %s", w.Code, w.Body.String())
+	}
+
+	close(finish)
+	if w := <-first; w.Code != http.StatusCreated {
+		t.Fatalf("первый запрос: ожидался статус 201, получен %d", w.Code)
+	}
+	w := send()
+	if w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("повтор после выполнения: ожидался сохранённый ответ 201, получен %d", w.Code)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("обработчик выполнен %d раз", n)
+	}
+}
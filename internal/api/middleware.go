@@ -0,0 +1,19 @@
+package api
+
+import (
+	"net/http"
+	"person-service/internal/audit"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// auditMeta сохраняет в контексте запроса метаданные для истории изменений.
+func auditMeta(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := audit.WithMeta(r.Context(), audit.Meta{
+			RequestID: middleware.GetReqID(r.Context()),
+			Source:    audit.SourceAPI,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
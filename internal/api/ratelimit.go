@@ -0,0 +1,64 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"person-service/internal/ratelimit"
+	"person-service/pkg/logger"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// rateLimit ограничивает частоту запросов клиента для класса маршрутов class.
+// Ответ содержит заголовки RateLimit-*, а при превышении лимита — 429 с Retry-After.
+// Обработчик может расходовать токены других классов на операции внутри запроса через ratelimit.AllowContext.
+// Без limiter запросы не ограничиваются.
+func rateLimit(limiter *ratelimit.Limiter, class ratelimit.Class, trustProxy bool, log *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limiter == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := ratelimit.ClientKey(r.Context(), clientIP(r, trustProxy))
+			result := limiter.Allow(r.Context(), class, client)
+
+			header := w.Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(result.Rule.Burst))
+			header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(int(result.Reset.Seconds())))
+			header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d",
+				result.Rule.Requests, int(result.Rule.Period.Seconds()), result.Rule.Burst))
+			if !result.Allowed {
+				retryAfter := int(result.RetryAfter.Seconds())
+				logger.FromContext(r.Context(), log).Info("Запрос отклонён: превышен лимит запросов",
+					zap.String("client", client), zap.String("class", string(class)), zap.String("path", r.URL.Path))
+				header.Set("Retry-After", strconv.Itoa(retryAfter))
+				http.Error(w, fmt.Sprintf(`{"error": "Превышен лимит запросов, повторите через %d с"}`, retryAfter), http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ratelimit.WithScope(r.Context(), limiter, client)))
+		})
+	}
+}
+
+// clientIP возвращает IP-адрес клиента. Заголовкам X-Forwarded-For и X-Real-IP
+// можно доверять, только если сервис стоит за прокси, который их перезаписывает.
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+			return realIP
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
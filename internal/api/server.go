@@ -4,9 +4,15 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"person-service/internal/api/graphql"
 	v1 "person-service/internal/api/v1"
+	"person-service/internal/auth"
 	"person-service/internal/config"
+	"person-service/internal/health"
+	"person-service/internal/metrics"
+	"person-service/internal/ratelimit"
 	"person-service/internal/service"
+	"person-service/internal/tracing"
 	"person-service/pkg/logger"
 
 	"github.com/go-chi/chi/v5"
@@ -22,23 +28,73 @@ type Server struct {
 }
 
 // NewServer создаёт новый HTTP-сервер.
-func NewServer(cfg *config.Config, service *service.PersonService, logger *zap.Logger) *Server {
+// API и GraphQL требуют аутентификации через authenticator; nil допустим, только если аутентификация отключена в настройках.
+// Маршруты API проверяют права клиента по policy, операции GraphQL — в PersonService.
+// Данные запроса ограничены арендатором из учётных данных или заголовка X-Tenant-ID.
+// Частота запросов ограничивается через limiter; nil отключает ограничение.
+// Проверки /healthz и /readyz выполняет checker.
+func NewServer(cfg *config.Config, service *service.PersonService, idempotencyService *service.IdempotencyService, authenticator *auth.Authenticator, policy *auth.Policy, limiter *ratelimit.Limiter, checker *health.Checker, logger *zap.Logger) *Server {
 	r := chi.NewRouter()
 
 	// Middleware
+	r.Use(requestID)
+	r.Use(tracing.HTTPMiddleware)
+	r.Use(accessLog(logger))
+	r.Use(metrics.HTTPMiddleware)
 	r.Use(middleware.Recoverer)
+	r.Use(auditMeta)
+
+	// Аутентификация API и GraphQL; Swagger UI доступен без неё
+	authMiddleware := func(next http.Handler) http.Handler { return next }
+	if !cfg.Auth.Disabled {
+		authMiddleware = authenticate(authenticator, logger)
+	}
+
+	// Ограничение частоты запросов по классам маршрутов: создание с платным обогащением ограничено строже всего
+	limit := func(class ratelimit.Class) func(http.Handler) http.Handler {
+		return rateLimit(limiter, class, cfg.RateLimit.TrustProxy, logger)
+	}
+
+	// Метрики Prometheus доступны без аутентификации, как Swagger UI
+	r.Handle("/metrics", metrics.Handler())
+
+	// Проверки liveness и readiness для оркестратора и балансировщика
+	r.Get("/healthz", checker.Liveness)
+	r.Get("/readyz", checker.Readiness)
 
 	// API v1
-	handler := v1.NewHandler(service, logger)
+	handler := v1.NewHandler(&cfg.Server, service, logger)
 	r.Route("/api/v1", func(r chi.Router) {
-		r.Post("/persons", handler.CreatePerson)
-		r.Get("/persons", handler.ListPersons)
-		r.Get("/persons/{id}", handler.GetPerson)
-		r.Put("/persons/{id}", handler.UpdatePerson)
-		r.Patch("/persons/{id}", handler.PatchPerson)
-		r.Delete("/persons/{id}", handler.DeletePerson)
+		r.Use(authMiddleware)
+		r.Use(tenantScope(policy, logger))
+		can := func(perm auth.Permission, class ratelimit.Class) chi.Router {
+			return r.With(limit(class), authorize(policy, perm))
+		}
+		idempotent := idempotency(idempotencyService, cfg.Server.RequestMaxBytes, logger)
+		can(auth.PermWrite, ratelimit.ClassCreate).With(idempotent).Post("/persons", handler.CreatePerson)
+		can(auth.PermBulk, ratelimit.ClassBulk).With(idempotent).Post("/persons:batch", handler.CreatePersonsBatch)
+		can(auth.PermBulk, ratelimit.ClassBulk).Post("/persons:import", handler.ImportPersons)
+		can(auth.PermRead, ratelimit.ClassBulk).Get("/persons/export", handler.ExportPersons)
+		can(auth.PermRead, ratelimit.ClassRead).Get("/persons", handler.ListPersons)
+		can(auth.PermBulk, ratelimit.ClassBulk).With(idempotent).Patch("/persons", handler.BulkPatchPersons)
+		can(auth.PermBulk, ratelimit.ClassBulk).With(idempotent).Delete("/persons", handler.BulkDeletePersons)
+		can(auth.PermWrite, ratelimit.ClassWrite).With(authorize(policy, auth.PermDelete), idempotent).Post("/persons/merge", handler.MergePersons)
+		can(auth.PermRead, ratelimit.ClassRead).Get("/persons/{id}", handler.GetPerson)
+		can(auth.PermWrite, ratelimit.ClassWrite).Put("/persons/{id}", handler.UpdatePerson)
+		can(auth.PermWrite, ratelimit.ClassWrite).Patch("/persons/{id}", handler.PatchPerson)
+		can(auth.PermDelete, ratelimit.ClassWrite).Delete("/persons/{id}", handler.DeletePerson)
+		can(auth.PermDelete, ratelimit.ClassWrite).Post("/persons/{id}/restore", handler.RestorePerson)
+		can(auth.PermHistory, ratelimit.ClassRead).Get("/persons/{id}/history", handler.GetPersonHistory)
+		can(auth.PermRead, ratelimit.ClassRead).Get("/persons/{id}/duplicates", handler.GetPersonDuplicates)
 	})
 
+	// GraphQL; playground доступен только в режиме разработки
+	graphqlHandler := graphql.NewHandler(service, logger)
+	r.With(authMiddleware, tenantScope(policy, logger), limit(ratelimit.ClassWrite)).Post("/graphql", graphqlHandler.ServeHTTP)
+	if cfg.IsDev() {
+		r.Get("/graphql", graphqlHandler.Playground)
+	}
+
 	httpServer := &http.Server{
 		Addr:    fmt.Sprintf(":%s", cfg.Server.Port),
 		Handler: r,
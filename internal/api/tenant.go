@@ -0,0 +1,42 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"person-service/internal/auth"
+	"person-service/internal/tenant"
+	"person-service/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// tenantHeader — заголовок с арендатором запроса.
+const tenantHeader = "X-Tenant-ID"
+
+// tenantScope сохраняет в контексте арендатора запроса: из учётных данных или из заголовка X-Tenant-ID.
+// Заголовок учитывается только для учётных данных без привязки к арендатору с правом auth.PermAnyTenant,
+// остальные ограничены своим арендатором или tenant.Default; запрос другого арендатора получает 403.
+func tenantScope(policy *auth.Policy, log *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := auth.FromContext(r.Context())
+			bound := policy.BoundTenant(principal)
+
+			tenantID, err := tenant.Resolve(bound, r.Header.Get(tenantHeader))
+			if err != nil {
+				status := http.StatusBadRequest
+				if errors.Is(err, tenant.ErrMismatch) {
+					status = http.StatusForbidden
+					logger.FromContext(r.Context(), log).Warn("Доступ к арендатору запрещён",
+						zap.String("subject", principal.Subject),
+						zap.String("tenant", bound),
+						zap.String("requested", r.Header.Get(tenantHeader)))
+				}
+				http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), status)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), tenantID)))
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"person-service/internal/auth"
+	"person-service/internal/config"
+	"person-service/internal/health"
+	"person-service/internal/models"
+	"person-service/internal/repository/postgres"
+	"person-service/internal/service"
+	"person-service/internal/tenant"
+	pg "person-service/pkg/postgres"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+func TestTenantScope(t *testing.T) {
+	policy, err := auth.NewPolicy(map[string][]string{
+		"admin":    {string(auth.PermRead)},
+		"operator": {string(auth.PermRead), string(auth.PermAnyTenant)},
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		header    string
+		status    int
+		tenant    string
+	}{
+		{"привязанный к арендатору", &auth.Principal{Roles: []string{"admin"}, Tenant: "acme"}, "", http.StatusOK, "acme"},
+		{"привязанный к арендатору, чужой заголовок", &auth.Principal{Roles: []string{"admin"}, Tenant: "acme"}, "globex", http.StatusForbidden, ""},
+		{"без привязки и права any_tenant", &auth.Principal{Roles: []string{"admin"}}, "", http.StatusOK, tenant.Default},
+		{"без привязки и права any_tenant, заголовок", &auth.Principal{Roles: []string{"admin"}}, "globex", http.StatusForbidden, ""},
+		{"с правом any_tenant", &auth.Principal{Roles: []string{"operator"}}, "globex", http.StatusOK, "globex"},
+		{"с правом any_tenant, некорректный заголовок", &auth.Principal{Roles: []string{"operator"}}, "Globex!", http.StatusBadRequest, ""},
+		{"аутентификация отключена", nil, "globex", http.StatusOK, "globex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := tenantScope(policy, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = tenant.FromContext(r.Context())
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/persons", nil)
+			if tt.principal != nil {
+				r = r.WithContext(auth.WithPrincipal(r.Context(), tt.principal))
+			}
+			if tt.header != "" {
+				r.Header.Set(tenantHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("ожидался статус %d, получен %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if got != tt.tenant {
+				t.Fatalf("ожидался арендатор %q, получен %q", tt.tenant, got)
+			}
+		})
+	}
+}
+
+// testDSNEnv — переменная окружения с DSN базы данных для тестов; без неё тесты с Postgres пропускаются.
+const testDSNEnv = "PERSON_SERVICE_TEST_DSN"
+
+// testPool подключается к тестовой базе из PERSON_SERVICE_TEST_DSN и применяет к ней миграции.
+func testPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s не задан, тест с Postgres пропущен", testDSNEnv)
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("не удалось подключиться к тестовой базе: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	migrator, err := pg.NewMigrator(&pg.Pool{Pool: pool})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer migrator.Close()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func TestTenantIsolationHTTP(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	logger := zap.NewNop()
+	suffix := time.Now().UnixNano()
+	tenantA := fmt.Sprintf("isolation-a-%d", suffix)
+	tenantB := fmt.Sprintf("isolation-b-%d", suffix)
+
+	repo, err := postgres.NewPersonRepository(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idempotencyRepo, err := postgres.NewIdempotencyRepository(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiKeyRepo, err := postgres.NewAPIKeyRepository(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		Server: config.Server{BulkMaxRows: 100, RequestMaxBytes: 1 << 20},
+		Auth: config.Auth{APIKeys: []string{
+			"tenant-a:key-a:admin:" + tenantA,
+			"tenant-b:key-b:admin:" + tenantB,
+		}},
+	}
+	authenticator, err := auth.NewAuthenticator(ctx, &cfg.Auth, apiKeyRepo, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, err := auth.NewPolicy(nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := service.NewPersonService(repo, logger, &cfg.APIs, &cfg.Duplicates, &cfg.Enrichment, policy)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, logger, time.Hour, time.Minute)
+	server := NewServer(cfg, svc, idempotencyService, authenticator, policy, nil, health.NewChecker(time.Second), logger)
+	ts := httptest.NewServer(server.Router)
+	defer ts.Close()
+
+	// Записи создаются напрямую в репозитории, чтобы тест не обращался к API обогащения
+	name := "Изолированный"
+	surnameA := fmt.Sprintf("Арендатор%d", suffix)
+	surnameB := "Соседов"
+	create := func(tenantID, surname string) *models.Person {
+		t.Helper()
+		person := &models.Person{Name: name, Surname: &surname}
+		if err := repo.Create(tenant.WithID(ctx, tenantID), person); err != nil {
+			t.Fatalf("не удалось создать запись: %v", err)
+		}
+		return person
+	}
+	person := create(tenantA, surnameA)
+	deleted := create(tenantA, surnameA)
+	if err := repo.Delete(tenant.WithID(ctx, tenantA), deleted.ID); err != nil {
+		t.Fatal(err)
+	}
+	own := create(tenantB, surnameB)
+
+	do := func(t *testing.T, method, path, key, body string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(apiKeyHeader, key)
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(data)
+	}
+
+	if status, body := do(t, http.MethodGet, fmt.Sprintf("/api/v1/persons/%d", person.ID), "key-a", ""); status != http.StatusOK {
+		t.Fatalf("арендатор A не получил свою запись: %d %s", status, body)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"GetByID", http.MethodGet, fmt.Sprintf("/api/v1/persons/%d", person.ID), "", http.StatusNotFound},
+		{"GetByIDAsOf", http.MethodGet, fmt.Sprintf("/api/v1/persons/%d?as_of=%s", person.ID, time.Now().Add(time.Minute).UTC().Format(time.RFC3339)), "", http.StatusNotFound},
+		{"List", http.MethodGet, "/api/v1/persons?surname=" + url.QueryEscape(surnameA), "", http.StatusOK},
+		{"Replace", http.MethodPut, fmt.Sprintf("/api/v1/persons/%d", person.ID), `{"name": "Чужой"}`, http.StatusNotFound},
+		{"ApplyPatch", http.MethodPatch, fmt.Sprintf("/api/v1/persons/%d", person.ID), `{"surname": "Чужой"}`, http.StatusNotFound},
+		{"Delete", http.MethodDelete, fmt.Sprintf("/api/v1/persons/%d", person.ID), "", http.StatusNotFound},
+		{"Restore", http.MethodPost, fmt.Sprintf("/api/v1/persons/%d/restore", deleted.ID), "", http.StatusNotFound},
+		{"BulkPatch", http.MethodPatch, "/api/v1/persons?dry_run=false&expected_count=1&surname=" + url.QueryEscape(surnameA), `{"age": 99}`, http.StatusConflict},
+		{"BulkDelete", http.MethodDelete, "/api/v1/persons?dry_run=false&expected_count=1&surname=" + url.QueryEscape(surnameA), "", http.StatusConflict},
+		{"Merge", http.MethodPost, "/api/v1/persons/merge", fmt.Sprintf(`{"target_id": %d, "source_ids": [%d]}`, own.ID, person.ID), http.StatusNotFound},
+		{"FindSimilar", http.MethodGet, fmt.Sprintf("/api/v1/persons/%d/duplicates", person.ID), "", http.StatusNotFound},
+		{"FindSimilarOwn", http.MethodGet, fmt.Sprintf("/api/v1/persons/%d/duplicates?min_score=0", own.ID), "", http.StatusOK},
+		{"History", http.MethodGet, fmt.Sprintf("/api/v1/persons/%d/history", person.ID), "", http.StatusOK},
+		{"Export", http.MethodGet, "/api/v1/persons/export?format=ndjson&include_deleted=true", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := do(t, tt.method, tt.path, "key-b", tt.body)
+			if status != tt.status {
+				t.Fatalf("ожидался статус %d, получен %d: %s", tt.status, status, body)
+			}
+			if strings.Contains(body, surnameA) {
+				t.Fatalf("в ответе арендатору B есть данные арендатора A: %s", body)
+			}
+		})
+	}
+
+	t.Run("HistoryEmpty", func(t *testing.T) {
+		_, body := do(t, http.MethodGet, fmt.Sprintf("/api/v1/persons/%d/history", person.ID), "key-b", "")
+		var entries []json.RawMessage
+		if err := json.Unmarshal([]byte(body), &entries); err != nil {
+			t.Fatalf("некорректный ответ истории: %v: %s", err, body)
+		}
+		if len(entries) != 0 {
+			t.Fatalf("арендатору B видны %d изменений записи арендатора A", len(entries))
+		}
+	})
+
+	t.Run("TenantHeader", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/persons/%d", ts.URL, person.ID), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(apiKeyHeader, "key-b")
+		req.Header.Set(tenantHeader, tenantA)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("ключ арендатора B получил доступ к арендатору A через %s: %d", tenantHeader, resp.StatusCode)
+		}
+	})
+
+	got, err := repo.GetByID(tenant.WithID(ctx, tenantA), person.ID, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Surname == nil || *got.Surname != surnameA || got.Age != nil || got.DeletedAt != nil || got.Version != 1 {
+		t.Fatalf("запись арендатора A изменена другим арендатором: %+v", got)
+	}
+	restored, err := repo.GetByID(tenant.WithID(ctx, tenantA), deleted.ID, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.DeletedAt == nil {
+		t.Fatal("запись арендатора A восстановлена другим арендатором")
+	}
+}
@@ -0,0 +1,181 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"person-service/internal/models"
+	"person-service/internal/repository"
+	"person-service/pkg/logger"
+	"strconv"
+)
+
+// BulkPatchPersons частично обновляет все записи, подходящие под фильтры.
+// @Summary Массово обновить записи о людях по фильтру
+// @Description Применяет PersonUpdate ко всем записям, подходящим под фильтры списка. Нужен хотя бы один фильтр.
+// @Description По умолчанию выполняется предпросмотр (dry_run=true), который возвращает число подходящих записей.
+// @Description Для выполнения нужно передать dry_run=false и expected_count с числом из предпросмотра; изменения выполняются в одной транзакции.
+// @Tags persons
+// @Accept json
+// @Produce json
+// @Param name query string false "Фильтр по имени"
+// @Param surname query string false "Фильтр по фамилии"
+// @Param patronymic query string false "Фильтр по отчеству"
+// @Param age query int false "Фильтр по возрасту"
+// @Param gender query string false "Фильтр по полу (male, female)"
+// @Param nationality query string false "Фильтр по национальности"
+// @Param dry_run query bool false "Только подсчитать затрагиваемые записи" default(true)
+// @Param expected_count query int false "Число записей из предпросмотра, обязательно при dry_run=false"
+// @Param person body models.PersonUpdate true "Обновляемые поля"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом и телом возвращает сохранённый ответ"
+// @Success 200 {object} models.BulkResult "Результат предпросмотра или обновления"
+// @Failure 400 {object} map[string]string "Нет фильтров, некорректные параметры, JSON или ошибка валидации"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
+// @Failure 409 {object} map[string]string "Число записей изменилось после предпросмотра"
+// @Failure 413 {object} map[string]string "Тело запроса с Idempotency-Key больше server.request_max_bytes"
+// @Failure 422 {object} map[string]string "Превышен лимит записей массовой операции"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов, повторите после паузы из Retry-After"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/persons [patch]
+func (h *Handler) BulkPatchPersons(w http.ResponseWriter, r *http.Request) {
+	filters := parseFilters(r)
+	dryRun, expected, err := parseBulkParams(r)
+	if err != nil {
+		h.log(r).Error("Некорректные параметры массовой операции", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	var update models.PersonUpdate
+	if err := decoder.Decode(&update); err != nil {
+		h.log(r).Error("Ошибка декодирования запроса", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный JSON или несуществующее поле"}`, http.StatusBadRequest)
+		return
+	}
+	if err := update.Validate(); err != nil {
+		h.log(r).Error("Ошибка валидации", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	if dryRun {
+		h.previewBulk(w, r, filters)
+		return
+	}
+	affected, err := h.service.BulkPatch(r.Context(), filters, &update, expected, h.cfg.BulkMaxRows)
+	h.writeBulkResult(w, r, affected, expected, err)
+}
+
+// BulkDeletePersons помечает удалёнными все записи, подходящие под фильтры.
+// @Summary Массово удалить записи о людях по фильтру
+// @Description Помечает удалёнными все записи, подходящие под фильтры списка. Нужен хотя бы один фильтр.
+// @Description По умолчанию выполняется предпросмотр (dry_run=true), который возвращает число подходящих записей.
+// @Description Для выполнения нужно передать dry_run=false и expected_count с числом из предпросмотра; удаление выполняется в одной транзакции.
+// @Tags persons
+// @Produce json
+// @Param name query string false "Фильтр по имени"
+// @Param surname query string false "Фильтр по фамилии"
+// @Param patronymic query string false "Фильтр по отчеству"
+// @Param age query int false "Фильтр по возрасту"
+// @Param gender query string false "Фильтр по полу (male, female)"
+// @Param nationality query string false "Фильтр по национальности"
+// @Param dry_run query bool false "Только подсчитать затрагиваемые записи" default(true)
+// @Param expected_count query int false "Число записей из предпросмотра, обязательно при dry_run=false"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом и телом возвращает сохранённый ответ"
+// @Success 200 {object} models.BulkResult "Результат предпросмотра или удаления"
+// @Failure 400 {object} map[string]string "Нет фильтров или некорректные параметры"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
+// @Failure 409 {object} map[string]string "Число записей изменилось после предпросмотра"
+// @Failure 422 {object} map[string]string "Превышен лимит записей массовой операции"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов, повторите после паузы из Retry-After"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/persons [delete]
+func (h *Handler) BulkDeletePersons(w http.ResponseWriter, r *http.Request) {
+	filters := parseFilters(r)
+	dryRun, expected, err := parseBulkParams(r)
+	if err != nil {
+		h.log(r).Error("Некорректные параметры массовой операции", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	if dryRun {
+		h.previewBulk(w, r, filters)
+		return
+	}
+	affected, err := h.service.BulkDelete(r.Context(), filters, expected, h.cfg.BulkMaxRows)
+	h.writeBulkResult(w, r, affected, expected, err)
+}
+
+// previewBulk отправляет число записей, которые затронет массовая операция.
+func (h *Handler) previewBulk(w http.ResponseWriter, r *http.Request, filters map[string]string) {
+	matched, err := h.service.PreviewBulk(r.Context(), filters)
+	if err != nil {
+		h.log(r).Error("Ошибка предпросмотра массовой операции", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	h.writeBulk(w, r, &models.BulkResult{DryRun: true, Matched: matched, MaxRows: h.cfg.BulkMaxRows})
+}
+
+// writeBulkResult отправляет результат выполненной массовой операции.
+func (h *Handler) writeBulkResult(w http.ResponseWriter, r *http.Request, affected, matched int, err error) {
+	if err != nil {
+		h.log(r).Error("Ошибка массовой операции", logger.ErrorKV("error", err))
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, repository.ErrBulkCountMismatch):
+			status = http.StatusConflict
+		case errors.Is(err, repository.ErrBulkLimitExceeded):
+			status = http.StatusUnprocessableEntity
+		}
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), status)
+		return
+	}
+
+	h.writeBulk(w, r, &models.BulkResult{Matched: matched, Affected: affected, MaxRows: h.cfg.BulkMaxRows})
+}
+
+// writeBulk кодирует результат массовой операции.
+func (h *Handler) writeBulk(w http.ResponseWriter, r *http.Request, result *models.BulkResult) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.log(r).Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
+	}
+}
+
+// parseBulkParams разбирает параметры dry_run и expected_count массовой операции.
+// Без явного dry_run=false выполняется только предпросмотр.
+func parseBulkParams(r *http.Request) (bool, int, error) {
+	dryRun := true
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, 0, fmt.Errorf("некорректный параметр dry_run")
+		}
+		dryRun = parsed
+	}
+	if dryRun {
+		return true, 0, nil
+	}
+
+	value := r.URL.Query().Get("expected_count")
+	if value == "" {
+		return false, 0, fmt.Errorf("expected_count обязателен при dry_run=false")
+	}
+	expected, err := strconv.Atoi(value)
+	if err != nil || expected < 0 {
+		return false, 0, fmt.Errorf("некорректный параметр expected_count")
+	}
+	return false, expected, nil
+}
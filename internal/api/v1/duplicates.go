@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"person-service/internal/models"
+	"person-service/internal/repository"
+	"person-service/pkg/logger"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetPersonDuplicates возвращает записи, похожие на указанную.
+// @Summary Найти дубликаты записи о человеке
+// @Description Ищет неудалённые записи с похожим полным именем по триграммам и оценивает их.
+// @Description Оценка от 0 до 1 — среднее триграммного сходства полного имени и совпадения нормализованных фамилии, имени и отчества (без учёта регистра и различия е/ё).
+// @Tags persons
+// @Produce json
+// @Param id path int true "ID человека"
+// @Param limit query int false "Максимальное число кандидатов (до 50)" default(10)
+// @Param min_score query number false "Минимальная оценка от 0 до 1" default(0.5)
+// @Success 200 {array} models.DuplicateCandidate "Кандидаты в дубликаты, начиная с самых похожих"
+// @Failure 400 {object} map[string]string "Некорректный ID или параметры"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
+// @Failure 404 {object} map[string]string "Запись не найдена"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов, повторите после паузы из Retry-After"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/persons/{id}/duplicates [get]
+func (h *Handler) GetPersonDuplicates(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.log(r).Error("Некорректный ID", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+	minScore := 0.5
+	if value := r.URL.Query().Get("min_score"); value != "" {
+		minScore, err = strconv.ParseFloat(value, 64)
+		if err != nil || minScore < 0 || minScore > 1 {
+			h.log(r).Error("Некорректный параметр min_score", logger.ErrorKV("min_score", value))
+			http.Error(w, `{"error": "Некорректный параметр min_score, ожидается число от 0 до 1"}`, http.StatusBadRequest)
+			return
+		}
+	}
+
+	candidates, err := h.service.FindDuplicates(r.Context(), id, limit, minScore)
+	if err != nil {
+		h.log(r).Error("Ошибка поиска дубликатов", logger.ErrorKV("error", err))
+		if repository.IsNotFound(err) {
+			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "Внутренняя ошибка сервера"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(candidates); err != nil {
+		h.log(r).Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
+	}
+}
+
+// MergePersons сливает записи-источники в целевую запись.
+// @Summary Слить записи о людях
+// @Description Сливает записи source_ids в запись target_id в одной транзакции. Значение каждого поля выбирается по правилу из rules:
+// @Description first_non_empty (по умолчанию; целевая запись, затем источники по порядку), target, most_recent (запись, изменённая последней), longest (самая длинная строка), max и min (только age).
+// @Description Источники помечаются удалёнными; в истории целевой записи сохраняется merged_from, в истории источников — merged_into.
+// @Tags persons
+// @Accept json
+// @Produce json
+// @Produce xml
+// @Produce application/msgpack
+// @Produce text/csv
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом и телом возвращает сохранённый ответ"
+// @Param merge body models.MergeRequest true "Записи и правила слияния"
+// @Success 200 {object} models.Person "Целевая запись после слияния"
+// @Failure 400 {object} map[string]string "Некорректный JSON, правило или ошибка валидации"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав: требуются persons:write и persons:delete"
+// @Failure 404 {object} map[string]string "Одна из записей не найдена или удалена"
+// @Failure 406 {object} map[string]string "Неподдерживаемый Accept"
+// @Failure 413 {object} map[string]string "Тело запроса с Idempotency-Key больше server.request_max_bytes"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов, повторите после паузы из Retry-After"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/persons/merge [post]
+func (h *Handler) MergePersons(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.responseCodec(w, r)
+	if !ok {
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	var req models.MergeRequest
+	if err := decoder.Decode(&req); err != nil {
+		h.log(r).Error("Ошибка декодирования запроса", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный JSON или несуществующее поле"}`, http.StatusBadRequest)
+		return
+	}
+
+	person, err := h.service.Merge(r.Context(), &req)
+	if err != nil {
+		h.log(r).Error("Ошибка слияния записей", logger.ErrorKV("error", err))
+		if repository.IsNotFound(err) {
+			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	h.writePerson(w, r, c, http.StatusOK, person)
+}
@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"person-service/internal/auth"
+	"person-service/internal/exporter"
+	"person-service/pkg/logger"
+	"time"
+)
+
+// ExportPersons потоково выгружает все записи под фильтрами списка.
+// @Summary Выгрузить записи о людях
+// @Description Выгружает все записи, подходящие под фильтры списка, без пагинации. Записи читаются серверным курсором и передаются клиенту по мере чтения.
+// @Tags persons
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.apache.parquet
+// @Param format query string false "Формат выгрузки (csv, ndjson, parquet)" default(csv)
+// @Param name query string false "Фильтр по имени"
+// @Param surname query string false "Фильтр по фамилии"
+// @Param patronymic query string false "Фильтр по отчеству"
+// @Param age query int false "Фильтр по возрасту"
+// @Param gender query string false "Фильтр по полу (male, female)"
+// @Param nationality query string false "Фильтр по национальности"
+// @Param include_deleted query bool false "Включить удалённые записи (требуется право persons:delete)"
+// @Success 200 {file} file "Файл выгрузки"
+// @Failure 400 {object} map[string]string "Некорректные параметры"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов, повторите после паузы из Retry-After"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/persons/export [get]
+func (h *Handler) ExportPersons(w http.ResponseWriter, r *http.Request) {
+	format, err := exporter.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		h.log(r).Error("Некорректный формат выгрузки", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	filters := parseFilters(r)
+	includeDeleted, err := parseIncludeDeleted(r)
+	if err != nil {
+		h.log(r).Error("Некорректный параметр include_deleted", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный параметр include_deleted"}`, http.StatusBadRequest)
+		return
+	}
+	if includeDeleted {
+		filters["include_deleted"] = "true"
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="persons-%s.%s"`, time.Now().UTC().Format("20060102-150405"), format))
+
+	out := &trackingWriter{ResponseWriter: w}
+	writer, err := exporter.NewWriter(format, out)
+	if err == nil {
+		err = h.service.Export(r.Context(), filters, writer.Write)
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		h.log(r).Error("Ошибка выгрузки", logger.ErrorKV("error", err))
+		// После начала передачи статус уже не изменить, клиент получит оборванный файл.
+		if !out.written {
+			w.Header().Del("Content-Disposition")
+			status := http.StatusInternalServerError
+			if errors.Is(err, auth.ErrForbidden) {
+				status = http.StatusForbidden
+			}
+			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), status)
+		}
+	}
+}
+
+// trackingWriter запоминает, начата ли передача ответа клиенту.
+type trackingWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (t *trackingWriter) Write(data []byte) (int, error) {
+	t.written = true
+	return t.ResponseWriter.Write(data)
+}
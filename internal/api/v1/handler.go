@@ -1,13 +1,23 @@
 package v1
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
 	"net/http"
+	"person-service/internal/auth"
+	"person-service/internal/config"
+	"person-service/internal/importer"
 	"person-service/internal/models"
+	"person-service/internal/ratelimit"
+	"person-service/internal/repository"
 	"person-service/internal/service"
 	"person-service/pkg/logger"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -15,167 +25,453 @@ import (
 
 // Handler предоставляет обработчики для REST API.
 type Handler struct {
-	service *service.PersonService
-	logger  *zap.Logger
+	cfg      *config.Server
+	service  *service.PersonService
+	importer *importer.Importer
+	logger   *zap.Logger
 }
 
 // NewHandler создаёт новый экземпляр Handler.
-func NewHandler(service *service.PersonService, logger *zap.Logger) *Handler {
+func NewHandler(cfg *config.Server, service *service.PersonService, logger *zap.Logger) *Handler {
 	return &Handler{
-		service: service,
-		logger:  logger,
+		cfg:      cfg,
+		service:  service,
+		importer: importer.NewImporter(service, logger),
+		logger:   logger,
 	}
 }
 
+// log возвращает логгер с идентификаторами трассировки запроса.
+func (h *Handler) log(r *http.Request) *zap.Logger {
+	return logger.FromContext(r.Context(), h.logger)
+}
+
 // CreatePerson создаёт новую запись о человеке.
 // @Summary Создать новую запись о человеке
 // @Description Создаёт запись о человеке с указанным именем, фамилией (опционально) и отчеством (опционально). Данные обогащаются через внешние API (Agify.io, Genderize.io, Nationalize.io).
 // @Tags persons
 // @Accept json
+// @Accept xml
+// @Accept application/msgpack
 // @Produce json
+// @Produce xml
+// @Produce application/msgpack
+// @Produce text/csv
 // @Param person body models.PersonInput true "Данные человека"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом и телом возвращает сохранённый ответ"
 // @Success 201 {object} models.Person "Созданная запись"
-// @Failure 400 {object} map[string]string "Некорректный JSON или ошибка валидации, например, пустое имя"
+// @Failure 400 {object} map[string]string "Некорректное тело запроса или ошибка валидации, например, пустое имя"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
+// @Failure 406 {object} map[string]string "Неподдерживаемый Accept"
+// @Failure 409 {object} map[string]string "Найдены дубликаты (при duplicates.reject_on_create) или запрос с этим Idempotency-Key ещё выполняется"
+// @Failure 413 {object} map[string]string "Тело запроса с Idempotency-Key больше server.request_max_bytes"
+// @Failure 415 {object} map[string]string "Неподдерживаемый Content-Type"
+// @Failure 422 {object} map[string]string "Idempotency-Key уже использован для другого запроса"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов (см. Retry-After) или арендатор исчерпал дневную квоту обогащения"
 // @Failure 500 {object} map[string]string "Внутренняя ошибка сервера, например, сбой API обогащения"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/persons [post]
 func (h *Handler) CreatePerson(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.responseCodec(w, r)
+	if !ok {
+		return
+	}
 	var input models.PersonInput
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		h.logger.Error("Ошибка декодирования запроса", logger.ErrorKV("error", err))
-		http.Error(w, `{"error": "Некорректный JSON"}`, http.StatusBadRequest)
+	if !h.decodeBody(w, r, &input) {
 		return
 	}
 
 	person, err := h.service.Create(r.Context(), &input)
 	if err != nil {
-		h.logger.Error("Ошибка создания записи", logger.ErrorKV("error", err))
+		h.log(r).Error("Ошибка создания записи", logger.ErrorKV("error", err))
+		var duplicateErr *service.DuplicateError
+		if errors.As(err, &duplicateErr) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]any{"error": err.Error(), "duplicates": duplicateErr.IDs})
+			return
+		}
+		if errors.Is(err, service.ErrEnrichmentQuotaExceeded) {
+			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusTooManyRequests)
+			return
+		}
 		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(person); err != nil {
-		h.logger.Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/persons/%d", person.ID))
+	h.writePerson(w, r, c, http.StatusCreated, person)
+}
+
+// CreatePersonsBatch создаёт записи пакетом.
+// @Summary Создать записи о людях пакетом
+// @Description Создаёт записи из массива PersonInput в одной транзакции. Обогащение выполняется один раз для каждого уникального имени.
+// @Description В режиме all_or_nothing (по умолчанию) ошибка любого элемента отменяет весь пакет, в режиме partial сохраняются корректные элементы.
+// @Description Результат содержит статус каждого элемента: created, failed или skipped.
+// @Description Каждое имя, которого нет в кэше обогащения, расходует токен лимита create. Элементы с именами, на которые токенов не хватило, завершаются ошибкой;
+// @Description если не хватило ни на одно имя или режим all_or_nothing, запрос отклоняется с 429.
+// @Tags persons
+// @Accept json
+// @Produce json
+// @Param mode query string false "Режим обработки ошибок (all_or_nothing, partial)" default(all_or_nothing)
+// @Param persons body []models.PersonInput true "Данные людей"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом и телом возвращает сохранённый ответ"
+// @Success 201 {object} models.BatchResult "Все записи созданы"
+// @Success 207 {object} models.BatchResult "Часть записей создана (режим partial)"
+// @Failure 400 {object} map[string]string "Некорректный JSON, режим или пустой пакет"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
+// @Failure 413 {object} map[string]string "Превышен максимальный размер пакета или тело запроса с Idempotency-Key больше server.request_max_bytes"
+// @Failure 422 {object} models.BatchResult "Ни одна запись не создана"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов, повторите после паузы из Retry-After"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/persons:batch [post]
+func (h *Handler) CreatePersonsBatch(w http.ResponseWriter, r *http.Request) {
+	mode, err := models.ParseBatchMode(r.URL.Query().Get("mode"))
+	if err != nil {
+		h.log(r).Error("Некорректный режим пакета", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	var inputs []models.PersonInput
+	if err := json.NewDecoder(r.Body).Decode(&inputs); err != nil {
+		h.log(r).Error("Ошибка декодирования запроса", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный JSON: ожидается массив записей"}`, http.StatusBadRequest)
+		return
+	}
+	if len(inputs) == 0 {
+		h.log(r).Error("Пустой пакет в запросе")
+		http.Error(w, `{"error": "Пакет не содержит записей"}`, http.StatusBadRequest)
+		return
+	}
+	if len(inputs) > h.cfg.BatchMaxItems {
+		h.log(r).Error("Превышен размер пакета", logger.ErrorKV("count", len(inputs)))
+		http.Error(w, fmt.Sprintf(`{"error": "Пакет содержит больше %d записей"}`, h.cfg.BatchMaxItems), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	result, err := h.service.CreateBatch(r.Context(), inputs, mode, true)
+	if err != nil {
+		h.log(r).Error("Ошибка пакетного создания записей", logger.ErrorKV("error", err))
+		var limitErr *ratelimit.LimitedError
+		if errors.As(err, &limitErr) {
+			setRetryAfter(w, limitErr)
+			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, limitErr.Error()), http.StatusTooManyRequests)
+			return
+		}
 		http.Error(w, `{"error": "Внутренняя ошибка сервера"}`, http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusCreated
+	switch {
+	case result.Created == 0:
+		status = http.StatusUnprocessableEntity
+	case result.Failed > 0:
+		status = http.StatusMultiStatus
 	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.log(r).Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
+	}
+}
+
+// setRetryAfter добавляет к ответу заголовок Retry-After из ошибки лимита запросов.
+func setRetryAfter(w http.ResponseWriter, err *ratelimit.LimitedError) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(err.Result.RetryAfter.Seconds())))
 }
 
 // GetPerson возвращает запись по ID.
 // @Summary Получить запись о человеке по ID
-// @Description Возвращает запись о человеке по указанному ID.
+// @Description Возвращает запись о человеке по указанному ID. Удалённые записи возвращаются только с include_deleted=true.
 // @Tags persons
 // @Produce json
+// @Produce xml
+// @Produce application/msgpack
+// @Produce text/csv
 // @Param id path int true "ID человека"
+// @Param include_deleted query bool false "Включить удалённые записи (требуется право persons:delete)"
+// @Param as_of query string false "Вернуть состояние записи на момент времени (RFC 3339)"
+// @Param fields query string false "Выводимые поля через запятую, например id,name,surname"
+// @Param expand query string false "Раскрываемые связанные данные через запятую (history, enrichment)"
 // @Success 200 {object} models.Person "Запись найдена"
-// @Failure 400 {object} map[string]string "Некорректный ID, например, не число"
+// @Failure 400 {object} map[string]string "Некорректный ID, поле или раскрытие"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
 // @Failure 404 {object} map[string]string "Запись не найдена"
+// @Failure 406 {object} map[string]string "Неподдерживаемый Accept"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов, повторите после паузы из Retry-After"
 // @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
-// @Router /api/v1/persons/{id} [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/persons/{id} [get]
 func (h *Handler) GetPerson(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		h.logger.Error("Некорректный ID", logger.ErrorKV("error", err))
+		h.log(r).Error("Некорректный ID", logger.ErrorKV("error", err))
 		http.Error(w, `{"error": "Некорректный ID"}`, http.StatusBadRequest)
 		return
 	}
+	c, ok := h.responseCodec(w, r)
+	if !ok {
+		return
+	}
 
-	person, err := h.service.GetByID(r.Context(), id)
+	includeDeleted, err := parseIncludeDeleted(r)
+	if err != nil {
+		h.log(r).Error("Некорректный параметр include_deleted", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный параметр include_deleted"}`, http.StatusBadRequest)
+		return
+	}
+
+	fields, expand, ok := h.parseFieldsParams(w, r)
+	if !ok {
+		return
+	}
+
+	var person *models.Person
+	if asOfStr := r.URL.Query().Get("as_of"); asOfStr != "" {
+		asOf, parseErr := time.Parse(time.RFC3339, asOfStr)
+		if parseErr != nil {
+			h.log(r).Error("Некорректный параметр as_of", logger.ErrorKV("error", parseErr))
+			http.Error(w, `{"error": "Некорректный параметр as_of, ожидается RFC 3339"}`, http.StatusBadRequest)
+			return
+		}
+		person, err = h.service.GetByIDAsOf(r.Context(), id, asOf, includeDeleted)
+	} else {
+		person, err = h.service.GetByID(r.Context(), id, includeDeleted, selectFields(fields, expand))
+	}
+	if errors.Is(err, auth.ErrForbidden) {
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusForbidden)
+		return
+	}
 	if err != nil {
-		h.logger.Error("Ошибка получения записи", logger.ErrorKV("error", err))
+		h.log(r).Error("Ошибка получения записи", logger.ErrorKV("error", err))
 		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusNotFound)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(person); err != nil {
-		h.logger.Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
+	views, err := h.personViews(r.Context(), []*models.Person{person}, fields, expand)
+	if errors.Is(err, auth.ErrForbidden) {
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		h.log(r).Error("Ошибка раскрытия связанных данных", logger.ErrorKV("error", err))
 		http.Error(w, `{"error": "Внутренняя ошибка сервера"}`, http.StatusInternalServerError)
+		return
 	}
+	h.writePersonView(w, r, c, http.StatusOK, views[0])
 }
 
 // UpdatePerson обновляет запись полностью.
-// @Summary Обновить запись о человеке
-// @Description Полностью обновляет существующую запись о человеке по указанному ID.
+// @Summary Заменить запись о человеке
+// @Description Полностью заменяет запись о человеке по указанному ID: поля, не указанные в теле, очищаются.
+// @Description Поля created_at, updated_at и version игнорируются, id допускается только совпадающий с ID в пути.
+// @Description Если включена настройка server.put_create_if_absent, отсутствующая запись создаётся с указанным ID,
+// @Description а последовательность ID сдвигается за него, чтобы POST не выдал его повторно.
 // @Tags persons
 // @Accept json
+// @Accept xml
+// @Accept application/msgpack
 // @Produce json
+// @Produce xml
+// @Produce application/msgpack
+// @Produce text/csv
 // @Param id path int true "ID человека"
-// @Param person body models.Person true "Обновлённые данные человека"
-// @Success 200 {object} map[string]string "Запись обновлена"
-// @Failure 400 {object} map[string]string "Некорректный ID, JSON или ошибка валидации"
+// @Param person body models.PersonReplace true "Новые данные человека"
+// @Success 200 {object} models.Person "Запись обновлена"
+// @Success 201 {object} models.Person "Запись создана"
+// @Failure 400 {object} map[string]string "Некорректный ID, тело запроса, несуществующее поле или ошибка валидации"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
 // @Failure 404 {object} map[string]string "Запись не найдена"
+// @Failure 406 {object} map[string]string "Неподдерживаемый Accept"
+// @Failure 409 {object} map[string]string "Запись нельзя создать с этим ID: он занят"
+// @Failure 415 {object} map[string]string "Неподдерживаемый Content-Type"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов, повторите после паузы из Retry-After"
 // @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/persons/{id} [put]
 func (h *Handler) UpdatePerson(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		h.logger.Error("Некорректный ID", logger.ErrorKV("error", err))
+		h.log(r).Error("Некорректный ID", logger.ErrorKV("error", err))
 		http.Error(w, `{"error": "Некорректный ID"}`, http.StatusBadRequest)
 		return
 	}
 
-	var person models.Person
-	if err := json.NewDecoder(r.Body).Decode(&person); err != nil {
-		h.logger.Error("Ошибка декодирования запроса", logger.ErrorKV("error", err))
-		http.Error(w, `{"error": "Некорректный JSON"}`, http.StatusBadRequest)
+	c, ok := h.responseCodec(w, r)
+	if !ok {
+		return
+	}
+	input, ok := h.decodeReplace(w, r, id)
+	if !ok {
 		return
 	}
-	person.ID = id
 
-	if err := h.service.Update(r.Context(), &person); err != nil {
-		h.logger.Error("Ошибка обновления записи", logger.ErrorKV("error", err))
-		if err.Error() == fmt.Sprintf("не удалось найти запись: запись с id %d не найдена", id) {
+	person, created, err := h.service.Replace(r.Context(), id, input, h.cfg.PutCreateIfAbsent)
+	if err != nil {
+		h.log(r).Error("Ошибка обновления записи", logger.ErrorKV("error", err))
+		if repository.IsNotFound(err) {
 			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, repository.ErrIDUnavailable) {
+			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusConflict)
+			return
+		}
 		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(map[string]string{"message": "Запись обновлена"}); err != nil {
-		h.logger.Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
+	status := http.StatusOK
+	if created {
+		w.Header().Set("Location", fmt.Sprintf("/api/v1/persons/%d", person.ID))
+		status = http.StatusCreated
+	}
+	h.writePerson(w, r, c, status, person)
+}
+
+// decodeReplace декодирует тело PUT-запроса в PersonReplace.
+// В JSON read-only поля отбрасываются, а несуществующие поля отклоняются; в остальных представлениях лишние поля игнорируются.
+func (h *Handler) decodeReplace(w http.ResponseWriter, r *http.Request, id int) (*models.PersonReplace, bool) {
+	reqCodec, err := requestCodec(r)
+	if err != nil {
+		h.log(r).Error("Неподдерживаемый Content-Type", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusUnsupportedMediaType)
+		return nil, false
+	}
+	var input models.PersonReplace
+	if reqCodec.mediaType != mediaTypeJSON {
+		if err := reqCodec.decode(r.Body, &input); err != nil {
+			h.log(r).Error("Ошибка декодирования запроса", logger.ErrorKV("error", err))
+			http.Error(w, `{"error": "Некорректное тело запроса"}`, http.StatusBadRequest)
+			return nil, false
+		}
+		return &input, true
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+		h.log(r).Error("Ошибка декодирования запроса", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный JSON"}`, http.StatusBadRequest)
+		return nil, false
+	}
+	if err := models.StripReadOnlyFields(doc, id); err != nil {
+		h.log(r).Error("Некорректные read-only поля", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		return nil, false
+	}
+
+	// Повторно декодируем без read-only полей, чтобы отклонить несуществующие поля
+	data, err := json.Marshal(doc)
+	if err != nil {
+		h.log(r).Error("Ошибка сериализации запроса", logger.ErrorKV("error", err))
 		http.Error(w, `{"error": "Внутренняя ошибка сервера"}`, http.StatusInternalServerError)
+		return nil, false
 	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&input); err != nil {
+		h.log(r).Error("Ошибка декодирования в PersonReplace", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный JSON или несуществующее поле"}`, http.StatusBadRequest)
+		return nil, false
+	}
+	return &input, true
 }
 
 // PatchPerson частично обновляет запись.
 // @Summary Частично обновить запись о человеке
-// @Description Частично обновляет существующую запись о человеке по указанному ID. Возвращает сообщение об успехе, совпадении данных или ошибке.
+// @Description Частично обновляет существующую запись о человеке по указанному ID.
+// @Description Тип содержимого application/json принимает PersonUpdate: указанные поля заменяются, остальные не изменяются.
+// @Description application/merge-patch+json (RFC 7396) очищает поле значением null, application/json-patch+json (RFC 6902) поддерживает операции test, replace и remove.
+// @Description Изменения применяются атомарно, в ответе возвращается обновлённая запись с новой версией; если данные совпадают с текущими, версия не изменяется.
 // @Tags persons
 // @Accept json
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Accept xml
+// @Accept application/msgpack
 // @Produce json
+// @Produce xml
+// @Produce application/msgpack
+// @Produce text/csv
 // @Param id path int true "ID человека"
 // @Param person body models.PersonUpdate true "Обновлённые данные человека"
-// @Success 200 {object} map[string]string "Запись обновлена или данные не изменены"
+// @Success 200 {object} models.Person "Обновлённая запись"
 // @Failure 400 {object} map[string]string "Некорректный ID, JSON, пустой запрос, несуществующее поле или ошибка валидации"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
 // @Failure 404 {object} map[string]string "Запись не найдена"
+// @Failure 406 {object} map[string]string "Неподдерживаемый Accept"
+// @Failure 409 {object} map[string]string "Операция test из JSON Patch не пройдена"
+// @Failure 415 {object} map[string]string "Неподдерживаемый тип содержимого"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов, повторите после паузы из Retry-After"
 // @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/persons/{id} [patch]
 func (h *Handler) PatchPerson(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		h.logger.Error("Некорректный ID", logger.ErrorKV("error", err))
+		h.log(r).Error("Некорректный ID", logger.ErrorKV("error", err))
 		http.Error(w, `{"error": "Некорректный ID"}`, http.StatusBadRequest)
 		return
 	}
+	c, ok := h.responseCodec(w, r)
+	if !ok {
+		return
+	}
+
+	contentType := "application/json"
+	if header := r.Header.Get("Content-Type"); header != "" {
+		contentType, _, err = mime.ParseMediaType(header)
+		if err != nil {
+			h.log(r).Error("Некорректный Content-Type", logger.ErrorKV("error", err))
+			http.Error(w, `{"error": "Некорректный Content-Type"}`, http.StatusUnsupportedMediaType)
+			return
+		}
+	}
+	switch contentType {
+	case models.ContentTypeMergePatch:
+		h.mergePatchPerson(w, r, c, id)
+		return
+	case models.ContentTypeJSONPatch:
+		h.jsonPatchPerson(w, r, c, id)
+		return
+	case "application/json":
+	default:
+		if reqCodec := codecFor(contentType); reqCodec != nil && reqCodec.decode != nil {
+			h.decodedPatchPerson(w, r, c, reqCodec, id)
+			return
+		}
+		h.log(r).Error("Неподдерживаемый Content-Type", logger.ErrorKV("content_type", contentType))
+		http.Error(w, fmt.Sprintf(`{"error": "Неподдерживаемый Content-Type: %s"}`, contentType), http.StatusUnsupportedMediaType)
+		return
+	}
 
 	// Читаем JSON как RawMessage для проверки полей
 	var rawBody json.RawMessage
 	if err := json.NewDecoder(r.Body).Decode(&rawBody); err != nil {
-		h.logger.Error("Ошибка декодирования запроса", logger.ErrorKV("error", err))
+		h.log(r).Error("Ошибка декодирования запроса", logger.ErrorKV("error", err))
 		http.Error(w, `{"error": "Некорректный JSON"}`, http.StatusBadRequest)
 		return
 	}
 
 	// Проверяем, пустой ли запрос
 	if string(rawBody) == "{}" {
-		h.logger.Error("Пустой JSON в запросе")
+		h.log(r).Error("Пустой JSON в запросе")
 		http.Error(w, `{"error": "Не указано ни одного поля для обновления"}`, http.StatusBadRequest)
 		return
 	}
@@ -183,7 +479,7 @@ func (h *Handler) PatchPerson(w http.ResponseWriter, r *http.Request) {
 	// Проверяем наличие несуществующих полей
 	var tempMap map[string]interface{}
 	if err := json.Unmarshal(rawBody, &tempMap); err != nil {
-		h.logger.Error("Ошибка разбора JSON", logger.ErrorKV("error", err))
+		h.log(r).Error("Ошибка разбора JSON", logger.ErrorKV("error", err))
 		http.Error(w, `{"error": "Некорректный JSON"}`, http.StatusBadRequest)
 		return
 	}
@@ -198,7 +494,7 @@ func (h *Handler) PatchPerson(w http.ResponseWriter, r *http.Request) {
 	}
 	for key := range tempMap {
 		if !validFields[key] {
-			h.logger.Error("Указано несуществующее поле", logger.ErrorKV("field", key))
+			h.log(r).Error("Указано несуществующее поле", logger.ErrorKV("field", key))
 			http.Error(w, fmt.Sprintf(`{"error": "Указано несуществующее поле: %s"}`, key), http.StatusBadRequest)
 			return
 		}
@@ -207,68 +503,226 @@ func (h *Handler) PatchPerson(w http.ResponseWriter, r *http.Request) {
 	// Декодируем в PersonUpdate
 	var update models.PersonUpdate
 	if err := json.Unmarshal(rawBody, &update); err != nil {
-		h.logger.Error("Ошибка декодирования в PersonUpdate", logger.ErrorKV("error", err))
+		h.log(r).Error("Ошибка декодирования в PersonUpdate", logger.ErrorKV("error", err))
 		http.Error(w, `{"error": "Некорректный JSON"}`, http.StatusBadRequest)
 		return
 	}
 
-	err = h.service.Patch(r.Context(), id, &update)
+	person, err := h.service.Patch(r.Context(), id, &update)
+	h.writePatchResult(w, r, c, person, err)
+}
+
+// decodedPatchPerson частично обновляет запись по PersonUpdate в представлении, отличном от JSON.
+func (h *Handler) decodedPatchPerson(w http.ResponseWriter, r *http.Request, c, reqCodec *codec, id int) {
+	var update models.PersonUpdate
+	if err := reqCodec.decode(r.Body, &update); err != nil {
+		h.log(r).Error("Ошибка декодирования в PersonUpdate", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректное тело запроса"}`, http.StatusBadRequest)
+		return
+	}
+	if update.Name == nil && update.Surname == nil && update.Patronymic == nil &&
+		update.Age == nil && update.Gender == nil && update.Nationality == nil {
+		h.log(r).Error("Пустой запрос на обновление")
+		http.Error(w, `{"error": "Не указано ни одного поля для обновления"}`, http.StatusBadRequest)
+		return
+	}
+
+	person, err := h.service.Patch(r.Context(), id, &update)
+	h.writePatchResult(w, r, c, person, err)
+}
+
+// mergePatchPerson применяет к записи JSON Merge Patch (RFC 7396).
+func (h *Handler) mergePatchPerson(w http.ResponseWriter, r *http.Request, c *codec, id int) {
+	var patch map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		h.log(r).Error("Ошибка декодирования merge patch", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный JSON: ожидается объект"}`, http.StatusBadRequest)
+		return
+	}
+
+	person, err := h.service.MergePatch(r.Context(), id, patch)
+	h.writePatchResult(w, r, c, person, err)
+}
+
+// jsonPatchPerson применяет к записи операции JSON Patch (RFC 6902).
+func (h *Handler) jsonPatchPerson(w http.ResponseWriter, r *http.Request, c *codec, id int) {
+	var ops []models.PatchOperation
+	if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+		h.log(r).Error("Ошибка декодирования json patch", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный JSON: ожидается массив операций"}`, http.StatusBadRequest)
+		return
+	}
+
+	person, err := h.service.JSONPatch(r.Context(), id, ops)
+	h.writePatchResult(w, r, c, person, err)
+}
+
+// writePatchResult отправляет результат частичного обновления записи.
+func (h *Handler) writePatchResult(w http.ResponseWriter, r *http.Request, c *codec, person *models.Person, err error) {
 	if err != nil {
-		h.logger.Error("Ошибка частичного обновления записи", logger.ErrorKV("error", err))
-		if err.Error() == fmt.Sprintf("не удалось найти запись: запись с id %d не найдена", id) {
-			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusNotFound)
-			return
-		}
-		if err.Error() == "данные не изменены, так как они совпадают с текущими" {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			if err := json.NewEncoder(w).Encode(map[string]string{"message": "Данные не изменены, так как они совпадают с текущими"}); err != nil {
-				h.logger.Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
-				http.Error(w, `{"error": "Внутренняя ошибка сервера"}`, http.StatusInternalServerError)
-			}
-			return
+		h.log(r).Error("Ошибка частичного обновления записи", logger.ErrorKV("error", err))
+		status := http.StatusBadRequest
+		switch {
+		case repository.IsNotFound(err):
+			status = http.StatusNotFound
+		case errors.Is(err, models.ErrPatchTestFailed):
+			status = http.StatusConflict
 		}
-		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), status)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(map[string]string{"message": "Запись обновлена"}); err != nil {
-		h.logger.Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
-		http.Error(w, `{"error": "Внутренняя ошибка сервера"}`, http.StatusInternalServerError)
+	h.writePerson(w, r, c, http.StatusOK, person)
+}
+
+// writePerson отправляет запись в представлении c с заголовком ETag, содержащим её версию.
+func (h *Handler) writePerson(w http.ResponseWriter, r *http.Request, c *codec, status int, person *models.Person) {
+	h.writePersonView(w, r, c, status, fullView(person))
+}
+
+// writePersonView отправляет представление записи; ETag указывается, если в выборке есть версия.
+func (h *Handler) writePersonView(w http.ResponseWriter, r *http.Request, c *codec, status int, view personView) {
+	w.Header().Set("Content-Type", c.contentType)
+	w.Header().Set("Vary", "Accept")
+	if view.fields == nil || slices.Contains(view.fields, "version") {
+		w.Header().Set("ETag", fmt.Sprintf(`"%d"`, view.person.Version))
+	}
+	w.WriteHeader(status)
+	if err := c.encodePerson(w, view); err != nil {
+		h.log(r).Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
 	}
 }
 
 // DeletePerson удаляет запись.
 // @Summary Удалить запись о человеке
-// @Description Удаляет запись о человеке по указанному ID.
+// @Description Помечает запись о человеке удалённой. Запись можно восстановить, пока она не очищена фоновой задачей.
 // @Tags persons
 // @Produce json
 // @Param id path int true "ID человека"
 // @Success 200 {object} map[string]string "Запись удалена"
 // @Failure 400 {object} map[string]string "Некорректный ID, например, не число"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
 // @Failure 404 {object} map[string]string "Запись не найдена"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов, повторите после паузы из Retry-After"
 // @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/persons/{id} [delete]
 func (h *Handler) DeletePerson(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		h.logger.Error("Некорректный ID", logger.ErrorKV("error", err))
+		h.log(r).Error("Некорректный ID", logger.ErrorKV("error", err))
 		http.Error(w, `{"error": "Некорректный ID"}`, http.StatusBadRequest)
 		return
 	}
 
 	if err := h.service.Delete(r.Context(), id); err != nil {
-		h.logger.Error("Ошибка удаления записи", logger.ErrorKV("error", err))
+		h.log(r).Error("Ошибка удаления записи", logger.ErrorKV("error", err))
 		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusNotFound)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(map[string]string{"message": "Запись удалена"}); err != nil {
-		h.logger.Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
+		h.log(r).Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Внутренняя ошибка сервера"}`, http.StatusInternalServerError)
+	}
+}
+
+// RestorePerson восстанавливает удалённую запись.
+// @Summary Восстановить удалённую запись о человеке
+// @Description Снимает пометку удаления с записи о человеке по указанному ID.
+// @Tags persons
+// @Produce json
+// @Produce xml
+// @Produce application/msgpack
+// @Produce text/csv
+// @Param id path int true "ID человека"
+// @Success 200 {object} models.Person "Восстановленная запись"
+// @Failure 400 {object} map[string]string "Некорректный ID, например, не число"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
+// @Failure 404 {object} map[string]string "Удалённая запись не найдена"
+// @Failure 406 {object} map[string]string "Неподдерживаемый Accept"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов, повторите после паузы из Retry-After"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/persons/{id}/restore [post]
+func (h *Handler) RestorePerson(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.log(r).Error("Некорректный ID", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный ID"}`, http.StatusBadRequest)
+		return
+	}
+	c, ok := h.responseCodec(w, r)
+	if !ok {
+		return
+	}
+
+	person, err := h.service.Restore(r.Context(), id)
+	if err != nil {
+		h.log(r).Error("Ошибка восстановления записи", logger.ErrorKV("error", err))
+		if repository.IsNotFound(err) {
+			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "Внутренняя ошибка сервера"}`, http.StatusInternalServerError)
+		return
+	}
+
+	h.writePerson(w, r, c, http.StatusOK, person)
+}
+
+// GetPersonHistory возвращает историю изменений записи.
+// @Summary Получить историю изменений записи о человеке
+// @Description Возвращает изменения записи (создание, обновление, удаление, восстановление, обогащение), начиная с последних. Для каждого изменения указаны значения изменённых полей до и после, автор, ID запроса и источник.
+// @Tags persons
+// @Produce json
+// @Param id path int true "ID человека"
+// @Param limit query int false "Лимит записей" default(50)
+// @Param offset query int false "Смещение" default(0)
+// @Success 200 {array} models.HistoryEntry "История изменений"
+// @Failure 400 {object} map[string]string "Некорректный ID, например, не число"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов, повторите после паузы из Retry-After"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/persons/{id}/history [get]
+func (h *Handler) GetPersonHistory(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.log(r).Error("Некорректный ID", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	entries, err := h.service.History(r.Context(), id, limit, offset)
+	if err != nil {
+		h.log(r).Error("Ошибка получения истории", logger.ErrorKV("error", err))
 		http.Error(w, `{"error": "Внутренняя ошибка сервера"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		h.log(r).Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
 	}
 }
 
@@ -277,6 +731,9 @@ func (h *Handler) DeletePerson(w http.ResponseWriter, r *http.Request) {
 // @Description Возвращает список записей о людях с поддержкой пагинации и фильтров по всем полям. Если записей нет, возвращается сообщение.
 // @Tags persons
 // @Produce json
+// @Produce xml
+// @Produce application/msgpack
+// @Produce text/csv
 // @Param limit query int false "Лимит записей" default(10)
 // @Param offset query int false "Смещение" default(0)
 // @Param name query string false "Фильтр по имени"
@@ -285,11 +742,25 @@ func (h *Handler) DeletePerson(w http.ResponseWriter, r *http.Request) {
 // @Param age query int false "Фильтр по возрасту"
 // @Param gender query string false "Фильтр по полу (male, female)"
 // @Param nationality query string false "Фильтр по национальности"
+// @Param include_deleted query bool false "Включить удалённые записи (требуется право persons:delete)"
+// @Param fields query string false "Выводимые поля через запятую, например id,name,surname"
+// @Param expand query string false "Раскрываемые связанные данные через запятую (history, enrichment)"
 // @Success 200 {array} models.Person "Список записей или сообщение о пустом списке"
-// @Failure 400 {object} map[string]string "Некорректные параметры, например, отрицательный лимит"
+// @Failure 400 {object} map[string]string "Некорректные параметры, например, неизвестное поле"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
+// @Failure 406 {object} map[string]string "Неподдерживаемый Accept"
+// @Failure 429 {object} map[string]string "Превышен лимит запросов, повторите после паузы из Retry-After"
 // @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/persons [get]
 func (h *Handler) ListPersons(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.responseCodec(w, r)
+	if !ok {
+		return
+	}
+
 	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
 
@@ -302,49 +773,107 @@ func (h *Handler) ListPersons(w http.ResponseWriter, r *http.Request) {
 		offset = 0
 	}
 
-	filters := map[string]string{}
-	if name := r.URL.Query().Get("name"); name != "" {
-		filters["name"] = name
-	}
-	if surname := r.URL.Query().Get("surname"); surname != "" {
-		filters["surname"] = surname
-	}
-	if patronymic := r.URL.Query().Get("patronymic"); patronymic != "" {
-		filters["patronymic"] = patronymic
-	}
-	if age := r.URL.Query().Get("age"); age != "" {
-		filters["age"] = age
+	filters := parseFilters(r)
+
+	includeDeleted, err := parseIncludeDeleted(r)
+	if err != nil {
+		h.log(r).Error("Некорректный параметр include_deleted", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректный параметр include_deleted"}`, http.StatusBadRequest)
+		return
 	}
-	if gender := r.URL.Query().Get("gender"); gender != "" {
-		filters["gender"] = gender
+	if includeDeleted {
+		filters["include_deleted"] = "true"
 	}
-	if nationality := r.URL.Query().Get("nationality"); nationality != "" {
-		filters["nationality"] = nationality
+
+	fields, expand, ok := h.parseFieldsParams(w, r)
+	if !ok {
+		return
 	}
 
-	persons, err := h.service.List(r.Context(), limit, offset, filters)
+	persons, err := h.service.List(r.Context(), limit, offset, filters, selectFields(fields, expand))
+	if errors.Is(err, auth.ErrForbidden) {
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusForbidden)
+		return
+	}
 	if err != nil {
-		h.logger.Error("Ошибка получения списка", logger.ErrorKV("error", err))
+		h.log(r).Error("Ошибка получения списка", logger.ErrorKV("error", err))
 		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
 		return
 	}
 
-	// Проверяем, пустой ли список
-	if len(persons) == 0 {
-		h.logger.Info("Список записей пуст")
+	// Проверяем, пустой ли список; сообщение вместо списка отправляется только в JSON
+	if len(persons) == 0 && c.mediaType == mediaTypeJSON {
+		h.log(r).Info("Список записей пуст")
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(map[string]string{"message": "В базе данных нет записей"}); err != nil {
-			h.logger.Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
+			h.log(r).Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
 			http.Error(w, `{"error": "Внутренняя ошибка сервера"}`, http.StatusInternalServerError)
 		}
 		return
 	}
 
-	h.logger.Info("Список записей получен", logger.InfoKV("count", len(persons)))
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(persons); err != nil {
-		h.logger.Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
+	views, err := h.personViews(r.Context(), persons, fields, expand)
+	if errors.Is(err, auth.ErrForbidden) {
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		h.log(r).Error("Ошибка раскрытия связанных данных", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Внутренняя ошибка сервера"}`, http.StatusInternalServerError)
+		return
+	}
+
+	h.log(r).Info("Список записей получен", logger.InfoKV("count", len(persons)))
+	w.Header().Set("Content-Type", c.contentType)
+	w.Header().Set("Vary", "Accept")
+	if err := c.encodeList(w, views); err != nil {
+		h.log(r).Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
 		http.Error(w, `{"error": "Внутренняя ошибка сервера"}`, http.StatusInternalServerError)
 	}
 }
+
+// parseFilters возвращает фильтры списка из параметров запроса.
+func parseFilters(r *http.Request) map[string]string {
+	filters := map[string]string{}
+	for _, key := range []string{"name", "surname", "patronymic", "age", "gender", "nationality"} {
+		if value := r.URL.Query().Get(key); value != "" {
+			filters[key] = value
+		}
+	}
+	return filters
+}
+
+// parseFieldsParams разбирает параметры fields и expand и отвечает 400 при ошибке.
+func (h *Handler) parseFieldsParams(w http.ResponseWriter, r *http.Request) ([]string, []string, bool) {
+	fields, err := models.ParseFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		h.log(r).Error("Некорректный параметр fields", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		return nil, nil, false
+	}
+	expand, err := h.service.ParseExpand(r.URL.Query().Get("expand"))
+	if err != nil {
+		h.log(r).Error("Некорректный параметр expand", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		return nil, nil, false
+	}
+	return fields, expand, true
+}
+
+// selectFields возвращает поля, читаемые из базы: раскрытиям нужен ID записи, даже если он не выводится.
+func selectFields(fields, expand []string) []string {
+	if len(expand) > 0 {
+		return models.WithField(fields, "id")
+	}
+	return fields
+}
+
+// parseIncludeDeleted разбирает параметр include_deleted; по умолчанию удалённые записи скрыты.
+func parseIncludeDeleted(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("include_deleted")
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"person-service/internal/auth"
+	"person-service/internal/importer"
+	"person-service/internal/ratelimit"
+	"person-service/pkg/logger"
+	"strconv"
+)
+
+// contentTypeXLSX — MIME-тип книги Excel.
+const contentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// ImportPersons импортирует записи из файла CSV или XLSX.
+// @Summary Импортировать записи о людях из CSV или XLSX
+// @Description Принимает файл в поле file формы multipart/form-data или в теле запроса (text/csv, XLSX).
+// @Description Заголовок определяется автоматически по названиям колонок (name/имя, surname/фамилия, patronymic/отчество); без заголовка колонки идут в порядке имя, фамилия, отчество.
+// @Description CSV читается потоково в UTF-8 или Windows-1251, записи сохраняются пакетами. Некорректные строки попадают в отчёт и не мешают импорту остальных.
+// @Description Если импорт прерван после сохранения части пакетов, ответ с кодом ошибки содержит отчёт о сохранённых записях и поле error.
+// @Description С enrich=true каждое имя вне кэша обогащения расходует токен лимита create; строки с именами, на которые токенов не хватило, попадают в отчёт с ошибкой,
+// @Description а пакет, в котором не хватило ни на одно имя, прерывает импорт с 429.
+// @Tags persons
+// @Accept mpfd
+// @Accept text/csv
+// @Produce json
+// @Produce text/csv
+// @Param file formData file false "Импортируемый файл"
+// @Param format query string false "Формат файла (csv, xlsx); по умолчанию определяется по имени файла или Content-Type"
+// @Param encoding query string false "Кодировка CSV (auto, utf-8, windows-1251)" default(auto)
+// @Param mapping query string false "Сопоставление колонок, например name=Имя,surname=2"
+// @Param enrich query bool false "Обогатить данные через внешние API" default(false)
+// @Param report query string false "Формат отчёта (json, csv)" default(json)
+// @Success 200 {object} importer.Report "Отчёт об импорте"
+// @Failure 400 {object} importer.Report "Некорректный файл или параметры; отчёт, если часть записей уже сохранена"
+// @Failure 401 {object} map[string]string "Не переданы или недействительны учётные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав для операции"
+// @Failure 413 {object} importer.Report "Файл слишком большой; отчёт, если часть записей уже сохранена"
+// @Failure 429 {object} importer.Report "Превышен лимит запросов, повторите после паузы из Retry-After; отчёт, если часть записей уже сохранена"
+// @Failure 500 {object} importer.Report "Внутренняя ошибка сервера; отчёт, если часть записей уже сохранена"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/persons:import [post]
+func (h *Handler) ImportPersons(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	opts := importer.Options{Encoding: query.Get("encoding")}
+	mapping, err := importer.ParseMapping(query.Get("mapping"))
+	if err != nil {
+		h.log(r).Error("Некорректное сопоставление колонок", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+	opts.Mapping = mapping
+	if value := query.Get("enrich"); value != "" {
+		if opts.Enrich, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, `{"error": "Некорректный параметр enrich"}`, http.StatusBadRequest)
+			return
+		}
+	}
+	reportFormat := query.Get("report")
+	if reportFormat != "" && reportFormat != "json" && reportFormat != "csv" {
+		http.Error(w, `{"error": "Некорректный параметр report, ожидается json или csv"}`, http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.cfg.ImportMaxBytes)
+	file, filename, err := importSource(r)
+	if err != nil {
+		h.log(r).Error("Ошибка чтения загружаемого файла", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	opts.Format, err = importFormat(query.Get("format"), filename, r.Header.Get("Content-Type"))
+	if err != nil {
+		h.log(r).Error("Не удалось определить формат файла", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.importer.Import(r.Context(), file, opts)
+	status := http.StatusOK
+	if err != nil {
+		h.log(r).Error("Ошибка импорта", logger.ErrorKV("error", err))
+		var maxBytesErr *http.MaxBytesError
+		var limitErr *ratelimit.LimitedError
+		message := err.Error()
+		switch {
+		case errors.As(err, &maxBytesErr):
+			status = http.StatusRequestEntityTooLarge
+			message = fmt.Sprintf("Файл больше %d байт", h.cfg.ImportMaxBytes)
+		case errors.As(err, &limitErr):
+			status = http.StatusTooManyRequests
+			setRetryAfter(w, limitErr)
+		case errors.Is(err, importer.ErrInvalidFile):
+			status = http.StatusBadRequest
+		case errors.Is(err, auth.ErrForbidden):
+			status = http.StatusForbidden
+		default:
+			status = http.StatusInternalServerError
+		}
+		// Пока ни один пакет не сохранён, отвечаем обычной ошибкой; иначе отчёт нужен клиенту, чтобы узнать
+		// ID уже созданных записей и продолжить импорт с первой несохранённой строки
+		if report == nil || report.Rows == 0 {
+			http.Error(w, fmt.Sprintf(`{"error": "%s"}`, message), status)
+			return
+		}
+		report.Error = message
+	}
+
+	if reportFormat == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="import-report.csv"`)
+		w.WriteHeader(status)
+		if err := report.WriteCSV(w); err != nil {
+			h.log(r).Error("Ошибка записи отчёта", logger.ErrorKV("error", err))
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		h.log(r).Error("Ошибка кодирования ответа", logger.ErrorKV("error", err))
+	}
+}
+
+// importSource возвращает поток импортируемого файла и его имя.
+// Для multipart/form-data используется поле file, иначе — тело запроса.
+func importSource(r *http.Request) (io.Reader, string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, "", nil
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", fmt.Errorf("некорректная форма: %w", err)
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, "", fmt.Errorf("в форме нет поля file")
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("некорректная форма: %w", err)
+		}
+		if part.FormName() == "file" {
+			return part, part.FileName(), nil
+		}
+	}
+}
+
+// importFormat определяет формат файла по параметру format, имени файла или Content-Type.
+func importFormat(format, filename, contentType string) (importer.Format, error) {
+	if format != "" {
+		return importer.ParseFormat(format)
+	}
+	if filename != "" {
+		return importer.FormatFromFilename(filename)
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return importer.FormatCSV, nil
+	case contentTypeXLSX:
+		return importer.FormatXLSX, nil
+	}
+	return "", fmt.Errorf("не удалось определить формат файла, укажите параметр format")
+}
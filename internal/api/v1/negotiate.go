@@ -0,0 +1,258 @@
+package v1
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"person-service/internal/exporter"
+	"person-service/pkg/logger"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Медиа-типы представлений записей.
+const (
+	mediaTypeJSON    = "application/json"
+	mediaTypeXML     = "application/xml"
+	mediaTypeMsgPack = "application/msgpack"
+	mediaTypeCSV     = "text/csv"
+)
+
+// errUnsupportedMediaType возвращается, если представление нельзя использовать для тела запроса.
+var errUnsupportedMediaType = errors.New("неподдерживаемый Content-Type")
+
+// codec кодирует записи в одно из представлений и декодирует из него тела запросов.
+type codec struct {
+	mediaType string
+	// aliases перечисляет другие медиа-типы того же представления.
+	aliases []string
+	// contentType задаёт заголовок Content-Type ответа.
+	contentType  string
+	encodePerson func(w io.Writer, view personView) error
+	encodeList   func(w io.Writer, views []personView) error
+	// decode равен nil, если представление поддерживается только в ответах.
+	decode func(r io.Reader, v any) error
+}
+
+// matches проверяет, относится ли медиа-тип к представлению.
+func (c *codec) matches(mediaType string) bool {
+	if mediaType == c.mediaType {
+		return true
+	}
+	for _, alias := range c.aliases {
+		if mediaType == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// xmlPersonList задаёт корневой элемент XML-представления списка.
+type xmlPersonList struct {
+	XMLName xml.Name     `xml:"persons"`
+	Persons []personView `xml:"person"`
+}
+
+// codecs перечисляет поддерживаемые представления; первое используется по умолчанию.
+var codecs = []*codec{
+	{
+		mediaType:   mediaTypeJSON,
+		contentType: mediaTypeJSON,
+		encodePerson: func(w io.Writer, view personView) error {
+			return json.NewEncoder(w).Encode(view)
+		},
+		encodeList: func(w io.Writer, views []personView) error {
+			return json.NewEncoder(w).Encode(views)
+		},
+		decode: func(r io.Reader, v any) error {
+			return json.NewDecoder(r).Decode(v)
+		},
+	},
+	{
+		mediaType:   mediaTypeXML,
+		aliases:     []string{"text/xml"},
+		contentType: mediaTypeXML + "; charset=utf-8",
+		encodePerson: func(w io.Writer, view personView) error {
+			if _, err := io.WriteString(w, xml.Header); err != nil {
+				return err
+			}
+			return xml.NewEncoder(w).EncodeElement(view, xml.StartElement{Name: xml.Name{Local: "person"}})
+		},
+		encodeList: func(w io.Writer, views []personView) error {
+			if _, err := io.WriteString(w, xml.Header); err != nil {
+				return err
+			}
+			return xml.NewEncoder(w).Encode(xmlPersonList{Persons: views})
+		},
+		decode: func(r io.Reader, v any) error {
+			return xml.NewDecoder(r).Decode(v)
+		},
+	},
+	{
+		mediaType:   mediaTypeMsgPack,
+		aliases:     []string{"application/x-msgpack", "application/vnd.msgpack"},
+		contentType: mediaTypeMsgPack,
+		encodePerson: func(w io.Writer, view personView) error {
+			return newMsgPackEncoder(w).Encode(view)
+		},
+		encodeList: func(w io.Writer, views []personView) error {
+			return newMsgPackEncoder(w).Encode(views)
+		},
+		decode: func(r io.Reader, v any) error {
+			decoder := msgpack.NewDecoder(r)
+			decoder.SetCustomStructTag("json")
+			return decoder.Decode(v)
+		},
+	},
+	{
+		mediaType:   mediaTypeCSV,
+		contentType: mediaTypeCSV + "; charset=utf-8",
+		encodePerson: func(w io.Writer, view personView) error {
+			return encodeCSV(w, []personView{view})
+		},
+		encodeList: encodeCSV,
+	},
+}
+
+// newMsgPackEncoder создаёт кодировщик MessagePack с именами полей из JSON-тегов.
+func newMsgPackEncoder(w io.Writer) *msgpack.Encoder {
+	encoder := msgpack.NewEncoder(w)
+	encoder.SetCustomStructTag("json")
+	return encoder
+}
+
+// encodeCSV записывает записи в CSV с заголовком, как при выгрузке.
+// Раскрытия в CSV не выводятся.
+func encodeCSV(w io.Writer, views []personView) error {
+	var fields []string
+	if len(views) > 0 {
+		fields = views[0].fields
+	}
+	writer, err := exporter.NewCSVWriter(w, fields)
+	if err != nil {
+		return err
+	}
+	for _, view := range views {
+		if err := writer.Write(view.person); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// acceptRange представляет один диапазон медиа-типов из заголовка Accept.
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+// negotiateCodec выбирает представление ответа по заголовку Accept.
+// Без заголовка или для */* выбирается JSON; nil означает, что подходящего представления нет.
+func negotiateCodec(accept string) *codec {
+	if strings.TrimSpace(accept) == "" {
+		return codecs[0]
+	}
+
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	for _, ar := range ranges {
+		switch ar.mediaType {
+		case "*/*", "application/*":
+			return codecs[0]
+		case "text/*":
+			return codecFor(mediaTypeCSV)
+		}
+		if c := codecFor(ar.mediaType); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// codecFor возвращает представление для медиа-типа или nil.
+func codecFor(mediaType string) *codec {
+	for _, c := range codecs {
+		if c.matches(mediaType) {
+			return c
+		}
+	}
+	return nil
+}
+
+// requestCodec выбирает представление тела запроса по Content-Type; без заголовка тело читается как JSON.
+func requestCodec(r *http.Request) (*codec, error) {
+	header := r.Header.Get("Content-Type")
+	if header == "" {
+		return codecs[0], nil
+	}
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedMediaType, header)
+	}
+	c := codecFor(mediaType)
+	if c == nil || c.decode == nil {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedMediaType, mediaType)
+	}
+	return c, nil
+}
+
+// responseCodec выбирает представление ответа и отвечает 406, если подходящего нет.
+func (h *Handler) responseCodec(w http.ResponseWriter, r *http.Request) (*codec, bool) {
+	c := negotiateCodec(r.Header.Get("Accept"))
+	if c == nil {
+		h.log(r).Error("Неподдерживаемый Accept", logger.ErrorKV("accept", r.Header.Get("Accept")))
+		http.Error(w, fmt.Sprintf(`{"error": "Неподдерживаемый Accept, ожидается %s"}`, supportedMediaTypes()), http.StatusNotAcceptable)
+		return nil, false
+	}
+	return c, true
+}
+
+// decodeBody декодирует тело запроса по Content-Type и отвечает 415 или 400 при ошибке.
+func (h *Handler) decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	c, err := requestCodec(r)
+	if err != nil {
+		h.log(r).Error("Неподдерживаемый Content-Type", logger.ErrorKV("error", err))
+		http.Error(w, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusUnsupportedMediaType)
+		return false
+	}
+	if err := c.decode(r.Body, v); err != nil {
+		h.log(r).Error("Ошибка декодирования запроса", logger.ErrorKV("error", err))
+		http.Error(w, `{"error": "Некорректное тело запроса"}`, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// supportedMediaTypes перечисляет медиа-типы ответов через запятую.
+func supportedMediaTypes() string {
+	types := make([]string, 0, len(codecs))
+	for _, c := range codecs {
+		types = append(types, c.mediaType)
+	}
+	return strings.Join(types, ", ")
+}
@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"person-service/internal/models"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// expansion содержит связанные данные, раскрытые через параметр expand.
+type expansion struct {
+	name  string
+	value any
+}
+
+// personView описывает запись в ответе: выбранные поля и раскрытые связанные данные.
+type personView struct {
+	person *models.Person
+	// fields перечисляет выводимые поля, nil означает все поля.
+	fields   []string
+	expanded []expansion
+}
+
+// fullView возвращает представление записи со всеми полями.
+func fullView(person *models.Person) personView {
+	return personView{person: person}
+}
+
+// sparse сообщает, отличается ли представление от полной записи.
+func (v personView) sparse() bool {
+	return v.fields != nil || len(v.expanded) > 0
+}
+
+// outputFields возвращает выводимые поля.
+func (v personView) outputFields() []string {
+	if v.fields == nil {
+		return models.PersonFields
+	}
+	return v.fields
+}
+
+// MarshalJSON выводит выбранные поля в порядке models.PersonFields, затем раскрытия.
+func (v personView) MarshalJSON() ([]byte, error) {
+	if !v.sparse() {
+		return json.Marshal(v.person)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	write := func(name string, value any) error {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(data)
+		return nil
+	}
+	for _, field := range v.outputFields() {
+		if err := write(field, v.person.FieldValue(field)); err != nil {
+			return nil, err
+		}
+	}
+	for _, exp := range v.expanded {
+		if err := write(exp.name, exp.value); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalXML выводит выбранные поля элементами start; пустые поля пропускаются.
+func (v personView) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if !v.sparse() {
+		return e.EncodeElement(v.person, start)
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, field := range v.outputFields() {
+		value := v.person.FieldValue(field)
+		if value == nil {
+			continue
+		}
+		if err := e.EncodeElement(value, xml.StartElement{Name: xml.Name{Local: field}}); err != nil {
+			return err
+		}
+	}
+	for _, exp := range v.expanded {
+		if exp.value == nil {
+			continue
+		}
+		if err := e.EncodeElement(exp.value, xml.StartElement{Name: xml.Name{Local: exp.name}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// EncodeMsgpack выводит выбранные поля и раскрытия в виде map.
+func (v personView) EncodeMsgpack(enc *msgpack.Encoder) error {
+	if !v.sparse() {
+		return enc.Encode(v.person)
+	}
+	fields := v.outputFields()
+	if err := enc.EncodeMapLen(len(fields) + len(v.expanded)); err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if err := enc.EncodeString(field); err != nil {
+			return err
+		}
+		if err := enc.Encode(v.person.FieldValue(field)); err != nil {
+			return err
+		}
+	}
+	for _, exp := range v.expanded {
+		if err := enc.EncodeString(exp.name); err != nil {
+			return err
+		}
+		if err := enc.Encode(exp.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// personViews строит представления записей с полями fields и раскрытиями expand.
+func (h *Handler) personViews(ctx context.Context, persons []*models.Person, fields, expand []string) ([]personView, error) {
+	expanded, err := h.service.Expand(ctx, persons, expand)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]personView, len(persons))
+	for i, person := range persons {
+		views[i] = personView{person: person, fields: fields}
+		for _, name := range expand {
+			views[i].expanded = append(views[i].expanded, expansion{name: name, value: expanded[name][person.ID]})
+		}
+	}
+	return views, nil
+}
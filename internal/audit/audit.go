@@ -0,0 +1,33 @@
+package audit
+
+import "context"
+
+// Источники изменений, записываемые в историю.
+const (
+	SourceAPI     = "api"
+	SourceJob     = "job"
+	SourceCLI     = "cli"
+	SourceGraphQL = "graphql"
+	SourceGRPC    = "grpc"
+)
+
+// Meta описывает, кто и откуда выполняет изменение записи.
+type Meta struct {
+	Actor     string
+	RequestID string
+	Source    string
+}
+
+type metaKey struct{}
+
+// WithMeta возвращает контекст с метаданными аудита.
+func WithMeta(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, meta)
+}
+
+// FromContext возвращает метаданные аудита из контекста.
+// Если они не заданы, возвращаются пустые метаданные.
+func FromContext(ctx context.Context) Meta {
+	meta, _ := ctx.Value(metaKey{}).(Meta)
+	return meta
+}
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"person-service/internal/config"
+	"person-service/internal/repository"
+	"person-service/internal/tenant"
+	"person-service/pkg/logger"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// apiKeyTouchInterval ограничивает частоту записи last_used_at для ключа API.
+const apiKeyTouchInterval = time.Minute
+
+// apiKeyBytes — число случайных байт в ключе API, созданном GenerateAPIKey.
+const apiKeyBytes = 32
+
+// ErrUnauthenticated возвращается, если учётные данные не переданы, некорректны или истекли.
+var ErrUnauthenticated = errors.New("требуется аутентификация")
+
+// staticKey — ключ API из настроек.
+type staticKey struct {
+	name   string
+	hash   [sha256.Size]byte
+	roles  []string
+	tenant string
+}
+
+// Authenticator проверяет JWT и ключи API.
+type Authenticator struct {
+	secret      []byte
+	jwks        *jwks
+	parser      *jwt.Parser
+	rolesClaim  string
+	tenantClaim string
+	staticKeys  []staticKey
+	repo        repository.APIKeyRepository
+	logger      *zap.Logger
+}
+
+// NewAuthenticator создаёт Authenticator по настройкам; JWKS загружается сразу.
+// JWT принимаются, только если задан секрет HS256 или источник JWKS для RS256.
+func NewAuthenticator(ctx context.Context, cfg *config.Auth, repo repository.APIKeyRepository, logger *zap.Logger) (*Authenticator, error) {
+	a := &Authenticator{rolesClaim: cfg.RolesClaim, tenantClaim: cfg.TenantClaim, repo: repo, logger: logger}
+
+	var methods []string
+	if cfg.JWTSecret != "" {
+		a.secret = []byte(cfg.JWTSecret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.JWKSFile != "" || cfg.JWKSURL != "" {
+		keys, err := newJWKS(ctx, cfg.JWKSFile, cfg.JWKSURL, cfg.JWKSRefreshInterval)
+		if err != nil {
+			return nil, err
+		}
+		a.jwks = keys
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) > 0 {
+		opts := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired(), jwt.WithLeeway(30 * time.Second)}
+		if cfg.Issuer != "" {
+			opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+		}
+		if cfg.Audience != "" {
+			opts = append(opts, jwt.WithAudience(cfg.Audience))
+		}
+		a.parser = jwt.NewParser(opts...)
+	}
+
+	for _, entry := range cfg.APIKeys {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 4)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("статический ключ API должен иметь вид name:key[:role1|role2[:tenant]]")
+		}
+		key := staticKey{name: parts[0], hash: sha256.Sum256([]byte(parts[1]))}
+		if len(parts) >= 3 && parts[2] != "" {
+			key.roles = strings.Split(parts[2], "|")
+		}
+		if len(parts) == 4 {
+			if err := tenant.Validate(parts[3]); err != nil {
+				return nil, fmt.Errorf("статический ключ API %s: %w", parts[0], err)
+			}
+			key.tenant = parts[3]
+		}
+		a.staticKeys = append(a.staticKeys, key)
+	}
+	return a, nil
+}
+
+// AuthenticateToken проверяет подпись, срок действия, издателя и аудиторию JWT.
+func (a *Authenticator) AuthenticateToken(ctx context.Context, token string) (*Principal, error) {
+	if a.parser == nil {
+		return nil, fmt.Errorf("%w: проверка JWT не настроена", ErrUnauthenticated)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		if t.Method == jwt.SigningMethodHS256 {
+			return a.secret, nil
+		}
+		kid, _ := t.Header["kid"].(string)
+		return a.jwks.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: недействительный токен: %v", ErrUnauthenticated, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: токен не содержит sub", ErrUnauthenticated)
+	}
+	tenantID, _ := claims[a.tenantClaim].(string)
+	if tenantID != "" {
+		if err := tenant.Validate(tenantID); err != nil {
+			return nil, fmt.Errorf("%w: claim %s: %v", ErrUnauthenticated, a.tenantClaim, err)
+		}
+	}
+	return &Principal{Subject: subject, Method: MethodJWT, Roles: claimStrings(claims[a.rolesClaim]), Tenant: tenantID}, nil
+}
+
+// GenerateAPIKey создаёт случайный ключ API в виде base64url без дополнения.
+func GenerateAPIKey() (string, error) {
+	key := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("не удалось создать ключ API: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(key), nil
+}
+
+// HashAPIKey возвращает SHA-256 ключа в шестнадцатеричном виде, как он хранится в api_keys.key_hash.
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// AuthenticateAPIKey проверяет ключ API среди статических ключей и ключей в базе.
+func (a *Authenticator) AuthenticateAPIKey(ctx context.Context, key string) (*Principal, error) {
+	hash := sha256.Sum256([]byte(key))
+
+	// Сравниваются все статические ключи, чтобы время проверки не зависело от позиции совпадения
+	var matched *staticKey
+	for i := range a.staticKeys {
+		if subtle.ConstantTimeCompare(hash[:], a.staticKeys[i].hash[:]) == 1 {
+			matched = &a.staticKeys[i]
+		}
+	}
+	if matched != nil {
+		return &Principal{Subject: "api_key:" + matched.name, Method: MethodAPIKey, Roles: matched.roles, Tenant: matched.tenant}, nil
+	}
+
+	stored, err := a.repo.GetByHash(ctx, hex.EncodeToString(hash[:]))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if stored == nil || !stored.Active(now) {
+		return nil, fmt.Errorf("%w: недействительный ключ API", ErrUnauthenticated)
+	}
+	if err := a.repo.Touch(ctx, stored.ID, now, now.Add(-apiKeyTouchInterval)); err != nil {
+		a.logger.Warn("Ошибка отметки использования ключа API", logger.ErrorKV("error", err))
+	}
+	principal := &Principal{Subject: "api_key:" + stored.Name, Method: MethodAPIKey, Roles: stored.Roles}
+	if stored.TenantID != nil {
+		principal.Tenant = *stored.TenantID
+	}
+	return principal, nil
+}
+
+// claimStrings приводит claim со строкой или массивом строк к срезу.
+func claimStrings(claim any) []string {
+	switch v := claim.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
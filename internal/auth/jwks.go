@@ -0,0 +1,205 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// jwksMinRefresh ограничивает частоту внеочередного перечитывания JWKS при неизвестном kid.
+const jwksMinRefresh = 30 * time.Second
+
+// maxJWKSBytes ограничивает размер загружаемого JWKS.
+const maxJWKSBytes = 1 << 20
+
+// jwks хранит открытые ключи RSA из файла или URL и периодически перечитывает их.
+// Перечитывание выполняется вне блокировки и одно на все запросы: проверки с известными ключами
+// продолжают использовать прежний набор, пока загружается новый.
+type jwks struct {
+	file            string
+	url             string
+	refreshInterval time.Duration
+	client          *http.Client
+	group           singleflight.Group
+
+	mu        sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+	// attemptedAt — время последней попытки перечитывания, в том числе неудачной.
+	attemptedAt time.Time
+	// refreshing сообщает, что набор перечитывается и к загрузке можно присоединиться.
+	refreshing bool
+}
+
+// newJWKS создаёт набор ключей и сразу загружает его, чтобы ошибка настройки обнаружилась при запуске.
+func newJWKS(ctx context.Context, file, url string, refreshInterval time.Duration) (*jwks, error) {
+	j := &jwks{
+		file:            file,
+		url:             url,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: 10 * time.Second},
+	}
+	if err := j.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// key возвращает ключ с идентификатором kid; пустой kid допустим, если ключ в наборе один.
+// Устаревший набор перечитывается в фоне, а проверка использует прежний. Неизвестный kid перечитывает набор
+// не чаще jwksMinRefresh; запрос ждёт перечитывания, пока не отменён ctx, но отмена не прерывает загрузку
+// для остальных. При ошибке загрузки используется прежний набор.
+func (j *jwks) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	j.mu.RLock()
+	key, known := j.lookup(kid)
+	stale := time.Since(j.fetchedAt) > j.refreshInterval
+	retry := j.refreshing || time.Since(j.attemptedAt) > jwksMinRefresh
+	j.mu.RUnlock()
+
+	switch {
+	case known:
+		if stale && retry {
+			j.group.DoChan("refresh", j.refreshBackground)
+		}
+		return key, nil
+	case retry:
+		select {
+		case <-j.group.DoChan("refresh", j.refreshBackground):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		j.mu.RLock()
+		key, known = j.lookup(kid)
+		j.mu.RUnlock()
+		if known {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("ключ %q не найден в JWKS", kid)
+}
+
+// lookup ищет ключ в текущем наборе; вызывается под j.mu.
+func (j *jwks) lookup(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+	key, ok := j.keys[kid]
+	return key, ok
+}
+
+// refreshBackground перечитывает набор ключей в контексте, не связанном с запросом, который её вызвал;
+// время загрузки по URL ограничено таймаутом клиента.
+func (j *jwks) refreshBackground() (any, error) {
+	// Ошибка не прерывает проверку: ключи прежнего набора остаются действительными
+	return nil, j.refresh(context.Background())
+}
+
+// refresh загружает набор ключей и заменяет текущий; j.mu удерживается только на время замены.
+func (j *jwks) refresh(ctx context.Context) error {
+	j.mu.Lock()
+	j.attemptedAt = time.Now()
+	j.refreshing = true
+	j.mu.Unlock()
+
+	keys, err := j.fetch(ctx)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.refreshing = false
+	if err != nil {
+		return err
+	}
+	j.keys = keys
+	j.fetchedAt = time.Now()
+	return nil
+}
+
+// fetch загружает и разбирает набор ключей.
+func (j *jwks) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	data, err := j.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parseJWKS(data)
+}
+
+// load читает JWKS из файла или по URL.
+func (j *jwks) load(ctx context.Context) ([]byte, error) {
+	if j.file != "" {
+		data, err := os.ReadFile(j.file)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось прочитать JWKS: %w", err)
+		}
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось создать запрос JWKS: %w", err)
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось загрузить JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("не удалось загрузить JWKS: статус %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSBytes))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать JWKS: %w", err)
+	}
+	return data, nil
+}
+
+// parseJWKS разбирает ключи RSA для подписи; ключи других типов пропускаются.
+func parseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("некорректный JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("некорректный модуль ключа %q в JWKS: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("некорректная экспонента ключа %q в JWKS: %w", k.Kid, err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("некорректная экспонента ключа %q в JWKS", k.Kid)
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS не содержит ключей RSA для подписи")
+	}
+	return keys, nil
+}
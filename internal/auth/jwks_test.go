@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// jwksDocument возвращает JWKS с открытым ключом key под каждым из идентификаторов kids.
+func jwksDocument(t *testing.T, key *rsa.PublicKey, kids ...string) []byte {
+	t.Helper()
+	type jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	for _, kid := range kids {
+		set.Keys = append(set.Keys, jwk{
+			Kty: "RSA",
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestJWKSRefreshDoesNotBlockOrFollowRequestContext(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	initial := jwksDocument(t, &private.PublicKey, "old")
+	rotated := jwksDocument(t, &private.PublicKey, "old", "new")
+
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Write(initial)
+			return
+		}
+		<-release
+		w.Write(rotated)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	j, err := newJWKS(context.Background(), "", server.URL, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Неизвестный kid запускает перечитывание; запрос отменяется, пока JWKS отвечает
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	j.mu.Lock()
+	j.attemptedAt = time.Time{}
+	j.mu.Unlock()
+	if _, err := j.key(ctx, "new"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ожидалась отмена ожидания по контексту запроса, получено %v", err)
+	}
+
+	// Пока набор перечитывается, известный ключ выдаётся без ожидания
+	done := make(chan error, 1)
+	go func() {
+		_, err := j.key(context.Background(), "old")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("известный ключ не найден: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("проверка известного ключа ждёт перечитывания JWKS")
+	}
+
+	// Отмена запроса не прервала загрузку: после ответа JWKS новый ключ доступен
+	release <- struct{}{}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		j.mu.RLock()
+		_, ok := j.lookup("new")
+		j.mu.RUnlock()
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("новый ключ не загружен после отмены запроса, запустившего перечитывание")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("ожидалось 2 загрузки JWKS, выполнено %d", got)
+	}
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"person-service/internal/tenant"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+// Permission — право на операцию с записями.
+type Permission string
+
+// Права на операции с записями.
+const (
+	// PermRead разрешает читать записи, списки, выгрузку и поиск дубликатов.
+	PermRead Permission = "persons:read"
+	// PermWrite разрешает создавать и изменять записи.
+	PermWrite Permission = "persons:write"
+	// PermDelete разрешает удалять, восстанавливать и сливать записи.
+	PermDelete Permission = "persons:delete"
+	// PermBulk разрешает пакетное создание, импорт и массовые изменения по фильтру.
+	PermBulk Permission = "persons:bulk"
+	// PermHistory разрешает читать историю изменений и состояние записи на момент времени.
+	PermHistory Permission = "persons:history"
+	// PermAnyTenant разрешает учётным данным без привязки к арендатору выбирать арендатора
+	// заголовком X-Tenant-ID или метаданными x-tenant-id.
+	PermAnyTenant Permission = "persons:any_tenant"
+)
+
+// permissions перечисляет все известные права.
+var permissions = []Permission{PermRead, PermWrite, PermDelete, PermBulk, PermHistory, PermAnyTenant}
+
+// DefaultRoles — роли по умолчанию, если auth.roles не задан.
+var DefaultRoles = map[string][]string{
+	"reader": {string(PermRead)},
+	"editor": {string(PermRead), string(PermWrite)},
+	"admin":  {string(PermRead), string(PermWrite), string(PermDelete), string(PermBulk), string(PermHistory)},
+}
+
+// ErrForbidden возвращается, если у клиента нет права на операцию.
+var ErrForbidden = errors.New("недостаточно прав")
+
+// Policy сопоставляет роли клиентов с правами.
+type Policy struct {
+	roles  map[string][]Permission
+	logger *zap.Logger
+}
+
+// NewPolicy создаёт политику из ролей и их прав; пустой roles означает DefaultRoles.
+func NewPolicy(roles map[string][]string, logger *zap.Logger) (*Policy, error) {
+	if len(roles) == 0 {
+		roles = DefaultRoles
+	}
+	p := &Policy{roles: make(map[string][]Permission, len(roles)), logger: logger}
+	for role, perms := range roles {
+		for _, perm := range perms {
+			if !slices.Contains(permissions, Permission(perm)) {
+				return nil, fmt.Errorf("роль %s: неизвестное право %q", role, perm)
+			}
+			p.roles[role] = append(p.roles[role], Permission(perm))
+		}
+	}
+	return p, nil
+}
+
+// HasRole сообщает, известна ли политике роль.
+func (p *Policy) HasRole(role string) bool {
+	_, ok := p.roles[role]
+	return ok
+}
+
+// Allowed сообщает, есть ли право perm хотя бы у одной из ролей клиента.
+func (p *Policy) Allowed(principal *Principal, perm Permission) bool {
+	for _, role := range principal.Roles {
+		if slices.Contains(p.roles[role], perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// BoundTenant возвращает арендатора, которым ограничен клиент: из учётных данных, а если они не привязаны
+// к арендатору и у клиента нет права PermAnyTenant — tenant.Default. Пустая строка означает, что клиент
+// выбирает арендатора сам: у него есть PermAnyTenant или аутентификация отключена.
+func (p *Policy) BoundTenant(principal *Principal) string {
+	switch {
+	case principal == nil:
+		return ""
+	case principal.Tenant != "":
+		return principal.Tenant
+	case p.Allowed(principal, PermAnyTenant):
+		return ""
+	default:
+		return tenant.Default
+	}
+}
+
+// Authorize проверяет право клиента из контекста на операцию и логирует отказ.
+// Вызовы без клиента в контексте — фоновые задачи, CLI и работа с отключённой аутентификацией — разрешены.
+func (p *Policy) Authorize(ctx context.Context, perm Permission) error {
+	principal := FromContext(ctx)
+	if principal == nil || p.Allowed(principal, perm) {
+		return nil
+	}
+	p.logger.Warn("Доступ запрещён",
+		zap.String("subject", principal.Subject),
+		zap.String("method", principal.Method),
+		zap.Strings("roles", principal.Roles),
+		zap.String("permission", string(perm)))
+	return fmt.Errorf("%w: требуется право %s", ErrForbidden, perm)
+}
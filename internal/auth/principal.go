@@ -0,0 +1,35 @@
+// Package auth проверяет учётные данные запросов: JWT и ключи API.
+package auth
+
+import "context"
+
+// Способы аутентификации.
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+)
+
+// Principal описывает аутентифицированного клиента.
+type Principal struct {
+	// Subject — claim sub токена или api_key:<имя> для ключа API.
+	Subject string
+	// Method — способ аутентификации: jwt или api_key.
+	Method string
+	// Roles — роли из claim roles токена или из настроек ключа.
+	Roles []string
+	// Tenant — арендатор, к данным которого ограничен клиент; пустая строка разрешает выбирать арендатора в запросе.
+	Tenant string
+}
+
+type principalKey struct{}
+
+// WithPrincipal возвращает контекст с аутентифицированным клиентом.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext возвращает аутентифицированного клиента из контекста или nil.
+func FromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
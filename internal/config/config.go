@@ -2,17 +2,30 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
+// Режимы работы приложения.
+const (
+	EnvDev  = "dev"
+	EnvProd = "prod"
+)
+
 // Database содержит настройки для подключения к базе данных.
 type Database struct {
 	Host     string `mapstructure:"db_host"`
 	Port     string `mapstructure:"db_port"`
 	User     string `mapstructure:"db_user"`
-	Password string `mapstructure:"db_password"`
+	Password string `mapstructure:"db_password" secret:"true"`
 	Name     string `mapstructure:"db_name"`
+	// RowLevelSecurity передаёт арендатора запроса в app.tenant_id каждого соединения,
+	// чтобы политики RLS Postgres ограничивали строки арендатором в дополнение к фильтрам запросов.
+	RowLevelSecurity bool `mapstructure:"row_level_security"`
+	// AutoMigrate применяет встроенные миграции при запуске сервера.
+	AutoMigrate bool `mapstructure:"auto_migrate"`
 }
 
 // APIs содержит URL внешних API для обогащения данных.
@@ -25,14 +38,177 @@ type APIs struct {
 // Server содержит настройки сервера.
 type Server struct {
 	Port string `mapstructure:"server_port"`
+	// GRPCPort задаёт порт gRPC-сервера.
+	GRPCPort string `mapstructure:"grpc_port"`
+	// PutCreateIfAbsent разрешает PUT создавать отсутствующую запись с ID из пути.
+	PutCreateIfAbsent bool `mapstructure:"put_create_if_absent"`
+	// BatchMaxItems ограничивает число записей в одном пакетном запросе.
+	BatchMaxItems int `mapstructure:"batch_max_items"`
+	// BulkMaxRows ограничивает число записей, затрагиваемых массовым изменением или удалением.
+	BulkMaxRows int `mapstructure:"bulk_max_rows"`
+	// ImportMaxBytes ограничивает размер загружаемого для импорта файла.
+	ImportMaxBytes int64 `mapstructure:"import_max_bytes"`
+	// RequestMaxBytes ограничивает размер тела запроса с Idempotency-Key, которое сохраняется в памяти для хэша.
+	RequestMaxBytes int64 `mapstructure:"request_max_bytes"`
+}
+
+// Purge содержит настройки фоновой очистки удалённых записей.
+// Очистка отключена, если Retention не задан.
+type Purge struct {
+	Retention time.Duration `mapstructure:"retention"`
+	Interval  time.Duration `mapstructure:"interval"`
+}
+
+// Duplicates содержит настройки поиска дубликатов.
+type Duplicates struct {
+	// RejectOnCreate запрещает создавать запись, если найден дубликат с оценкой не ниже Threshold.
+	RejectOnCreate bool `mapstructure:"reject_on_create"`
+	// Threshold задаёт минимальную оценку от 0 до 1, при которой запись считается дубликатом.
+	Threshold float64 `mapstructure:"threshold"`
+}
+
+// Enrichment содержит настройки кэша и квот обогащения, которые действуют отдельно для каждого арендатора.
+type Enrichment struct {
+	// CacheTTL задаёт, сколько хранится результат обогащения имени; 0 отключает кэш.
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	// CacheSize ограничивает число имён в кэше одного арендатора.
+	CacheSize int `mapstructure:"cache_size"`
+	// DailyQuota ограничивает число запросов к внешним API в сутки UTC для арендатора; 0 — без ограничения.
+	DailyQuota int `mapstructure:"daily_quota"`
+	// TenantQuotas переопределяет DailyQuota для отдельных арендаторов, например enrichment.tenant_quotas.acme=500.
+	TenantQuotas map[string]int `mapstructure:"tenant_quotas"`
+	// MaxTenants ограничивает число арендаторов, для которых в памяти хранятся кэш и счётчики квоты.
+	MaxTenants int `mapstructure:"max_tenants"`
+}
+
+// Idempotency содержит настройки хранения ключей Idempotency-Key.
+type Idempotency struct {
+	// TTL задаёт, сколько хранится ответ на запрос с ключом.
+	TTL time.Duration `mapstructure:"ttl"`
+	// CleanupInterval задаёт период удаления истёкших ключей.
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval"`
+	// LockTimeout задаёт, на сколько ключ закрепляется за выполняющимся запросом. Пока запрос выполняется,
+	// срок продлевается; ключ запроса, экземпляр которого завершился, не сохранив ответ, можно занять заново
+	// через LockTimeout.
+	LockTimeout time.Duration `mapstructure:"lock_timeout"`
+}
+
+// Health содержит настройки проверки готовности /readyz.
+type Health struct {
+	// Timeout ограничивает время всех проверок одного запроса.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// CheckEnrichment добавляет проверку доступности API обогащения; их недоступность отмечает сервис
+	// как degraded, но не снимает готовность.
+	CheckEnrichment bool `mapstructure:"check_enrichment"`
+	// DrainDelay задаёт, сколько после сигнала завершения /readyz отвечает 503, прежде чем сервер
+	// перестанет принимать запросы, чтобы балансировщик успел снять с него трафик.
+	DrainDelay time.Duration `mapstructure:"drain_delay"`
+}
+
+// Экспортёры трассировки.
+const (
+	TracingNone   = "none"
+	TracingOTLP   = "otlp"
+	TracingStdout = "stdout"
+	TracingFile   = "file"
+)
+
+// Tracing содержит настройки трассировки OpenTelemetry.
+type Tracing struct {
+	// Exporter — none, otlp (OTLP/gRPC), stdout или file.
+	Exporter string `mapstructure:"exporter"`
+	// Endpoint — адрес коллектора OTLP, например otel-collector:4317.
+	Endpoint string `mapstructure:"endpoint"`
+	// Insecure отключает TLS при подключении к коллектору.
+	Insecure bool `mapstructure:"insecure"`
+	// File — файл, в который exporter=file пишет spans в JSON.
+	File string `mapstructure:"file"`
+	// SampleRatio — доля сохраняемых трассировок от 0 до 1 для запросов без входящего traceparent.
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+// Ограничители частоты запросов.
+const (
+	RateLimitMemory   = "memory"
+	RateLimitPostgres = "postgres"
+)
+
+// RateLimitRule задаёт ведро token bucket: Burst запросов подряд, затем Requests запросов за Period.
+type RateLimitRule struct {
+	Requests int           `mapstructure:"requests"`
+	Period   time.Duration `mapstructure:"period"`
+	Burst    int           `mapstructure:"burst"`
+}
+
+// Rate возвращает скорость пополнения ведра в токенах в секунду.
+func (r RateLimitRule) Rate() float64 {
+	return float64(r.Requests) / r.Period.Seconds()
+}
+
+// RateLimit содержит настройки ограничения частоты запросов по классам маршрутов.
+// Ведра ведутся отдельно для каждого клиента: ключа API, субъекта токена или IP-адреса.
+type RateLimit struct {
+	// Enabled включает ограничение частоты запросов.
+	Enabled bool `mapstructure:"enabled"`
+	// Backend — memory (ведра каждого экземпляра) или postgres (ведра, общие для всех экземпляров).
+	Backend string `mapstructure:"backend"`
+	// TrustProxy разрешает брать IP-адрес клиента из X-Forwarded-For и X-Real-IP.
+	TrustProxy bool `mapstructure:"trust_proxy"`
+	// Read — чтение, Write — изменение и удаление, Create — создание с платным обогащением,
+	// Bulk — пакетное создание, импорт и массовые операции.
+	Read   RateLimitRule `mapstructure:"read"`
+	Write  RateLimitRule `mapstructure:"write"`
+	Create RateLimitRule `mapstructure:"create"`
+	Bulk   RateLimitRule `mapstructure:"bulk"`
+}
+
+// Auth содержит настройки аутентификации по JWT и ключам API.
+// Ключи API из таблицы api_keys принимаются всегда, пока аутентификация не отключена.
+type Auth struct {
+	// Disabled отключает аутентификацию; допустимо только для локальной разработки.
+	Disabled bool `mapstructure:"disabled"`
+	// JWTSecret включает проверку JWT с подписью HS256.
+	JWTSecret string `mapstructure:"jwt_secret" secret:"true"`
+	// JWKSFile и JWKSURL задают источник открытых ключей RS256 в формате JWKS; допустим только один.
+	JWKSFile string `mapstructure:"jwks_file"`
+	JWKSURL  string `mapstructure:"jwks_url"`
+	// JWKSRefreshInterval задаёт период перечитывания JWKS.
+	JWKSRefreshInterval time.Duration `mapstructure:"jwks_refresh_interval"`
+	// Issuer и Audience, если заданы, должны совпадать с claims iss и aud токена.
+	Issuer   string `mapstructure:"issuer"`
+	Audience string `mapstructure:"audience"`
+	// APIKeys перечисляет статические ключи в виде name:key[:role1|role2[:tenant]] через запятую.
+	APIKeys []string `mapstructure:"api_keys" secret:"true"`
+	// RolesClaim задаёт claim JWT со списком ролей.
+	RolesClaim string `mapstructure:"roles_claim"`
+	// TenantClaim задаёт claim JWT с арендатором, к данным которого ограничен токен.
+	TenantClaim string `mapstructure:"tenant_claim"`
+	// Roles сопоставляет роли с правами, например auth.roles.editor=persons:read,persons:write.
+	// Если не задано, используются роли reader, editor и admin.
+	Roles map[string][]string `mapstructure:"roles"`
 }
 
 // Config содержит все настройки приложения.
 type Config struct {
-	Server   Server   `mapstructure:"server"`
-	Database Database `mapstructure:"database"`
-	APIs     APIs     `mapstructure:"apis"`
-	LogLevel string   `mapstructure:"log_level"`
+	Server      Server      `mapstructure:"server"`
+	Database    Database    `mapstructure:"database"`
+	APIs        APIs        `mapstructure:"apis"`
+	Purge       Purge       `mapstructure:"purge"`
+	Idempotency Idempotency `mapstructure:"idempotency"`
+	Duplicates  Duplicates  `mapstructure:"duplicates"`
+	Enrichment  Enrichment  `mapstructure:"enrichment"`
+	Auth        Auth        `mapstructure:"auth"`
+	RateLimit   RateLimit   `mapstructure:"rate_limit"`
+	Tracing     Tracing     `mapstructure:"tracing"`
+	Health      Health      `mapstructure:"health"`
+	LogLevel    string      `mapstructure:"log_level"`
+	// Env задаёт режим работы: dev включает инструменты разработчика, например GraphQL playground.
+	Env string `mapstructure:"env"`
+}
+
+// IsDev сообщает, запущено ли приложение в режиме разработки.
+func (c *Config) IsDev() bool {
+	return c.Env == EnvDev
 }
 
 // LoadConfig загружает конфигурацию из .env файла или переменных окружения.
@@ -40,11 +216,12 @@ func LoadConfig() (*Config, error) {
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
 
-	fmt.Println("Попытка чтения .env файла...")
+	// Сообщения пишутся в stderr, чтобы не смешиваться с выводом команд CLI в stdout
+	fmt.Fprintln(os.Stderr, "Попытка чтения .env файла...")
 	if err := viper.ReadInConfig(); err != nil {
-		fmt.Printf("Ошибка чтения .env: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Ошибка чтения .env: %v\n", err)
 	} else {
-		fmt.Println("Файл .env успешно прочитан")
+		fmt.Fprintln(os.Stderr, "Файл .env успешно прочитан")
 	}
 
 	var cfg Config
@@ -52,11 +229,15 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("не удалось десериализовать конфигурацию: %w", err)
 	}
 
-	fmt.Printf("Загруженная конфигурация: %+v\n", cfg)
-
 	if cfg.Server.Port == "" {
 		return nil, fmt.Errorf("SERVER_PORT обязателен")
 	}
+	if cfg.Server.GRPCPort == "" {
+		cfg.Server.GRPCPort = "9090"
+	}
+	if cfg.Server.GRPCPort == cfg.Server.Port {
+		return nil, fmt.Errorf("server.grpc_port должен отличаться от server.server_port")
+	}
 	if cfg.Database.Name == "" {
 		return nil, fmt.Errorf("DB_NAME обязателен")
 	}
@@ -66,6 +247,136 @@ func LoadConfig() (*Config, error) {
 	if cfg.LogLevel == "" {
 		cfg.LogLevel = "info"
 	}
+	if cfg.Env == "" {
+		cfg.Env = EnvProd
+	}
+	if cfg.Env != EnvDev && cfg.Env != EnvProd {
+		return nil, fmt.Errorf("env должен быть %s или %s", EnvDev, EnvProd)
+	}
+	if cfg.Server.BatchMaxItems <= 0 {
+		cfg.Server.BatchMaxItems = 1000
+	}
+	if cfg.Server.BulkMaxRows <= 0 {
+		cfg.Server.BulkMaxRows = 1000
+	}
+	if cfg.Server.ImportMaxBytes <= 0 {
+		cfg.Server.ImportMaxBytes = 100 << 20
+	}
+	if cfg.Server.RequestMaxBytes <= 0 {
+		cfg.Server.RequestMaxBytes = 10 << 20
+	}
+	if cfg.Purge.Retention < 0 {
+		return nil, fmt.Errorf("purge.retention не может быть отрицательным")
+	}
+	if cfg.Purge.Retention > 0 && cfg.Purge.Interval <= 0 {
+		cfg.Purge.Interval = time.Hour
+	}
+	if cfg.Idempotency.TTL <= 0 {
+		cfg.Idempotency.TTL = 24 * time.Hour
+	}
+	if cfg.Idempotency.CleanupInterval <= 0 {
+		cfg.Idempotency.CleanupInterval = time.Hour
+	}
+	if cfg.Idempotency.LockTimeout <= 0 {
+		cfg.Idempotency.LockTimeout = time.Minute
+	}
+	if cfg.Duplicates.Threshold < 0 || cfg.Duplicates.Threshold > 1 {
+		return nil, fmt.Errorf("duplicates.threshold должен быть от 0 до 1")
+	}
+	if cfg.Duplicates.Threshold == 0 {
+		cfg.Duplicates.Threshold = 0.9
+	}
+	if cfg.Enrichment.CacheTTL < 0 || cfg.Enrichment.DailyQuota < 0 {
+		return nil, fmt.Errorf("enrichment.cache_ttl и enrichment.daily_quota не могут быть отрицательными")
+	}
+	if cfg.Enrichment.CacheTTL > 0 && cfg.Enrichment.CacheSize <= 0 {
+		cfg.Enrichment.CacheSize = 10000
+	}
+	if cfg.Enrichment.MaxTenants <= 0 {
+		cfg.Enrichment.MaxTenants = 1000
+	}
+	if cfg.RateLimit.Backend == "" {
+		cfg.RateLimit.Backend = RateLimitMemory
+	}
+	if cfg.RateLimit.Backend != RateLimitMemory && cfg.RateLimit.Backend != RateLimitPostgres {
+		return nil, fmt.Errorf("rate_limit.backend должен быть %s или %s", RateLimitMemory, RateLimitPostgres)
+	}
+	for name, rule := range map[string]*RateLimitRule{
+		"read":   &cfg.RateLimit.Read,
+		"write":  &cfg.RateLimit.Write,
+		"create": &cfg.RateLimit.Create,
+		"bulk":   &cfg.RateLimit.Bulk,
+	} {
+		if err := rule.setDefaults(name); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Tracing.Exporter == "" {
+		cfg.Tracing.Exporter = TracingNone
+	}
+	switch cfg.Tracing.Exporter {
+	case TracingNone, TracingStdout:
+	case TracingOTLP:
+		if cfg.Tracing.Endpoint == "" {
+			return nil, fmt.Errorf("tracing.endpoint обязателен для tracing.exporter=%s", TracingOTLP)
+		}
+	case TracingFile:
+		if cfg.Tracing.File == "" {
+			return nil, fmt.Errorf("tracing.file обязателен для tracing.exporter=%s", TracingFile)
+		}
+	default:
+		return nil, fmt.Errorf("tracing.exporter должен быть %s, %s, %s или %s", TracingNone, TracingOTLP, TracingStdout, TracingFile)
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		return nil, fmt.Errorf("tracing.sample_ratio должен быть от 0 до 1")
+	}
+	if cfg.Tracing.SampleRatio == 0 {
+		cfg.Tracing.SampleRatio = 1
+	}
+	if cfg.Health.Timeout <= 0 {
+		cfg.Health.Timeout = 2 * time.Second
+	}
+	if cfg.Health.DrainDelay < 0 {
+		return nil, fmt.Errorf("health.drain_delay не может быть отрицательным")
+	}
+	if cfg.Auth.JWKSFile != "" && cfg.Auth.JWKSURL != "" {
+		return nil, fmt.Errorf("auth.jwks_file и auth.jwks_url не могут быть заданы одновременно")
+	}
+	if cfg.Auth.JWKSRefreshInterval <= 0 {
+		cfg.Auth.JWKSRefreshInterval = time.Hour
+	}
+	if cfg.Auth.RolesClaim == "" {
+		cfg.Auth.RolesClaim = "roles"
+	}
+	if cfg.Auth.TenantClaim == "" {
+		cfg.Auth.TenantClaim = "tenant_id"
+	}
 
 	return &cfg, nil
 }
+
+// defaultRateLimits задаёт лимиты классов маршрутов, если они не настроены.
+var defaultRateLimits = map[string]RateLimitRule{
+	"read":   {Requests: 600, Period: time.Minute, Burst: 100},
+	"write":  {Requests: 120, Period: time.Minute, Burst: 30},
+	"create": {Requests: 30, Period: time.Minute, Burst: 10},
+	"bulk":   {Requests: 10, Period: time.Minute, Burst: 2},
+}
+
+// setDefaults заполняет незаданные поля правила класса name значениями по умолчанию.
+func (r *RateLimitRule) setDefaults(name string) error {
+	if r.Requests < 0 || r.Period < 0 || r.Burst < 0 {
+		return fmt.Errorf("rate_limit.%s не может содержать отрицательные значения", name)
+	}
+	def := defaultRateLimits[name]
+	if r.Requests == 0 {
+		r.Requests = def.Requests
+	}
+	if r.Period == 0 {
+		r.Period = def.Period
+	}
+	if r.Burst == 0 {
+		r.Burst = def.Burst
+	}
+	return nil
+}
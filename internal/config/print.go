@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// redacted заменяет значения секретных параметров при выводе конфигурации.
+const redacted = "******"
+
+// Print записывает действующую конфигурацию с учётом значений по умолчанию в формате .env:
+// по строке key=value в порядке ключей. Значения параметров с тегом secret:"true" заменяются на ******,
+// чтобы вывод можно было приложить к обращению в поддержку.
+func (c *Config) Print(w io.Writer) error {
+	values := make(map[string]string)
+	flatten("", reflect.ValueOf(*c), values)
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", key, values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flatten раскладывает структуру v по ключам вида prefix.key из тегов mapstructure.
+func flatten(prefix string, v reflect.Value, values map[string]string) {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		key := prefix + field.Tag.Get("mapstructure")
+		value := v.Field(i)
+		secret := field.Tag.Get("secret") == "true"
+
+		switch {
+		case value.Kind() == reflect.Struct:
+			flatten(key+".", value, values)
+		case value.Kind() == reflect.Map:
+			for _, mapKey := range value.MapKeys() {
+				values[key+"."+mapKey.String()] = format(value.MapIndex(mapKey), secret)
+			}
+		default:
+			values[key] = format(value, secret)
+		}
+	}
+}
+
+// format возвращает значение параметра в виде, принятом в .env; непустые секреты скрываются.
+func format(v reflect.Value, secret bool) string {
+	var s string
+	switch value := v.Interface().(type) {
+	case time.Duration:
+		s = value.String()
+	case []string:
+		s = strings.Join(value, ",")
+	default:
+		s = fmt.Sprint(value)
+	}
+	if secret && s != "" {
+		return redacted
+	}
+	return s
+}
@@ -0,0 +1,192 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"person-service/internal/models"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Format представляет формат выгрузки.
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatNDJSON  Format = "ndjson"
+	FormatParquet Format = "parquet"
+)
+
+// parquetRowGroupSize определяет, сколько записей Parquet держит в памяти до сброса группы строк.
+const parquetRowGroupSize = 10000
+
+// ParseFormat разбирает формат выгрузки; пустое значение означает CSV.
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(value)) {
+	case FormatCSV, "":
+		return FormatCSV, nil
+	case FormatNDJSON:
+		return FormatNDJSON, nil
+	case FormatParquet:
+		return FormatParquet, nil
+	default:
+		return "", fmt.Errorf("неподдерживаемый формат: %s, ожидается csv, ndjson или parquet", value)
+	}
+}
+
+// ContentType возвращает MIME-тип формата.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatParquet:
+		return "application/vnd.apache.parquet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Writer последовательно записывает записи о людях в поток.
+type Writer interface {
+	Write(person *models.Person) error
+	// Close дописывает буферизованные данные; поток w при этом не закрывается.
+	Close() error
+}
+
+// NewWriter создаёт Writer указанного формата поверх w.
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w, nil)
+	case FormatNDJSON:
+		return newNDJSONWriter(w), nil
+	case FormatParquet:
+		return newParquetWriter(w), nil
+	default:
+		return nil, fmt.Errorf("неподдерживаемый формат: %s", format)
+	}
+}
+
+type csvWriter struct {
+	writer  *csv.Writer
+	columns []string
+}
+
+// NewCSVWriter создаёт Writer, записывающий в CSV только колонки columns; nil означает все поля записи.
+func NewCSVWriter(w io.Writer, columns []string) (Writer, error) {
+	if columns == nil {
+		columns = models.PersonFields
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer, columns: columns}, nil
+}
+
+func (c *csvWriter) Write(p *models.Person) error {
+	record := make([]string, len(c.columns))
+	for i, column := range c.columns {
+		record[i] = csvValue(p.FieldValue(column))
+	}
+	return c.writer.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type ndjsonWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	buffer := bufio.NewWriter(w)
+	return &ndjsonWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}
+}
+
+func (n *ndjsonWriter) Write(p *models.Person) error {
+	return n.encoder.Encode(p)
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.buffer.Flush()
+}
+
+// parquetRow описывает схему Parquet-выгрузки.
+type parquetRow struct {
+	ID          int64      `parquet:"id"`
+	Name        string     `parquet:"name"`
+	Surname     *string    `parquet:"surname,optional"`
+	Patronymic  *string    `parquet:"patronymic,optional"`
+	Age         *int32     `parquet:"age,optional"`
+	Gender      *string    `parquet:"gender,optional"`
+	Nationality *string    `parquet:"nationality,optional"`
+	CreatedAt   time.Time  `parquet:"created_at"`
+	UpdatedAt   *time.Time `parquet:"updated_at,optional"`
+	Version     int32      `parquet:"version"`
+	DeletedAt   *time.Time `parquet:"deleted_at,optional"`
+}
+
+type parquetWriter struct {
+	writer   *parquet.GenericWriter[parquetRow]
+	buffered int
+}
+
+func newParquetWriter(w io.Writer) *parquetWriter {
+	return &parquetWriter{writer: parquet.NewGenericWriter[parquetRow](w)}
+}
+
+func (p *parquetWriter) Write(person *models.Person) error {
+	row := parquetRow{
+		ID:          int64(person.ID),
+		Name:        person.Name,
+		Surname:     person.Surname,
+		Patronymic:  person.Patronymic,
+		Nationality: person.Nationality,
+		CreatedAt:   person.CreatedAt,
+		UpdatedAt:   person.UpdatedAt,
+		Version:     int32(person.Version),
+		DeletedAt:   person.DeletedAt,
+	}
+	if person.Gender != nil {
+		gender := string(*person.Gender)
+		row.Gender = &gender
+	}
+	if person.Age != nil {
+		age := int32(*person.Age)
+		row.Age = &age
+	}
+	if _, err := p.writer.Write([]parquetRow{row}); err != nil {
+		return err
+	}
+	p.buffered++
+	if p.buffered >= parquetRowGroupSize {
+		p.buffered = 0
+		return p.writer.Flush()
+	}
+	return nil
+}
+
+func (p *parquetWriter) Close() error {
+	return p.writer.Close()
+}
+
+// csvValue форматирует значение поля для CSV; время записывается в RFC 3339.
+func csvValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
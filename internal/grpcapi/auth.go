@@ -0,0 +1,92 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"person-service/internal/audit"
+	"person-service/internal/auth"
+	"person-service/pkg/logger"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Ключи метаданных с учётными данными, как заголовки Authorization и X-API-Key в HTTP.
+const (
+	authorizationKey = "authorization"
+	apiKeyKey        = "x-api-key"
+)
+
+// publicServices перечисляет сервисы, доступные без аутентификации: проверки здоровья и reflection.
+var publicServices = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1.ServerReflection/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
+
+// authInterceptor проверяет учётные данные из метаданных вызова.
+type authInterceptor struct {
+	auth   *auth.Authenticator
+	logger *zap.Logger
+}
+
+// authenticate возвращает контекст с клиентом и автором изменений или ошибку Unauthenticated.
+func (a *authInterceptor) authenticate(ctx context.Context, method string) (context.Context, error) {
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var principal *auth.Principal
+	var err error
+	if values := md.Get(authorizationKey); len(values) > 0 && hasBearerScheme(values[0]) {
+		principal, err = a.auth.AuthenticateToken(ctx, strings.TrimSpace(values[0][len("bearer "):]))
+	} else if values := md.Get(apiKeyKey); len(values) > 0 && values[0] != "" {
+		principal, err = a.auth.AuthenticateAPIKey(ctx, values[0])
+	} else {
+		err = auth.ErrUnauthenticated
+	}
+
+	if errors.Is(err, auth.ErrUnauthenticated) {
+		logger.FromContext(ctx, a.logger).Info("Вызов отклонён: не пройдена аутентификация", zap.String("method", method), logger.ErrorKV("error", err))
+		return nil, status.Error(codes.Unauthenticated, auth.ErrUnauthenticated.Error())
+	}
+	if err != nil {
+		logger.FromContext(ctx, a.logger).Error("Ошибка аутентификации", logger.ErrorKV("error", err))
+		return nil, status.Error(codes.Internal, "не удалось проверить учётные данные")
+	}
+
+	meta := audit.FromContext(ctx)
+	meta.Actor = principal.Subject
+	ctx = logger.WithContext(ctx, logger.FromContext(ctx, a.logger).With(zap.String("principal", principal.Subject)))
+	return audit.WithMeta(auth.WithPrincipal(ctx, principal), meta), nil
+}
+
+// hasBearerScheme сообщает, передан ли в значении authorization токен со схемой Bearer.
+func hasBearerScheme(value string) bool {
+	return len(value) > len("bearer ") && strings.EqualFold(value[:len("bearer ")], "bearer ")
+}
+
+// unary проверяет учётные данные унарных вызовов.
+func (a *authInterceptor) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// stream проверяет учётные данные потоковых вызовов.
+func (a *authInterceptor) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
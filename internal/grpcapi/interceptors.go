@@ -0,0 +1,86 @@
+package grpcapi
+
+import (
+	"context"
+	"person-service/internal/audit"
+	"person-service/pkg/logger"
+	"runtime/debug"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey — ключ метаданных с идентификатором запроса.
+const requestIDKey = "x-request-id"
+
+// withRequestMeta добавляет в контекст метаданные аудита с источником grpc и логгер вызова с полем request_id,
+// как accessLog в HTTP. Идентификатор берётся из метаданных x-request-id или создаётся новый.
+func withRequestMeta(ctx context.Context, log *zap.Logger) context.Context {
+	meta := audit.Meta{Source: audit.SourceGRPC}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDKey); len(values) > 0 {
+			meta.RequestID = values[0]
+		}
+	}
+	if meta.RequestID == "" {
+		meta.RequestID = uuid.NewString()
+	}
+	ctx = logger.WithContext(ctx, log.With(zap.String("request_id", meta.RequestID)))
+	return audit.WithMeta(ctx, meta)
+}
+
+// requestUnary добавляет метаданные аудита и логгер вызова к унарным вызовам.
+func requestUnary(log *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withRequestMeta(ctx, log), req)
+	}
+}
+
+// requestStream добавляет метаданные аудита и логгер вызова к потоковым вызовам.
+func requestStream(log *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withRequestMeta(ss.Context(), log)})
+	}
+}
+
+// contextStream подменяет контекст потока.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// recoverUnary перехватывает панику в обработчике и возвращает Internal, как middleware.Recoverer в HTTP.
+func recoverUnary(log *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error("Паника в gRPC-обработчике", zap.String("method", info.FullMethod),
+					logger.ErrorKV("panic", r), zap.ByteString("stack", debug.Stack()))
+				err = status.Error(codes.Internal, "внутренняя ошибка сервера")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// recoverStream перехватывает панику в потоковом обработчике.
+func recoverStream(log *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error("Паника в gRPC-обработчике", zap.String("method", info.FullMethod),
+					logger.ErrorKV("panic", r), zap.ByteString("stack", debug.Stack()))
+				err = status.Error(codes.Internal, "внутренняя ошибка сервера")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,282 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"person-service/internal/auth"
+	"person-service/internal/models"
+	"person-service/internal/repository"
+	"person-service/internal/service"
+	"person-service/pkg/logger"
+	"person-service/pkg/pb/personv1"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// PersonServer реализует gRPC-сервис PersonService поверх сервисного слоя.
+type PersonServer struct {
+	personv1.UnimplementedPersonServiceServer
+	service *service.PersonService
+	logger  *zap.Logger
+}
+
+// NewPersonServer создаёт реализацию PersonService.
+func NewPersonServer(service *service.PersonService, logger *zap.Logger) *PersonServer {
+	return &PersonServer{service: service, logger: logger}
+}
+
+// Create создаёт запись с обогащением.
+func (s *PersonServer) Create(ctx context.Context, req *personv1.CreatePersonRequest) (*personv1.Person, error) {
+	input := &models.PersonInput{
+		Name:       req.GetName(),
+		Surname:    req.Surname,
+		Patronymic: req.Patronymic,
+	}
+	person, err := s.service.Create(ctx, input)
+	if err != nil {
+		logger.FromContext(ctx, s.logger).Error("Ошибка создания записи", logger.ErrorKV("error", err))
+		return nil, toStatus(err, codes.InvalidArgument)
+	}
+	return toProto(person), nil
+}
+
+// Get возвращает запись по ID.
+func (s *PersonServer) Get(ctx context.Context, req *personv1.GetPersonRequest) (*personv1.Person, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	person, err := s.service.GetByID(ctx, id, req.GetIncludeDeleted(), nil)
+	if err != nil {
+		logger.FromContext(ctx, s.logger).Error("Ошибка получения записи", logger.ErrorKV("error", err))
+		return nil, toStatus(err, codes.Internal)
+	}
+	return toProto(person), nil
+}
+
+// Update полностью заменяет запись.
+func (s *PersonServer) Update(ctx context.Context, req *personv1.UpdatePersonRequest) (*personv1.Person, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	input := &models.PersonReplace{
+		Name:        req.GetName(),
+		Surname:     req.Surname,
+		Patronymic:  req.Patronymic,
+		Age:         toInt(req.Age),
+		Gender:      fromProtoGender(req.GetGender()),
+		Nationality: req.Nationality,
+	}
+	person, _, err := s.service.Replace(ctx, id, input, false)
+	if err != nil {
+		logger.FromContext(ctx, s.logger).Error("Ошибка обновления записи", logger.ErrorKV("error", err))
+		return nil, toStatus(err, codes.InvalidArgument)
+	}
+	return toProto(person), nil
+}
+
+// Patch изменяет только заданные поля записи.
+func (s *PersonServer) Patch(ctx context.Context, req *personv1.PatchPersonRequest) (*personv1.Person, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	update := &models.PersonUpdate{
+		Name:        req.Name,
+		Surname:     req.Surname,
+		Patronymic:  req.Patronymic,
+		Age:         toInt(req.Age),
+		Gender:      fromProtoGender(req.GetGender()),
+		Nationality: req.Nationality,
+	}
+	person, err := s.service.Patch(ctx, id, update)
+	if err != nil {
+		logger.FromContext(ctx, s.logger).Error("Ошибка частичного обновления записи", logger.ErrorKV("error", err))
+		return nil, toStatus(err, codes.InvalidArgument)
+	}
+	return toProto(person), nil
+}
+
+// Delete помечает запись удалённой.
+func (s *PersonServer) Delete(ctx context.Context, req *personv1.DeletePersonRequest) (*emptypb.Empty, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.service.Delete(ctx, id); err != nil {
+		logger.FromContext(ctx, s.logger).Error("Ошибка удаления записи", logger.ErrorKV("error", err))
+		return nil, toStatus(err, codes.Internal)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// List возвращает страницу записей под фильтром.
+func (s *PersonServer) List(ctx context.Context, req *personv1.ListPersonsRequest) (*personv1.ListPersonsResponse, error) {
+	limit, offset := int(req.GetLimit()), int(req.GetOffset())
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	filters := toFilters(req.GetFilter())
+	if req.GetSort() != "" {
+		filters["sort"] = req.GetSort()
+	}
+
+	persons, err := s.service.List(ctx, limit, offset, filters, nil)
+	if err != nil {
+		logger.FromContext(ctx, s.logger).Error("Ошибка получения списка", logger.ErrorKV("error", err))
+		return nil, toStatus(err, codes.InvalidArgument)
+	}
+	resp := &personv1.ListPersonsResponse{Persons: make([]*personv1.Person, len(persons))}
+	for i, person := range persons {
+		resp.Persons[i] = toProto(person)
+	}
+	return resp, nil
+}
+
+// ListAll передаёт все записи под фильтром потоком.
+func (s *PersonServer) ListAll(req *personv1.ListAllPersonsRequest, stream personv1.PersonService_ListAllServer) error {
+	ctx := stream.Context()
+	err := s.service.Export(ctx, toFilters(req.GetFilter()), func(person *models.Person) error {
+		return stream.Send(toProto(person))
+	})
+	if err != nil {
+		logger.FromContext(ctx, s.logger).Error("Ошибка потоковой выдачи записей", logger.ErrorKV("error", err))
+		return toStatus(err, codes.Internal)
+	}
+	return nil
+}
+
+// toStatus преобразует ошибку сервисного слоя в статус gRPC; нераспознанные ошибки получают код fallback.
+func toStatus(err error, fallback codes.Code) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	code := fallback
+	switch {
+	case repository.IsNotFound(err):
+		code = codes.NotFound
+	case service.IsDuplicate(err):
+		code = codes.AlreadyExists
+	case errors.Is(err, auth.ErrForbidden):
+		code = codes.PermissionDenied
+	case errors.Is(err, service.ErrEnrichmentQuotaExceeded):
+		code = codes.ResourceExhausted
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	}
+	return status.Error(code, err.Error())
+}
+
+// parseID проверяет ID из запроса.
+func parseID(id int64) (int, error) {
+	if id <= 0 {
+		return 0, status.Error(codes.InvalidArgument, "некорректный ID: "+strconv.FormatInt(id, 10))
+	}
+	return int(id), nil
+}
+
+// toFilters преобразует фильтр запроса в фильтры списка сервиса.
+func toFilters(filter *personv1.PersonFilter) map[string]string {
+	filters := map[string]string{}
+	if filter == nil {
+		return filters
+	}
+	for key, value := range map[string]string{
+		"name":        filter.GetName(),
+		"surname":     filter.GetSurname(),
+		"patronymic":  filter.GetPatronymic(),
+		"nationality": filter.GetNationality(),
+	} {
+		if value != "" {
+			filters[key] = value
+		}
+	}
+	if filter.Age != nil {
+		filters["age"] = strconv.Itoa(int(filter.GetAge()))
+	}
+	if gender := fromProtoGender(filter.GetGender()); gender != nil {
+		filters["gender"] = string(*gender)
+	}
+	if filter.GetIncludeDeleted() {
+		filters["include_deleted"] = "true"
+	}
+	return filters
+}
+
+// toProto преобразует запись в сообщение Person.
+func toProto(p *models.Person) *personv1.Person {
+	msg := &personv1.Person{
+		Id:          int64(p.ID),
+		Name:        p.Name,
+		Surname:     p.Surname,
+		Patronymic:  p.Patronymic,
+		Gender:      toProtoGender(p.Gender),
+		Nationality: p.Nationality,
+		CreatedAt:   timestamppb.New(p.CreatedAt),
+		UpdatedAt:   toTimestamp(p.UpdatedAt),
+		Version:     int32(p.Version),
+		DeletedAt:   toTimestamp(p.DeletedAt),
+	}
+	if p.Age != nil {
+		age := int32(*p.Age)
+		msg.Age = &age
+	}
+	return msg
+}
+
+// toTimestamp преобразует необязательное время.
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// toInt преобразует необязательное целое.
+func toInt(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	value := int(*v)
+	return &value
+}
+
+// toProtoGender преобразует пол записи в значение перечисления.
+func toProtoGender(g *models.GenderType) personv1.Gender {
+	if g == nil {
+		return personv1.Gender_GENDER_UNSPECIFIED
+	}
+	switch *g {
+	case models.GenderMale:
+		return personv1.Gender_GENDER_MALE
+	case models.GenderFemale:
+		return personv1.Gender_GENDER_FEMALE
+	}
+	return personv1.Gender_GENDER_UNSPECIFIED
+}
+
+// fromProtoGender преобразует значение перечисления в пол записи; GENDER_UNSPECIFIED означает отсутствие значения.
+func fromProtoGender(g personv1.Gender) *models.GenderType {
+	var gender models.GenderType
+	switch g {
+	case personv1.Gender_GENDER_MALE:
+		gender = models.GenderMale
+	case personv1.Gender_GENDER_FEMALE:
+		gender = models.GenderFemale
+	default:
+		return nil
+	}
+	return &gender
+}
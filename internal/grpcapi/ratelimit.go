@@ -0,0 +1,98 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"person-service/internal/ratelimit"
+	"person-service/pkg/logger"
+	"person-service/pkg/pb/personv1"
+	"strconv"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// methodClasses сопоставляет методы PersonService классам ограничения частоты; остальные сервисы не ограничиваются.
+var methodClasses = map[string]ratelimit.Class{
+	personv1.PersonService_Create_FullMethodName:  ratelimit.ClassCreate,
+	personv1.PersonService_Get_FullMethodName:     ratelimit.ClassRead,
+	personv1.PersonService_List_FullMethodName:    ratelimit.ClassRead,
+	personv1.PersonService_ListAll_FullMethodName: ratelimit.ClassBulk,
+	personv1.PersonService_Update_FullMethodName:  ratelimit.ClassWrite,
+	personv1.PersonService_Patch_FullMethodName:   ratelimit.ClassWrite,
+	personv1.PersonService_Delete_FullMethodName:  ratelimit.ClassWrite,
+}
+
+// rateLimitInterceptor ограничивает частоту вызовов клиента, как rate limit middleware HTTP.
+// Состояние ведра передаётся в метаданных ответа ratelimit-*, при превышении лимита вызов
+// получает ResourceExhausted и метаданные retry-after.
+type rateLimitInterceptor struct {
+	limiter *ratelimit.Limiter
+	logger  *zap.Logger
+}
+
+// allow забирает токен для вызова method и возвращает метаданные ответа или ошибку ResourceExhausted.
+func (l *rateLimitInterceptor) allow(ctx context.Context, method string) (metadata.MD, error) {
+	class, ok := methodClasses[method]
+	if !ok {
+		return nil, nil
+	}
+	client := ratelimit.ClientKey(ctx, peerIP(ctx))
+	result := l.limiter.Allow(ctx, class, client)
+
+	md := metadata.Pairs(
+		"ratelimit-limit", strconv.Itoa(result.Rule.Burst),
+		"ratelimit-remaining", strconv.Itoa(result.Remaining),
+		"ratelimit-reset", strconv.Itoa(int(result.Reset.Seconds())),
+	)
+	if !result.Allowed {
+		retryAfter := int(result.RetryAfter.Seconds())
+		logger.FromContext(ctx, l.logger).Info("Вызов отклонён: превышен лимит запросов",
+			zap.String("client", client), zap.String("class", string(class)), zap.String("method", method))
+		md.Set("retry-after", strconv.Itoa(retryAfter))
+		return md, status.Error(codes.ResourceExhausted, fmt.Sprintf("превышен лимит запросов, повторите через %d с", retryAfter))
+	}
+	return md, nil
+}
+
+// unary ограничивает частоту унарных вызовов.
+func (l *rateLimitInterceptor) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, err := l.allow(ctx, info.FullMethod)
+	if md != nil {
+		grpc.SetHeader(ctx, md)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// stream ограничивает частоту потоковых вызовов.
+func (l *rateLimitInterceptor) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	md, err := l.allow(ss.Context(), info.FullMethod)
+	if md != nil {
+		ss.SetHeader(md)
+	}
+	if err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// peerIP возвращает IP-адрес клиента вызова.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,106 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"person-service/internal/auth"
+	"person-service/internal/config"
+	"person-service/internal/ratelimit"
+	"person-service/internal/service"
+	"person-service/pkg/logger"
+	"person-service/pkg/pb/personv1"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server представляет gRPC-сервер.
+type Server struct {
+	grpcServer *grpc.Server
+	health     *health.Server
+	addr       string
+	logger     *zap.Logger
+}
+
+// NewServer создаёт gRPC-сервер с PersonService, проверками здоровья и reflection.
+// Вызовы PersonService требуют аутентификации, если она не отключена в настройках; authenticator тогда может быть nil.
+// Выбор арендатора метаданными x-tenant-id разрешается по policy.
+// Частота вызовов ограничивается через limiter; nil отключает ограничение.
+func NewServer(cfg *config.Config, service *service.PersonService, authenticator *auth.Authenticator, policy *auth.Policy, limiter *ratelimit.Limiter, logger *zap.Logger) *Server {
+	unary := []grpc.UnaryServerInterceptor{recoverUnary(logger), requestUnary(logger)}
+	stream := []grpc.StreamServerInterceptor{recoverStream(logger), requestStream(logger)}
+	if !cfg.Auth.Disabled {
+		a := &authInterceptor{auth: authenticator, logger: logger}
+		unary = append(unary, a.unary)
+		stream = append(stream, a.stream)
+	}
+	tenants := &tenantInterceptor{policy: policy, logger: logger}
+	unary = append(unary, tenants.unary)
+	stream = append(stream, tenants.stream)
+	if limiter != nil {
+		limits := &rateLimitInterceptor{limiter: limiter, logger: logger}
+		unary = append(unary, limits.unary)
+		stream = append(stream, limits.stream)
+	}
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+	personv1.RegisterPersonServiceServer(grpcServer, NewPersonServer(service, logger))
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	healthServer.SetServingStatus(personv1.PersonService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	reflection.Register(grpcServer)
+
+	return &Server{
+		grpcServer: grpcServer,
+		health:     healthServer,
+		addr:       fmt.Sprintf(":%s", cfg.Server.GRPCPort),
+		logger:     logger,
+	}
+}
+
+// Start запускает сервер.
+func (s *Server) Start() error {
+	s.logger.Info("Запуск gRPC-сервера", logger.InfoKV("port", s.addr))
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("ошибка запуска gRPC-сервера: %w", err)
+	}
+	if err := s.grpcServer.Serve(listener); err != nil && err != grpc.ErrServerStopped {
+		return fmt.Errorf("ошибка запуска gRPC-сервера: %w", err)
+	}
+	return nil
+}
+
+// Drain переводит проверки здоровья в NOT_SERVING, не прерывая обработку вызовов,
+// чтобы балансировщик успел снять с сервера трафик до Shutdown.
+func (s *Server) Drain() {
+	s.health.Shutdown()
+}
+
+// Shutdown останавливает сервер: новые вызовы отклоняются, текущие дожидаются завершения до отмены ctx.
+// Проверки здоровья сразу начинают возвращать NOT_SERVING.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("Остановка gRPC-сервера")
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return fmt.Errorf("ошибка остановки gRPC-сервера: %w", ctx.Err())
+	}
+}
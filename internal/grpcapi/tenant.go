@@ -0,0 +1,66 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"person-service/internal/auth"
+	"person-service/internal/tenant"
+	"person-service/pkg/logger"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tenantKey — ключ метаданных с арендатором, как заголовок X-Tenant-ID в HTTP.
+const tenantKey = "x-tenant-id"
+
+// tenantInterceptor ограничивает вызовы арендатором из учётных данных или метаданных x-tenant-id;
+// метаданные учитываются по тем же правилам, что и заголовок X-Tenant-ID в HTTP (auth.Policy.BoundTenant).
+type tenantInterceptor struct {
+	policy *auth.Policy
+	logger *zap.Logger
+}
+
+// scope возвращает контекст с арендатором вызова или ошибку InvalidArgument либо PermissionDenied.
+func (t *tenantInterceptor) scope(ctx context.Context) (context.Context, error) {
+	var requested string
+	principal := auth.FromContext(ctx)
+	bound := t.policy.BoundTenant(principal)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(tenantKey); len(values) > 0 {
+			requested = values[0]
+		}
+	}
+
+	tenantID, err := tenant.Resolve(bound, requested)
+	if errors.Is(err, tenant.ErrMismatch) {
+		logger.FromContext(ctx, t.logger).Warn("Доступ к арендатору запрещён",
+			zap.String("subject", principal.Subject), zap.String("tenant", bound), zap.String("requested", requested))
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return tenant.WithID(ctx, tenantID), nil
+}
+
+// unary ограничивает арендатором унарные вызовы.
+func (t *tenantInterceptor) unary(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := t.scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// stream ограничивает арендатором потоковые вызовы.
+func (t *tenantInterceptor) stream(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := t.scope(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
@@ -0,0 +1,60 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"person-service/internal/repository"
+)
+
+// Pinger — пул соединений, который можно проверить запросом к серверу.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping проверяет, что db отвечает на запросы.
+func Ping(db Pinger) Check {
+	return func(ctx context.Context) (string, error) {
+		if err := db.Ping(ctx); err != nil {
+			return "", fmt.Errorf("база данных недоступна: %w", err)
+		}
+		return "", nil
+	}
+}
+
+// Schema проверяет, что схема базы данных не старше версии expected, на которую рассчитан код.
+// Более новая схема допустима: во время обновления её уже применили экземпляры новой версии.
+func Schema(repo repository.SchemaRepository, expected int64) Check {
+	return func(ctx context.Context) (string, error) {
+		version, err := repo.Version(ctx)
+		if err != nil {
+			return "", err
+		}
+		detail := fmt.Sprintf("version %d, expected %d", version, expected)
+		if version < expected {
+			return detail, fmt.Errorf("схема базы данных версии %d старше требуемой %d", version, expected)
+		}
+		return detail, nil
+	}
+}
+
+// Reachable проверяет, что сервер по адресу url отвечает; ответы с кодом ниже 500 считаются успешными,
+// так как API обогащения без параметров отвечает ошибкой клиента.
+func Reachable(client *http.Client, url string) Check {
+	return func(ctx context.Context) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return "", fmt.Errorf("не удалось создать запрос: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("сервер недоступен: %w", err)
+		}
+		resp.Body.Close()
+		detail := fmt.Sprintf("HTTP %d", resp.StatusCode)
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return detail, fmt.Errorf("сервер ответил %s", resp.Status)
+		}
+		return detail, nil
+	}
+}
@@ -0,0 +1,144 @@
+// Package health сообщает, жив ли сервис (liveness) и готов ли он принимать запросы (readiness).
+//
+// Готовность складывается из проверок компонентов. Обязательный компонент, который не прошёл
+// проверку, снимает готовность; необязательный только отмечает сервис как degraded.
+// После Drain сервис не готов независимо от компонентов, чтобы балансировщик успел снять с него трафик.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Статусы сервиса и компонентов.
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+	StatusDraining = "draining"
+)
+
+// Check проверяет компонент и возвращает пояснение к результату, например версию схемы.
+type Check func(ctx context.Context) (detail string, err error)
+
+// Component — результат проверки компонента.
+type Component struct {
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	// DurationMS — длительность проверки в миллисекундах.
+	DurationMS int64 `json:"duration_ms"`
+}
+
+// Report — ответ /readyz.
+type Report struct {
+	Status     string               `json:"status"`
+	Components map[string]Component `json:"components,omitempty"`
+}
+
+// component — зарегистрированная проверка.
+type component struct {
+	name     string
+	check    Check
+	optional bool
+}
+
+// Checker выполняет проверки компонентов.
+type Checker struct {
+	components []component
+	timeout    time.Duration
+	draining   atomic.Bool
+}
+
+// NewChecker создаёт Checker, который ограничивает все проверки одного запроса временем timeout.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add добавляет обязательный компонент name.
+func (c *Checker) Add(name string, check Check) {
+	c.components = append(c.components, component{name: name, check: check})
+}
+
+// AddOptional добавляет необязательный компонент name, недоступность которого не снимает готовность.
+func (c *Checker) AddOptional(name string, check Check) {
+	c.components = append(c.components, component{name: name, check: check, optional: true})
+}
+
+// Drain отмечает начало graceful shutdown: с этого момента сервис не готов.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Check параллельно проверяет все компоненты.
+func (c *Checker) Check(ctx context.Context) Report {
+	if c.draining.Load() {
+		return Report{Status: StatusDraining}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	results := make([]Component, len(c.components))
+	var wg sync.WaitGroup
+	for i, comp := range c.components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			detail, err := comp.check(ctx)
+			result := Component{Status: StatusOK, Detail: detail, Optional: comp.optional, DurationMS: time.Since(start).Milliseconds()}
+			if err != nil {
+				result.Status = StatusDown
+				result.Error = err.Error()
+			}
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Components: make(map[string]Component, len(c.components))}
+	for i, comp := range c.components {
+		result := results[i]
+		report.Components[comp.name] = result
+		if result.Status == StatusOK {
+			continue
+		}
+		if !comp.optional {
+			report.Status = StatusDown
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// Liveness отвечает 200, пока процесс способен обрабатывать запросы; зависимости не проверяются,
+// чтобы их сбой не приводил к перезапуску сервиса.
+func (c *Checker) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, Report{Status: StatusOK})
+}
+
+// Readiness отвечает 200 со статусом ok или degraded и 503, если обязательный компонент недоступен
+// или сервис завершает работу.
+func (c *Checker) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := c.Check(r.Context())
+	status := http.StatusOK
+	if report.Status == StatusDown || report.Status == StatusDraining {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+// writeJSON записывает report с кодом status; ответы проверок не кэшируются.
+func writeJSON(w http.ResponseWriter, status int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,247 @@
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"person-service/internal/models"
+	"person-service/internal/service"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Format представляет формат импортируемого файла.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// defaultChunkSize определяет, сколько строк сохраняется одним пакетом.
+const defaultChunkSize = 500
+
+// ErrInvalidFile отмечает ошибки содержимого файла: повреждённый формат, нечитаемую строку
+// или колонки, которые не удалось сопоставить. Остальные ошибки импорта, например базы данных, — внутренние.
+var ErrInvalidFile = errors.New("некорректный файл")
+
+// ParseFormat разбирает формат файла.
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(value)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("неподдерживаемый формат: %s, ожидается csv или xlsx", value)
+	}
+}
+
+// FormatFromFilename определяет формат по расширению имени файла.
+func FormatFromFilename(name string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
+
+// Options содержит настройки импорта.
+type Options struct {
+	Format Format
+	// Encoding задаёт кодировку CSV: utf-8, windows-1251 или auto.
+	Encoding string
+	// Mapping сопоставляет поля PersonInput с колонками файла, см. ParseMapping.
+	Mapping map[string]string
+	// Enrich включает обогащение данных через внешние API.
+	Enrich    bool
+	ChunkSize int
+}
+
+// RowError описывает ошибку импорта строки файла.
+type RowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// CreatedRow связывает строку файла с созданной записью.
+type CreatedRow struct {
+	Row int `json:"row"`
+	ID  int `json:"id"`
+}
+
+// Report содержит результат импорта.
+type Report struct {
+	Rows    int          `json:"rows"`
+	Created int          `json:"created"`
+	Failed  int          `json:"failed"`
+	Records []CreatedRow `json:"records"`
+	Errors  []RowError   `json:"errors"`
+	// Error — ошибка, прервавшая импорт; записи из Records к этому моменту уже сохранены.
+	Error string `json:"error,omitempty"`
+}
+
+// WriteCSV записывает отчёт в CSV: по строке на каждую созданную запись и каждую ошибку,
+// а если импорт прерван — строку без номера с ошибкой, прервавшей его.
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"row", "status", "id", "error"}); err != nil {
+		return err
+	}
+	for _, record := range r.Records {
+		if err := writer.Write([]string{strconv.Itoa(record.Row), string(models.BatchItemCreated), strconv.Itoa(record.ID), ""}); err != nil {
+			return err
+		}
+	}
+	for _, rowErr := range r.Errors {
+		if err := writer.Write([]string{strconv.Itoa(rowErr.Row), string(models.BatchItemFailed), "", rowErr.Error}); err != nil {
+			return err
+		}
+	}
+	if r.Error != "" {
+		if err := writer.Write([]string{"", string(models.BatchItemFailed), "", r.Error}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Importer импортирует записи о людях из CSV и XLSX.
+type Importer struct {
+	service *service.PersonService
+	logger  *zap.Logger
+}
+
+// NewImporter создаёт новый экземпляр Importer.
+func NewImporter(service *service.PersonService, logger *zap.Logger) *Importer {
+	return &Importer{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Import читает файл построчно и сохраняет записи пакетами по opts.ChunkSize.
+// Некорректные строки попадают в отчёт и не мешают импорту остальных.
+// Если импорт прерван, вместе с ошибкой возвращается отчёт о пакетах, сохранённых до неё;
+// ошибки содержимого файла оборачивают ErrInvalidFile.
+func (im *Importer) Import(ctx context.Context, r io.Reader, opts Options) (*Report, error) {
+	report := &Report{Records: []CreatedRow{}, Errors: []RowError{}}
+	var reader rowReader
+	var err error
+	switch opts.Format {
+	case FormatCSV:
+		reader, err = newCSVReader(r, opts.Encoding)
+	case FormatXLSX:
+		reader, err = newXLSXReader(r)
+	default:
+		return report, fmt.Errorf("%w: неподдерживаемый формат: %s", ErrInvalidFile, opts.Format)
+	}
+	if err != nil {
+		return report, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	defer reader.Close()
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+
+	var cols columns
+	rowNumber := 0
+	inputs := make([]models.PersonInput, 0, chunkSize)
+	rowNumbers := make([]int, 0, chunkSize)
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		rowNumber++
+		if err != nil {
+			return report, im.abort(report, fmt.Errorf("%w: строка %d: %w", ErrInvalidFile, rowNumber, err))
+		}
+
+		if rowNumber == 1 {
+			var header bool
+			cols, header, err = resolveColumns(row, opts.Mapping)
+			if err != nil {
+				return report, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+			}
+			if header {
+				continue
+			}
+		}
+		if isEmptyRow(row) {
+			continue
+		}
+
+		inputs = append(inputs, models.PersonInput{
+			Name:       cell(row, cols.name),
+			Surname:    optionalCell(row, cols.surname),
+			Patronymic: optionalCell(row, cols.patronymic),
+		})
+		rowNumbers = append(rowNumbers, rowNumber)
+		if len(inputs) == chunkSize {
+			if err := im.flush(ctx, inputs, rowNumbers, opts.Enrich, report); err != nil {
+				return report, im.abort(report, err)
+			}
+			inputs, rowNumbers = inputs[:0], rowNumbers[:0]
+		}
+	}
+	if len(inputs) > 0 {
+		if err := im.flush(ctx, inputs, rowNumbers, opts.Enrich, report); err != nil {
+			return report, im.abort(report, err)
+		}
+	}
+
+	im.logger.Info("Импорт завершён",
+		zap.Int("rows", report.Rows), zap.Int("created", report.Created), zap.Int("failed", report.Failed))
+	return report, nil
+}
+
+// abort логирует импорт, прерванный ошибкой err после сохранения записей из report, и возвращает err.
+func (im *Importer) abort(report *Report, err error) error {
+	im.logger.Warn("Импорт прерван",
+		zap.Int("rows", report.Rows), zap.Int("created", report.Created), zap.Int("failed", report.Failed), zap.Error(err))
+	return err
+}
+
+// flush сохраняет накопленные строки одним пакетом в режиме partial и дополняет отчёт.
+func (im *Importer) flush(ctx context.Context, inputs []models.PersonInput, rowNumbers []int, enrich bool, report *Report) error {
+	result, err := im.service.CreateBatch(ctx, inputs, models.BatchModePartial, enrich)
+	if err != nil {
+		return fmt.Errorf("строки %d-%d: %w", rowNumbers[0], rowNumbers[len(rowNumbers)-1], err)
+	}
+	for _, item := range result.Items {
+		row := rowNumbers[item.Index]
+		if item.Status == models.BatchItemCreated {
+			report.Records = append(report.Records, CreatedRow{Row: row, ID: item.Person.ID})
+			continue
+		}
+		report.Errors = append(report.Errors, RowError{Row: row, Error: item.Error})
+	}
+	report.Rows += len(inputs)
+	report.Created += result.Created
+	report.Failed += len(inputs) - result.Created
+	return nil
+}
+
+// optionalCell возвращает значение колонки или nil, если оно пустое.
+func optionalCell(row []string, index int) *string {
+	value := cell(row, index)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// isEmptyRow проверяет, что в строке нет ни одного значения.
+func isEmptyRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
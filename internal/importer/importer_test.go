@@ -0,0 +1,77 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"person-service/internal/config"
+	"person-service/internal/models"
+	"person-service/internal/repository"
+	"person-service/internal/service"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// batchRepository сохраняет пакеты в памяти и возвращает failOn на пакете с номером failAt (с единицы).
+type batchRepository struct {
+	repository.PersonRepository
+	calls  int
+	nextID int
+	failAt int
+	failOn error
+}
+
+func (r *batchRepository) CreateBatch(_ context.Context, persons []*models.Person, _ bool) ([]error, error) {
+	r.calls++
+	if r.calls == r.failAt {
+		return nil, r.failOn
+	}
+	for _, person := range persons {
+		r.nextID++
+		person.ID = r.nextID
+	}
+	return make([]error, len(persons)), nil
+}
+
+func newTestImporter(repo repository.PersonRepository) *Importer {
+	svc := service.NewPersonService(repo, zap.NewNop(), &config.APIs{}, &config.Duplicates{}, &config.Enrichment{}, nil)
+	return NewImporter(svc, zap.NewNop())
+}
+
+func TestImportReturnsPartialReportOnFailure(t *testing.T) {
+	dbErr := errors.New("соединение с базой данных потеряно")
+	repo := &batchRepository{failAt: 2, failOn: dbErr}
+	file := "name,surname\nИван,Иванов\nПётр,Петров\nАнна,Смирнова\nОльга,Орлова\n"
+
+	report, err := newTestImporter(repo).Import(context.Background(), strings.NewReader(file), Options{Format: FormatCSV, ChunkSize: 2})
+	if !errors.Is(err, dbErr) {
+		t.Fatalf("ожидалась ошибка базы данных, получено %v", err)
+	}
+	if errors.Is(err, ErrInvalidFile) {
+		t.Fatal("ошибка базы данных отмечена как ошибка файла")
+	}
+	if report == nil {
+		t.Fatal("отчёт о сохранённых пакетах не возвращён")
+	}
+	want := []CreatedRow{{Row: 2, ID: 1}, {Row: 3, ID: 2}}
+	if report.Created != 2 || len(report.Records) != len(want) {
+		t.Fatalf("ожидалось 2 созданные записи, отчёт: %+v", report)
+	}
+	for i, record := range want {
+		if report.Records[i] != record {
+			t.Errorf("запись %d: ожидалось %+v, получено %+v", i, record, report.Records[i])
+		}
+	}
+}
+
+func TestImportMarksFileErrors(t *testing.T) {
+	repo := &batchRepository{}
+	_, err := newTestImporter(repo).Import(context.Background(), strings.NewReader("name\nИван\n"), Options{Format: FormatCSV, Encoding: "koi8-r"})
+	if !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("ожидалась ErrInvalidFile, получено %v", err)
+	}
+	if repo.calls != 0 {
+		t.Fatalf("сохранено %d пакетов при некорректном файле", repo.calls)
+	}
+}
@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// fieldAliases перечисляет заголовки колонок, которые распознаются для полей PersonInput.
+var fieldAliases = map[string][]string{
+	"name":       {"name", "first_name", "firstname", "имя"},
+	"surname":    {"surname", "last_name", "lastname", "фамилия"},
+	"patronymic": {"patronymic", "middle_name", "middlename", "отчество"},
+}
+
+// columns содержит индексы колонок для полей PersonInput; -1 означает отсутствие колонки.
+type columns struct {
+	name       int
+	surname    int
+	patronymic int
+}
+
+// ParseMapping разбирает сопоставление колонок вида "name=Имя,surname=2".
+// Колонка задаётся заголовком или номером, начиная с 1.
+func ParseMapping(value string) (map[string]string, error) {
+	mapping := map[string]string{}
+	if strings.TrimSpace(value) == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		column = strings.TrimSpace(column)
+		if !ok || column == "" {
+			return nil, fmt.Errorf("некорректное сопоставление колонок: %s", pair)
+		}
+		if _, known := fieldAliases[field]; !known {
+			return nil, fmt.Errorf("неизвестное поле в сопоставлении: %s", field)
+		}
+		mapping[field] = column
+	}
+	return mapping, nil
+}
+
+// resolveColumns определяет колонки полей по первой строке файла.
+// Возвращает признак того, что первая строка — заголовок и её не нужно импортировать.
+func resolveColumns(first []string, mapping map[string]string) (columns, bool, error) {
+	headers := make(map[string]int, len(first))
+	for i, cell := range first {
+		key := normalizeHeader(cell)
+		if _, exists := headers[key]; !exists {
+			headers[key] = i
+		}
+	}
+
+	resolved := map[string]int{"name": -1, "surname": -1, "patronymic": -1}
+	header := false
+	if len(mapping) > 0 {
+		for field, column := range mapping {
+			if number, err := strconv.Atoi(column); err == nil {
+				if number < 1 {
+					return columns{}, false, fmt.Errorf("номер колонки должен начинаться с 1: %s", column)
+				}
+				resolved[field] = number - 1
+				continue
+			}
+			index, ok := headers[normalizeHeader(column)]
+			if !ok {
+				return columns{}, false, fmt.Errorf("колонка %s не найдена в заголовке", column)
+			}
+			resolved[field] = index
+			header = true
+		}
+	} else {
+		for field, aliases := range fieldAliases {
+			for _, alias := range aliases {
+				if index, ok := headers[alias]; ok {
+					resolved[field] = index
+					header = true
+					break
+				}
+			}
+		}
+		if !header {
+			// Без заголовка колонки идут в порядке: имя, фамилия, отчество
+			resolved = map[string]int{"name": 0, "surname": 1, "patronymic": 2}
+		}
+	}
+
+	if resolved["name"] < 0 {
+		return columns{}, false, fmt.Errorf("не найдена колонка с именем")
+	}
+	return columns{
+		name:       resolved["name"],
+		surname:    resolved["surname"],
+		patronymic: resolved["patronymic"],
+	}, header, nil
+}
+
+// normalizeHeader приводит заголовок колонки к виду для сравнения.
+func normalizeHeader(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.ReplaceAll(value, " ", "_")
+}
+
+// cell возвращает значение колонки index или пустую строку, если колонки нет.
+func cell(row []string, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
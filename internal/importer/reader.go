@@ -0,0 +1,160 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Поддерживаемые кодировки CSV.
+const (
+	EncodingAuto        = "auto"
+	EncodingUTF8        = "utf-8"
+	EncodingWindows1251 = "windows-1251"
+)
+
+// sniffSize определяет, сколько байт читается для определения кодировки и разделителя CSV.
+const sniffSize = 64 * 1024
+
+// rowReader последовательно возвращает строки таблицы; в конце возвращает io.EOF.
+type rowReader interface {
+	Next() ([]string, error)
+	Close() error
+}
+
+// csvReader читает CSV построчно, не загружая файл в память целиком.
+type csvReader struct {
+	reader *csv.Reader
+}
+
+// newCSVReader создаёт потоковый читатель CSV, определяя кодировку и разделитель по началу файла.
+func newCSVReader(r io.Reader, encoding string) (*csvReader, error) {
+	buffered := bufio.NewReaderSize(r, sniffSize)
+	head, err := buffered.Peek(sniffSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+
+	var source io.Reader = buffered
+	switch strings.ToLower(encoding) {
+	case "", EncodingAuto:
+		if !validUTF8Prefix(head) {
+			source = charmap.Windows1251.NewDecoder().Reader(buffered)
+			head, _ = charmap.Windows1251.NewDecoder().Bytes(head)
+		}
+	case EncodingUTF8, "utf8":
+	case EncodingWindows1251, "cp1251":
+		source = charmap.Windows1251.NewDecoder().Reader(buffered)
+		head, _ = charmap.Windows1251.NewDecoder().Bytes(head)
+	default:
+		return nil, fmt.Errorf("неподдерживаемая кодировка: %s", encoding)
+	}
+
+	reader := csv.NewReader(skipBOM(source))
+	reader.Comma = detectDelimiter(head)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+	return &csvReader{reader: reader}, nil
+}
+
+// Next возвращает следующую строку CSV.
+func (c *csvReader) Next() ([]string, error) {
+	record, err := c.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Close ничего не делает: источник CSV закрывает вызывающая сторона.
+func (c *csvReader) Close() error {
+	return nil
+}
+
+// xlsxReader читает строки первого листа XLSX через потоковый итератор excelize.
+type xlsxReader struct {
+	file *excelize.File
+	rows *excelize.Rows
+}
+
+// newXLSXReader открывает книгу XLSX и первый лист для построчного чтения.
+func newXLSXReader(r io.Reader) (*xlsxReader, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть XLSX: %w", err)
+	}
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		file.Close()
+		return nil, fmt.Errorf("файл XLSX не содержит листов")
+	}
+	rows, err := file.Rows(sheets[0])
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("не удалось прочитать лист %s: %w", sheets[0], err)
+	}
+	return &xlsxReader{file: file, rows: rows}, nil
+}
+
+// Next возвращает следующую строку листа.
+func (x *xlsxReader) Next() ([]string, error) {
+	if !x.rows.Next() {
+		if err := x.rows.Error(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return x.rows.Columns()
+}
+
+// Close освобождает ресурсы книги.
+func (x *xlsxReader) Close() error {
+	if err := x.rows.Close(); err != nil {
+		x.file.Close()
+		return err
+	}
+	return x.file.Close()
+}
+
+// validUTF8Prefix проверяет, что начало файла — корректный UTF-8.
+// Последний символ может быть обрезан границей буфера, поэтому он не учитывается.
+func validUTF8Prefix(data []byte) bool {
+	for i := 0; i < utf8.UTFMax && len(data) > 0; i++ {
+		if utf8.Valid(data) {
+			return true
+		}
+		data = data[:len(data)-1]
+	}
+	return utf8.Valid(data)
+}
+
+// detectDelimiter выбирает разделитель CSV по первой строке: точка с запятой, табуляция или запятая.
+func detectDelimiter(head []byte) rune {
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i]
+	}
+	best, bestCount := ',', bytes.Count(head, []byte{','})
+	for _, candidate := range []rune{';', '\t'} {
+		if count := bytes.Count(head, []byte(string(candidate))); count > bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+	return best
+}
+
+// skipBOM пропускает метку порядка байтов UTF-8 в начале потока.
+func skipBOM(r io.Reader) io.Reader {
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		buffered.Discard(3)
+	}
+	return buffered
+}
@@ -0,0 +1,79 @@
+// Package metrics собирает метрики сервиса в формате Prometheus.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry содержит все метрики сервиса, включая метрики среды выполнения Go и процесса.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests считает HTTP-запросы по методу, шаблону маршрута и статусу ответа.
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Число HTTP-запросов.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPDuration измеряет длительность HTTP-запросов по методу, шаблону маршрута и статусу ответа.
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Длительность HTTP-запросов в секундах.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// EnrichmentDuration измеряет длительность запросов к API обогащения по провайдеру.
+	EnrichmentDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "enrichment_request_duration_seconds",
+		Help:    "Длительность запросов к API обогащения в секундах.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"provider"})
+
+	// EnrichmentErrors считает неуспешные запросы к API обогащения по провайдеру.
+	EnrichmentErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "enrichment_errors_total",
+		Help: "Число неуспешных запросов к API обогащения.",
+	}, []string{"provider"})
+
+	// EnrichmentCache считает обращения к кэшу обогащения: result=hit или miss.
+	EnrichmentCache = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "enrichment_cache_requests_total",
+		Help: "Число обращений к кэшу обогащения.",
+	}, []string{"result"})
+
+	// DBQueryDuration измеряет длительность запросов к Postgres по имени запроса из queries.sql.
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Длительность запросов к Postgres в секундах.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"query"})
+
+	// DBQueryErrors считает запросы к Postgres, завершившиеся ошибкой.
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Число запросов к Postgres, завершившихся ошибкой.",
+	}, []string{"query"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		EnrichmentDuration,
+		EnrichmentErrors,
+		EnrichmentCache,
+		DBQueryDuration,
+		DBQueryErrors,
+	)
+}
+
+// Handler возвращает обработчик, отдающий метрики в формате Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector отдаёт статистику пула соединений pgxpool в момент сбора метрик.
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired        *prometheus.Desc
+	idle            *prometheus.Desc
+	total           *prometheus.Desc
+	max             *prometheus.Desc
+	acquires        *prometheus.Desc
+	emptyAcquires   *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	canceled        *prometheus.Desc
+}
+
+// RegisterPool добавляет в Registry статистику пула соединений.
+func RegisterPool(pool *pgxpool.Pool) error {
+	return Registry.Register(&poolCollector{
+		pool:            pool,
+		acquired:        prometheus.NewDesc("pgxpool_acquired_conns", "Число соединений, занятых запросами.", nil, nil),
+		idle:            prometheus.NewDesc("pgxpool_idle_conns", "Число свободных соединений.", nil, nil),
+		total:           prometheus.NewDesc("pgxpool_total_conns", "Общее число соединений пула.", nil, nil),
+		max:             prometheus.NewDesc("pgxpool_max_conns", "Максимальный размер пула.", nil, nil),
+		acquires:        prometheus.NewDesc("pgxpool_acquires_total", "Число получений соединения из пула.", nil, nil),
+		emptyAcquires:   prometheus.NewDesc("pgxpool_empty_acquires_total", "Число получений соединения, которым пришлось ждать свободного соединения.", nil, nil),
+		acquireDuration: prometheus.NewDesc("pgxpool_acquire_wait_seconds_total", "Суммарное время ожидания соединения из пула в секундах.", nil, nil),
+		canceled:        prometheus.NewDesc("pgxpool_canceled_acquires_total", "Число получений соединения, отменённых контекстом.", nil, nil),
+	})
+}
+
+// Describe передаёт описания метрик пула.
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquired
+	ch <- c.idle
+	ch <- c.total
+	ch <- c.max
+	ch <- c.acquires
+	ch <- c.emptyAcquires
+	ch <- c.acquireDuration
+	ch <- c.canceled
+}
+
+// Collect передаёт текущую статистику пула.
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.canceled, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// APIKey представляет ключ API, хранящийся в базе в виде SHA-256 хэша.
+type APIKey struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+	// KeyHash — SHA-256 ключа в шестнадцатеричном виде; сам ключ не хранится.
+	KeyHash string   `db:"key_hash"`
+	Roles   []string `db:"roles"`
+	// TenantID — арендатор, к данным которого ограничен ключ; nil означает выбор арендатора заголовком.
+	TenantID   *string    `db:"tenant_id"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+}
+
+// Active сообщает, можно ли использовать ключ в момент now.
+func (k *APIKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil && !k.RevokedAt.After(now) {
+		return false
+	}
+	return k.ExpiresAt == nil || k.ExpiresAt.After(now)
+}
@@ -0,0 +1,70 @@
+package models
+
+import "fmt"
+
+// BatchMode определяет поведение пакетной операции при ошибке отдельного элемента.
+type BatchMode string
+
+const (
+	// BatchModeAllOrNothing отменяет весь пакет, если хотя бы один элемент не обработан.
+	BatchModeAllOrNothing BatchMode = "all_or_nothing"
+	// BatchModePartial сохраняет успешные элементы и сообщает об ошибках остальных.
+	BatchModePartial BatchMode = "partial"
+)
+
+// ParseBatchMode разбирает режим пакетной операции; пустое значение означает all_or_nothing.
+func ParseBatchMode(value string) (BatchMode, error) {
+	switch BatchMode(value) {
+	case "", BatchModeAllOrNothing:
+		return BatchModeAllOrNothing, nil
+	case BatchModePartial:
+		return BatchModePartial, nil
+	default:
+		return "", fmt.Errorf("неподдерживаемый режим: %s, ожидается all_or_nothing или partial", value)
+	}
+}
+
+// BatchItemStatus представляет результат обработки одного элемента пакета.
+type BatchItemStatus string
+
+const (
+	BatchItemCreated BatchItemStatus = "created"
+	BatchItemFailed  BatchItemStatus = "failed"
+	// BatchItemSkipped означает, что элемент корректен, но не сохранён из-за отмены пакета.
+	BatchItemSkipped BatchItemStatus = "skipped"
+)
+
+// BatchItemResult содержит результат обработки одного элемента пакета.
+type BatchItemResult struct {
+	Index  int             `json:"index"`
+	Status BatchItemStatus `json:"status"`
+	Person *Person         `json:"person,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// BatchResult содержит результаты пакетного создания записей в порядке входных данных.
+type BatchResult struct {
+	Mode    BatchMode         `json:"mode"`
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Items   []BatchItemResult `json:"items"`
+}
+
+// BulkResult содержит результат массового изменения или удаления записей по фильтру.
+// При предпросмотре (DryRun) Matched содержит число записей, которые будут затронуты.
+type BulkResult struct {
+	DryRun   bool `json:"dry_run"`
+	Matched  int  `json:"matched"`
+	Affected int  `json:"affected"`
+	MaxRows  int  `json:"max_rows"`
+}
+
+// ReenrichResult содержит итог повторного обогащения устаревших записей.
+type ReenrichResult struct {
+	// Checked — число записей, для которых запрошено обогащение.
+	Checked int `json:"checked"`
+	// Updated — число записей, данные которых изменились.
+	Updated int `json:"updated"`
+	// Failed — число записей, которые не удалось обогатить или сохранить.
+	Failed int `json:"failed"`
+}
@@ -0,0 +1,225 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// DuplicateCandidate представляет запись, похожую на проверяемую.
+type DuplicateCandidate struct {
+	Person *Person `json:"person"`
+	// Score — итоговая оценка от 0 до 1: среднее триграммного сходства и совпадения нормализованных полей.
+	Score float64 `json:"score"`
+	// Similarity — триграммное сходство полного имени.
+	Similarity float64 `json:"similarity"`
+	// MatchedFields перечисляет поля, совпавшие после нормализации.
+	MatchedFields []string `json:"matched_fields"`
+}
+
+// duplicateFieldWeights задаёт вес совпадения каждого поля в оценке дубликата.
+var duplicateFieldWeights = []struct {
+	field  string
+	weight float64
+	value  func(p *Person) string
+}{
+	{"surname", 0.4, func(p *Person) string { return stringOrEmpty(p.Surname) }},
+	{"name", 0.35, func(p *Person) string { return p.Name }},
+	{"patronymic", 0.25, func(p *Person) string { return stringOrEmpty(p.Patronymic) }},
+}
+
+// NormalizeNamePart приводит часть имени к виду для сравнения:
+// нижний регистр, ё заменена на е, лишние пробелы удалены.
+func NormalizeNamePart(value string) string {
+	value = strings.ToLower(strings.Join(strings.Fields(value), " "))
+	return strings.ReplaceAll(value, "ё", "е")
+}
+
+// ScoreDuplicate оценивает, насколько candidate похож на person.
+// similarity — триграммное сходство полных имён, посчитанное в базе.
+// Поле, пустое у обеих записей, в оценке не учитывается.
+func ScoreDuplicate(person, candidate *Person, similarity float64) (float64, []string) {
+	matched := []string{}
+	var total, score float64
+	for _, fw := range duplicateFieldWeights {
+		a, b := NormalizeNamePart(fw.value(person)), NormalizeNamePart(fw.value(candidate))
+		if a == "" && b == "" {
+			continue
+		}
+		total += fw.weight
+		if a == b {
+			score += fw.weight
+			matched = append(matched, fw.field)
+		}
+	}
+	fieldScore := 0.0
+	if total > 0 {
+		fieldScore = score / total
+	}
+	return math.Round((similarity+fieldScore)/2*1000) / 1000, matched
+}
+
+// SurvivorshipRule определяет, из какой записи берётся значение поля при слиянии.
+type SurvivorshipRule string
+
+const (
+	// RuleFirstNonEmpty берёт первое непустое значение: из целевой записи, затем из источников по порядку.
+	RuleFirstNonEmpty SurvivorshipRule = "first_non_empty"
+	// RuleTarget оставляет значение целевой записи, даже пустое.
+	RuleTarget SurvivorshipRule = "target"
+	// RuleMostRecent берёт непустое значение из записи, изменённой последней.
+	RuleMostRecent SurvivorshipRule = "most_recent"
+	// RuleLongest берёт самую длинную строку.
+	RuleLongest SurvivorshipRule = "longest"
+	// RuleMax и RuleMin берут наибольшее и наименьшее значение возраста.
+	RuleMax SurvivorshipRule = "max"
+	RuleMin SurvivorshipRule = "min"
+)
+
+// mergeFields перечисляет поля, значения которых выбираются при слиянии.
+var mergeFields = []string{"name", "surname", "patronymic", "age", "gender", "nationality"}
+
+// MergeRequest описывает слияние записей-источников в целевую запись.
+// Источники помечаются удалёнными, целевая запись получает значения по правилам Rules.
+type MergeRequest struct {
+	TargetID  int                         `json:"target_id"`
+	SourceIDs []int                       `json:"source_ids"`
+	Rules     map[string]SurvivorshipRule `json:"rules,omitempty" swaggertype:"object,string"`
+}
+
+// Validate проверяет корректность запроса на слияние.
+func (mr *MergeRequest) Validate() error {
+	if mr.TargetID <= 0 {
+		return fmt.Errorf("target_id должен быть положительным")
+	}
+	if len(mr.SourceIDs) == 0 {
+		return fmt.Errorf("source_ids не может быть пустым")
+	}
+	seen := map[int]bool{mr.TargetID: true}
+	for _, id := range mr.SourceIDs {
+		if id <= 0 {
+			return fmt.Errorf("source_ids должны быть положительными")
+		}
+		if seen[id] {
+			return fmt.Errorf("id %d указан несколько раз", id)
+		}
+		seen[id] = true
+	}
+	for field, rule := range mr.Rules {
+		if !patchableFields[field] {
+			return fmt.Errorf("правило для несуществующего поля: %s", field)
+		}
+		switch rule {
+		case RuleFirstNonEmpty, RuleTarget, RuleMostRecent:
+		case RuleLongest:
+			if field == "age" {
+				return fmt.Errorf("правило %s неприменимо к полю age", rule)
+			}
+		case RuleMax, RuleMin:
+			if field != "age" {
+				return fmt.Errorf("правило %s применимо только к полю age", rule)
+			}
+		default:
+			return fmt.Errorf("неизвестное правило для поля %s: %s", field, rule)
+		}
+	}
+	return nil
+}
+
+// MergePersons записывает в target значения полей, выбранные по правилам rules среди target и sources.
+// Для полей без правила используется RuleFirstNonEmpty.
+func MergePersons(target *Person, sources []*Person, rules map[string]SurvivorshipRule) error {
+	records := append([]*Person{target}, sources...)
+	docs := make([]map[string]json.RawMessage, len(records))
+	for i, record := range records {
+		doc, err := personDocument(record)
+		if err != nil {
+			return err
+		}
+		docs[i] = doc
+	}
+
+	result := docs[0]
+	for _, field := range mergeFields {
+		rule := rules[field]
+		if rule == "" {
+			rule = RuleFirstNonEmpty
+		}
+		value, err := surviving(field, rule, records, docs)
+		if err != nil {
+			return err
+		}
+		result[field] = value
+	}
+	return decodePersonDocument(result, target)
+}
+
+// surviving выбирает значение поля по правилу rule; records[0] — целевая запись.
+func surviving(field string, rule SurvivorshipRule, records []*Person, docs []map[string]json.RawMessage) (json.RawMessage, error) {
+	best := -1
+	for i, doc := range docs {
+		value := doc[field]
+		if isEmptyValue(value) {
+			continue
+		}
+		if best < 0 {
+			best = i
+			continue
+		}
+		switch rule {
+		case RuleMostRecent:
+			if lastChanged(records[i]).After(lastChanged(records[best])) {
+				best = i
+			}
+		case RuleLongest:
+			if len([]rune(stringValue(value))) > len([]rune(stringValue(docs[best][field]))) {
+				best = i
+			}
+		case RuleMax, RuleMin:
+			var current, candidate int
+			if err := json.Unmarshal(docs[best][field], &current); err != nil {
+				return nil, fmt.Errorf("некорректное значение поля %s: %w", field, err)
+			}
+			if err := json.Unmarshal(value, &candidate); err != nil {
+				return nil, fmt.Errorf("некорректное значение поля %s: %w", field, err)
+			}
+			if (rule == RuleMax && candidate > current) || (rule == RuleMin && candidate < current) {
+				best = i
+			}
+		}
+	}
+	if rule == RuleTarget || best < 0 {
+		return docs[0][field], nil
+	}
+	return docs[best][field], nil
+}
+
+// isEmptyValue проверяет, пусто ли JSON-значение поля: null, отсутствует или пустая строка.
+func isEmptyValue(value json.RawMessage) bool {
+	return len(value) == 0 || string(value) == "null" || string(value) == `""`
+}
+
+// stringValue возвращает строковое JSON-значение или пустую строку.
+func stringValue(value json.RawMessage) string {
+	var s string
+	_ = json.Unmarshal(value, &s)
+	return s
+}
+
+// lastChanged возвращает время последнего изменения записи.
+func lastChanged(p *Person) time.Time {
+	if p.UpdatedAt != nil {
+		return *p.UpdatedAt
+	}
+	return p.CreatedAt
+}
+
+// stringOrEmpty возвращает значение строки или пустую строку для nil.
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
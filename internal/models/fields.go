@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PersonFields перечисляет поля Person в порядке их вывода.
+var PersonFields = []string{"id", "name", "surname", "patronymic", "age", "gender", "nationality", "created_at", "updated_at", "version", "deleted_at"}
+
+// ParseFields разбирает список полей из параметра fields, например "id,name,surname".
+// Пустое значение означает все поля и возвращает nil. Поля возвращаются в порядке PersonFields.
+func ParseFields(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	requested := map[string]bool{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !isPersonField(name) {
+			return nil, fmt.Errorf("неизвестное поле: %s", name)
+		}
+		requested[name] = true
+	}
+	if len(requested) == 0 {
+		return nil, nil
+	}
+	fields := make([]string, 0, len(requested))
+	for _, name := range PersonFields {
+		if requested[name] {
+			fields = append(fields, name)
+		}
+	}
+	return fields, nil
+}
+
+// WithField добавляет поле к выборке, если его там нет; nil означает все поля и не изменяется.
+func WithField(fields []string, name string) []string {
+	if fields == nil {
+		return nil
+	}
+	for _, field := range fields {
+		if field == name {
+			return fields
+		}
+	}
+	return append(append([]string{}, fields...), name)
+}
+
+// FieldValue возвращает значение поля записи; для незаполненных полей возвращается nil.
+func (p *Person) FieldValue(name string) any {
+	switch name {
+	case "id":
+		return p.ID
+	case "name":
+		return p.Name
+	case "surname":
+		return derefOrNil(p.Surname)
+	case "patronymic":
+		return derefOrNil(p.Patronymic)
+	case "age":
+		return derefOrNil(p.Age)
+	case "gender":
+		return derefOrNil(p.Gender)
+	case "nationality":
+		return derefOrNil(p.Nationality)
+	case "created_at":
+		return p.CreatedAt
+	case "updated_at":
+		return derefOrNil(p.UpdatedAt)
+	case "version":
+		return p.Version
+	case "deleted_at":
+		return derefOrNil(p.DeletedAt)
+	default:
+		return nil
+	}
+}
+
+// isPersonField проверяет, есть ли поле в PersonFields.
+func isPersonField(name string) bool {
+	for _, field := range PersonFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// derefOrNil возвращает значение указателя или nil.
+func derefOrNil[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// HistoryAction представляет тип изменения записи в истории.
+type HistoryAction string
+
+const (
+	ActionCreate  HistoryAction = "create"
+	ActionUpdate  HistoryAction = "update"
+	ActionPatch   HistoryAction = "patch"
+	ActionDelete  HistoryAction = "delete"
+	ActionRestore HistoryAction = "restore"
+	ActionEnrich  HistoryAction = "enrich"
+	ActionMerge   HistoryAction = "merge"
+)
+
+// SourceEnrichment обозначает изменения, полученные из внешних API обогащения.
+const SourceEnrichment = "enrichment"
+
+// EnrichedFields перечисляет поля, которые заполняются через внешние API.
+var EnrichedFields = []string{"age", "gender", "nationality"}
+
+// historyFields перечисляет поля, изменения которых записываются в историю.
+var historyFields = []string{"name", "surname", "patronymic", "age", "gender", "nationality", "deleted_at"}
+
+// HistoryEntry представляет одно изменение записи о человеке.
+// Before и After содержат только изменённые поля.
+type HistoryEntry struct {
+	ID        int64           `json:"id" db:"id"`
+	PersonID  int             `json:"person_id" db:"person_id"`
+	Action    HistoryAction   `json:"action" db:"action"`
+	Actor     *string         `json:"actor" db:"actor"`
+	RequestID *string         `json:"request_id" db:"request_id"`
+	Source    string          `json:"source" db:"source"`
+	Before    json.RawMessage `json:"before" db:"before" swaggertype:"object"`
+	After     json.RawMessage `json:"after" db:"after" swaggertype:"object"`
+	Version   int             `json:"version" db:"version"`
+	ChangedAt time.Time       `json:"changed_at" db:"changed_at"`
+}
+
+// DiffPersons возвращает значения изменившихся полей до и после изменения.
+// Если before равен nil, в after попадают все заполненные поля after.
+func DiffPersons(before, after *Person) (map[string]json.RawMessage, map[string]json.RawMessage, error) {
+	afterDoc, err := personDocument(after)
+	if err != nil {
+		return nil, nil, err
+	}
+	beforeDoc := map[string]json.RawMessage{}
+	if before != nil {
+		if beforeDoc, err = personDocument(before); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	oldValues := map[string]json.RawMessage{}
+	newValues := map[string]json.RawMessage{}
+	for _, field := range historyFields {
+		oldValue, newValue := nullIfMissing(beforeDoc[field]), nullIfMissing(afterDoc[field])
+		equal, err := jsonEqual(oldValue, newValue)
+		if err != nil {
+			return nil, nil, err
+		}
+		if equal {
+			continue
+		}
+		if before != nil {
+			oldValues[field] = oldValue
+		}
+		newValues[field] = newValue
+	}
+	return oldValues, newValues, nil
+}
+
+// RevertChange возвращает запись к состоянию до изменения entry.
+func RevertChange(p *Person, entry *HistoryEntry) error {
+	var before map[string]json.RawMessage
+	if len(entry.Before) > 0 {
+		if err := json.Unmarshal(entry.Before, &before); err != nil {
+			return fmt.Errorf("некорректная запись истории %d: %w", entry.ID, err)
+		}
+	}
+	doc, err := personDocument(p)
+	if err != nil {
+		return err
+	}
+	for field, value := range before {
+		doc[field] = value
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать документ: %w", err)
+	}
+	var reverted Person
+	if err := json.Unmarshal(data, &reverted); err != nil {
+		return fmt.Errorf("некорректная запись истории %d: %w", entry.ID, err)
+	}
+	p.Name = reverted.Name
+	p.Surname = reverted.Surname
+	p.Patronymic = reverted.Patronymic
+	p.Age = reverted.Age
+	p.Gender = reverted.Gender
+	p.Nationality = reverted.Nationality
+	p.DeletedAt = reverted.DeletedAt
+	return nil
+}
+
+// nullIfMissing заменяет отсутствующее значение на JSON null.
+func nullIfMissing(value json.RawMessage) json.RawMessage {
+	if len(value) == 0 {
+		return json.RawMessage("null")
+	}
+	return value
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// IdempotencyRecord представляет запрос, выполненный с заголовком Idempotency-Key, и сохранённый ответ на него.
+type IdempotencyRecord struct {
+	Key         string `db:"key"`
+	RequestHash string `db:"request_hash"`
+	// StatusCode равен нулю, пока запрос выполняется.
+	StatusCode int               `db:"status_code"`
+	Headers    map[string]string `db:"headers"`
+	Body       []byte            `db:"body"`
+	CreatedAt  time.Time         `db:"created_at"`
+	ExpiresAt  time.Time         `db:"expires_at"`
+}
+
+// Completed сообщает, сохранён ли ответ на запрос.
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Типы содержимого, поддерживаемые PATCH-запросами.
+const (
+	ContentTypeMergePatch = "application/merge-patch+json"
+	ContentTypeJSONPatch  = "application/json-patch+json"
+)
+
+// ErrPatchTestFailed возвращается, если операция test из JSON Patch не прошла.
+var ErrPatchTestFailed = errors.New("проверка test не пройдена")
+
+// patchableFields перечисляет поля Person, которые можно изменять через PATCH.
+var patchableFields = map[string]bool{
+	"name":        true,
+	"surname":     true,
+	"patronymic":  true,
+	"age":         true,
+	"gender":      true,
+	"nationality": true,
+}
+
+// readOnlyFields перечисляет поля Person, которые клиент не может изменить.
+var readOnlyFields = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"version":    true,
+	"deleted_at": true,
+}
+
+// PatchOperation представляет одну операцию JSON Patch (RFC 6902).
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Validate проверяет корректность операции JSON Patch.
+func (op *PatchOperation) Validate() error {
+	switch op.Op {
+	case "test", "replace":
+		if len(op.Value) == 0 {
+			return fmt.Errorf("операция %s требует value", op.Op)
+		}
+	case "remove":
+	default:
+		return fmt.Errorf("неподдерживаемая операция: %s", op.Op)
+	}
+	if _, err := op.field(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// field возвращает имя поля Person, на которое указывает path операции.
+func (op *PatchOperation) field() (string, error) {
+	if !strings.HasPrefix(op.Path, "/") {
+		return "", fmt.Errorf("некорректный path: %s", op.Path)
+	}
+	name := strings.TrimPrefix(op.Path, "/")
+	if readOnlyFields[name] && op.Op == "test" {
+		return name, nil
+	}
+	if !patchableFields[name] {
+		return "", fmt.Errorf("поле недоступно для изменения: %s", op.Path)
+	}
+	return name, nil
+}
+
+// StripReadOnlyFields удаляет из документа поля, которые управляются сервером.
+// Поле id допускается, только если совпадает с ID записи из пути запроса.
+func StripReadOnlyFields(doc map[string]json.RawMessage, id int) error {
+	if raw, ok := doc["id"]; ok {
+		var docID int
+		if err := json.Unmarshal(raw, &docID); err != nil || docID != id {
+			return fmt.Errorf("id в теле запроса не совпадает с id в пути")
+		}
+	}
+	for key := range readOnlyFields {
+		delete(doc, key)
+	}
+	return nil
+}
+
+// ApplyMergePatch применяет JSON Merge Patch (RFC 7396) к записи.
+// Значение null очищает поле, read-only поля игнорируются.
+func ApplyMergePatch(p *Person, patch map[string]json.RawMessage) error {
+	if len(patch) == 0 {
+		return fmt.Errorf("не указано ни одного поля для обновления")
+	}
+	doc, err := personDocument(p)
+	if err != nil {
+		return err
+	}
+	for key, value := range patch {
+		if readOnlyFields[key] {
+			continue
+		}
+		if !patchableFields[key] {
+			return fmt.Errorf("указано несуществующее поле: %s", key)
+		}
+		doc[key] = value
+	}
+	return decodePersonDocument(doc, p)
+}
+
+// ApplyJSONPatch применяет последовательность операций JSON Patch (RFC 6902) к записи.
+// Если любая операция не выполнена, запись не изменяется.
+func ApplyJSONPatch(p *Person, ops []PatchOperation) error {
+	if len(ops) == 0 {
+		return fmt.Errorf("не указано ни одной операции")
+	}
+	doc, err := personDocument(p)
+	if err != nil {
+		return err
+	}
+	for i := range ops {
+		op := &ops[i]
+		if err := op.Validate(); err != nil {
+			return fmt.Errorf("операция %d: %w", i, err)
+		}
+		name, _ := op.field()
+		switch op.Op {
+		case "test":
+			equal, err := jsonEqual(doc[name], op.Value)
+			if err != nil {
+				return fmt.Errorf("операция %d: %w", i, err)
+			}
+			if !equal {
+				return fmt.Errorf("операция %d (%s): %w", i, op.Path, ErrPatchTestFailed)
+			}
+		case "replace":
+			doc[name] = op.Value
+		case "remove":
+			doc[name] = json.RawMessage("null")
+		}
+	}
+	return decodePersonDocument(doc, p)
+}
+
+// personDocument представляет запись в виде JSON-документа.
+func personDocument(p *Person) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось сериализовать запись: %w", err)
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("не удалось разобрать запись: %w", err)
+	}
+	return doc, nil
+}
+
+// decodePersonDocument записывает изменённые поля документа обратно в запись.
+func decodePersonDocument(doc map[string]json.RawMessage, p *Person) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать документ: %w", err)
+	}
+	var patched Person
+	if err := json.Unmarshal(data, &patched); err != nil {
+		return fmt.Errorf("некорректное значение поля: %w", err)
+	}
+	p.Name = patched.Name
+	p.Surname = patched.Surname
+	p.Patronymic = patched.Patronymic
+	p.Age = patched.Age
+	p.Gender = patched.Gender
+	p.Nationality = patched.Nationality
+	return nil
+}
+
+// jsonEqual сравнивает два JSON-значения без учёта форматирования.
+func jsonEqual(a, b json.RawMessage) (bool, error) {
+	if bytes.Equal(a, b) {
+		return true, nil
+	}
+	var va, vb any
+	if len(a) == 0 {
+		a = json.RawMessage("null")
+	}
+	if err := json.Unmarshal(a, &va); err != nil {
+		return false, fmt.Errorf("некорректное значение: %w", err)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		return false, fmt.Errorf("некорректное значение: %w", err)
+	}
+	return reflect.DeepEqual(va, vb), nil
+}
@@ -15,32 +15,45 @@ const (
 
 // Person представляет запись о человеке в базе данных.
 type Person struct {
-	ID          int         `json:"id" db:"id"`
-	Name        string      `json:"name" db:"name"`
-	Surname     *string     `json:"surname" db:"surname"`
-	Patronymic  *string     `json:"patronymic" db:"patronymic"`
-	Age         *int        `json:"age" db:"age"`
-	Gender      *GenderType `json:"gender" db:"gender"`
-	Nationality *string     `json:"nationality" db:"nationality"`
-	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
-	UpdatedAt   *time.Time  `json:"updated_at" db:"updated_at"`
+	ID          int         `json:"id" xml:"id" db:"id"`
+	Name        string      `json:"name" xml:"name" db:"name"`
+	Surname     *string     `json:"surname" xml:"surname" db:"surname"`
+	Patronymic  *string     `json:"patronymic" xml:"patronymic" db:"patronymic"`
+	Age         *int        `json:"age" xml:"age" db:"age"`
+	Gender      *GenderType `json:"gender" xml:"gender" db:"gender"`
+	Nationality *string     `json:"nationality" xml:"nationality" db:"nationality"`
+	CreatedAt   time.Time   `json:"created_at" xml:"created_at" db:"created_at"`
+	UpdatedAt   *time.Time  `json:"updated_at" xml:"updated_at" db:"updated_at"`
+	Version     int         `json:"version" xml:"version" db:"version"`
+	DeletedAt   *time.Time  `json:"deleted_at,omitempty" xml:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 // PersonInput представляет входные данные для создания человека.
 type PersonInput struct {
-	Name       string  `json:"name"`
-	Surname    *string `json:"surname"`
-	Patronymic *string `json:"patronymic"`
+	Name       string  `json:"name" xml:"name"`
+	Surname    *string `json:"surname" xml:"surname"`
+	Patronymic *string `json:"patronymic" xml:"patronymic"`
+}
+
+// PersonReplace представляет входные данные для полной замены записи через PUT.
+// Поля id, created_at, updated_at и version управляются сервером и в теле запроса игнорируются.
+type PersonReplace struct {
+	Name        string      `json:"name" xml:"name"`
+	Surname     *string     `json:"surname" xml:"surname"`
+	Patronymic  *string     `json:"patronymic" xml:"patronymic"`
+	Age         *int        `json:"age" xml:"age"`
+	Gender      *GenderType `json:"gender" xml:"gender"`
+	Nationality *string     `json:"nationality" xml:"nationality"`
 }
 
 // PersonUpdate представляет входные данные для обновления человека.
 type PersonUpdate struct {
-	Name        *string     `json:"name"`
-	Surname     *string     `json:"surname"`
-	Patronymic  *string     `json:"patronymic"`
-	Age         *int        `json:"age"`
-	Gender      *GenderType `json:"gender"`
-	Nationality *string     `json:"nationality"`
+	Name        *string     `json:"name" xml:"name"`
+	Surname     *string     `json:"surname" xml:"surname"`
+	Patronymic  *string     `json:"patronymic" xml:"patronymic"`
+	Age         *int        `json:"age" xml:"age"`
+	Gender      *GenderType `json:"gender" xml:"gender"`
+	Nationality *string     `json:"nationality" xml:"nationality"`
 }
 
 // Validate проверяет корректность данных Person.
@@ -57,6 +70,69 @@ func (p *Person) Validate() error {
 	return nil
 }
 
+// SameData проверяет, совпадают ли изменяемые поля двух записей.
+func (p *Person) SameData(other *Person) bool {
+	return p.Name == other.Name &&
+		equalPtr(p.Surname, other.Surname) &&
+		equalPtr(p.Patronymic, other.Patronymic) &&
+		equalPtr(p.Age, other.Age) &&
+		equalPtr(p.Gender, other.Gender) &&
+		equalPtr(p.Nationality, other.Nationality)
+}
+
+// ToPerson преобразует PersonReplace в запись с указанным ID.
+func (pr *PersonReplace) ToPerson(id int) *Person {
+	return &Person{
+		ID:          id,
+		Name:        pr.Name,
+		Surname:     pr.Surname,
+		Patronymic:  pr.Patronymic,
+		Age:         pr.Age,
+		Gender:      pr.Gender,
+		Nationality: pr.Nationality,
+	}
+}
+
+// ToPerson преобразует PersonInput в новую запись без данных обогащения.
+func (pi *PersonInput) ToPerson(createdAt time.Time) *Person {
+	return &Person{
+		Name:       pi.Name,
+		Surname:    pi.Surname,
+		Patronymic: pi.Patronymic,
+		CreatedAt:  createdAt,
+	}
+}
+
+// Apply переносит заданные поля PersonUpdate в запись.
+func (pu *PersonUpdate) Apply(p *Person) {
+	if pu.Name != nil {
+		p.Name = *pu.Name
+	}
+	if pu.Surname != nil {
+		p.Surname = pu.Surname
+	}
+	if pu.Patronymic != nil {
+		p.Patronymic = pu.Patronymic
+	}
+	if pu.Age != nil {
+		p.Age = pu.Age
+	}
+	if pu.Gender != nil {
+		p.Gender = pu.Gender
+	}
+	if pu.Nationality != nil {
+		p.Nationality = pu.Nationality
+	}
+}
+
+// equalPtr сравнивает значения двух указателей, считая nil равным только nil.
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 // Validate проверяет корректность данных PersonInput.
 func (pi *PersonInput) Validate() error {
 	if pi.Name == "" {
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// bucket — ведро клиента: число токенов на момент updated.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// MemoryStore хранит ведра в памяти процесса; у каждого экземпляра сервиса свои ведра.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+// NewMemoryStore создаёт хранилище ведер в памяти.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// Take забирает токен из ведра key, предварительно пополнив его за время с прошлого запроса.
+func (m *MemoryStore) Take(_ context.Context, key string, rate float64, burst int) (float64, bool, error) {
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), updated: now}
+		m.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+	if b.tokens < 1 {
+		return b.tokens, false, nil
+	}
+	b.tokens--
+	return b.tokens, true, nil
+}
+
+// PurgeIdle удаляет ведра, не использовавшиеся дольше idle.
+func (m *MemoryStore) PurgeIdle(_ context.Context, idle time.Duration) (int64, error) {
+	before := m.now().Add(-idle)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var purged int64
+	for key, b := range m.buckets {
+		if b.updated.Before(before) {
+			delete(m.buckets, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
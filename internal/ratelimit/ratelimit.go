@@ -0,0 +1,189 @@
+// Package ratelimit ограничивает частоту запросов клиентов алгоритмом token bucket.
+//
+// Каждый клиент получает отдельное ведро на каждый класс маршрутов. Ведро вмещает Burst токенов
+// и пополняется на Requests токенов за Period; запрос забирает один токен и отклоняется, если токенов нет.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"person-service/internal/auth"
+	"person-service/internal/config"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Class — класс маршрутов с общим лимитом.
+type Class string
+
+const (
+	ClassRead   Class = "read"
+	ClassWrite  Class = "write"
+	ClassCreate Class = "create"
+	ClassBulk   Class = "bulk"
+)
+
+// Store хранит ведра клиентов.
+type Store interface {
+	// Take забирает токен из ведра key, которое пополняется на rate токенов в секунду до ёмкости burst.
+	// Возвращает число токенов, оставшихся в ведре, и allowed=false, если токена не было.
+	Take(ctx context.Context, key string, rate float64, burst int) (tokens float64, allowed bool, err error)
+	// PurgeIdle удаляет ведра, не использовавшиеся дольше idle.
+	PurgeIdle(ctx context.Context, idle time.Duration) (int64, error)
+}
+
+// Result — решение по запросу и состояние ведра клиента.
+type Result struct {
+	// Allowed сообщает, разрешён ли запрос.
+	Allowed bool
+	// Rule — правило класса маршрутов.
+	Rule config.RateLimitRule
+	// Remaining — число запросов, которые можно выполнить сразу.
+	Remaining int
+	// Reset — время до полного пополнения ведра.
+	Reset time.Duration
+	// RetryAfter — время до появления токена; ноль, если запрос разрешён.
+	RetryAfter time.Duration
+}
+
+// LimitedError возвращается операцией внутри запроса, которой не хватило токена класса Class.
+type LimitedError struct {
+	Class  Class
+	Result Result
+}
+
+func (e *LimitedError) Error() string {
+	return fmt.Sprintf("превышен лимит запросов %s, повторите через %d с", e.Class, int(e.Result.RetryAfter.Seconds()))
+}
+
+// Limiter ограничивает частоту запросов по правилам классов маршрутов.
+type Limiter struct {
+	rules    map[Class]config.RateLimitRule
+	store    Store
+	fallback *MemoryStore
+	logger   *zap.Logger
+}
+
+// NewLimiter создаёт ограничитель с ведрами в store.
+// Если store недоступен, запросы ограничиваются ведрами в памяти экземпляра, а не отклоняются.
+func NewLimiter(cfg *config.RateLimit, store Store, logger *zap.Logger) *Limiter {
+	l := &Limiter{
+		rules: map[Class]config.RateLimitRule{
+			ClassRead:   cfg.Read,
+			ClassWrite:  cfg.Write,
+			ClassCreate: cfg.Create,
+			ClassBulk:   cfg.Bulk,
+		},
+		store:  store,
+		logger: logger,
+	}
+	if memory, ok := store.(*MemoryStore); ok {
+		l.fallback = memory
+	} else {
+		l.fallback = NewMemoryStore()
+	}
+	return l
+}
+
+// Allow забирает токен из ведра клиента client для класса class.
+func (l *Limiter) Allow(ctx context.Context, class Class, client string) Result {
+	rule := l.rules[class]
+	rate := rule.Rate()
+	key := string(class) + ":" + client
+
+	tokens, allowed, err := l.store.Take(ctx, key, rate, rule.Burst)
+	if err != nil {
+		l.logger.Warn("Хранилище ограничения запросов недоступно, используются ведра в памяти", zap.Error(err))
+		tokens, allowed, _ = l.fallback.Take(ctx, key, rate, rule.Burst)
+	}
+
+	result := Result{
+		Allowed:   allowed,
+		Rule:      rule,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     seconds((float64(rule.Burst) - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	return result
+}
+
+// scopeKey — ключ контекста с ограничителем и клиентом запроса.
+type scopeKey struct{}
+
+// scope — ограничитель и клиент запроса.
+type scope struct {
+	limiter *Limiter
+	client  string
+}
+
+// WithScope сохраняет в контексте ограничитель и клиента запроса, чтобы обработчик мог расходовать
+// токены на отдельные операции внутри запроса, например на каждую мутацию createPerson в GraphQL.
+func WithScope(ctx context.Context, limiter *Limiter, client string) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope{limiter: limiter, client: client})
+}
+
+// AllowContext забирает токен класса class из ведра клиента, сохранённого в контексте через WithScope.
+// Без ограничителя в контексте операция разрешается.
+func AllowContext(ctx context.Context, class Class) Result {
+	s, ok := ctx.Value(scopeKey{}).(scope)
+	if !ok {
+		return Result{Allowed: true}
+	}
+	return s.limiter.Allow(ctx, class, s.client)
+}
+
+// ClientKey возвращает ключ клиента: субъект JWT или имя ключа API из контекста,
+// а без аутентификации — IP-адрес ip.
+func ClientKey(ctx context.Context, ip string) string {
+	if principal := auth.FromContext(ctx); principal != nil {
+		return "sub:" + principal.Subject
+	}
+	return "ip:" + ip
+}
+
+// RunCleanup периодически удаляет ведра, которые успели пополниться полностью, до отмены ctx.
+func (l *Limiter) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	idle := l.maxRefill()
+	for {
+		purged, err := l.store.PurgeIdle(ctx, idle)
+		if err != nil {
+			l.logger.Error("Ошибка очистки ведер ограничения запросов", zap.Error(err))
+		} else if purged > 0 {
+			l.logger.Debug("Неиспользуемые ведра ограничения запросов удалены", zap.Int64("count", purged))
+		}
+		if l.fallback != l.store {
+			l.fallback.PurgeIdle(ctx, idle)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// maxRefill возвращает наибольшее время пополнения пустого ведра: более старые ведра полны,
+// и их удаление не меняет лимиты.
+func (l *Limiter) maxRefill() time.Duration {
+	var longest time.Duration
+	for _, rule := range l.rules {
+		if d := seconds(float64(rule.Burst) / rule.Rate()); d > longest {
+			longest = d
+		}
+	}
+	return longest
+}
+
+// seconds переводит секунды в длительность, округляя вверх до целой секунды.
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(s)) * time.Second
+}
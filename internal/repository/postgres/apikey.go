@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"person-service/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// APIKeyRepository хранит хэши ключей API в таблице api_keys.
+type APIKeyRepository struct {
+	db      *pgxpool.Pool
+	queries map[string]string
+}
+
+// NewAPIKeyRepository создаёт новый репозиторий ключей API.
+func NewAPIKeyRepository(db *pgxpool.Pool) (*APIKeyRepository, error) {
+	queries, err := loadQueries()
+	if err != nil {
+		return nil, err
+	}
+	return &APIKeyRepository{db: db, queries: queries}, nil
+}
+
+// GetByHash возвращает ключ по SHA-256 хэшу или nil, если ключа нет.
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.QueryRow(ctx, r.queries["GetAPIKeyByHash"], keyHash).Scan(
+		&key.ID,
+		&key.Name,
+		&key.KeyHash,
+		&key.Roles,
+		&key.TenantID,
+		&key.CreatedAt,
+		&key.ExpiresAt,
+		&key.RevokedAt,
+		&key.LastUsedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить ключ API: %w", err)
+	}
+	return &key, nil
+}
+
+// Create сохраняет ключ по его хэшу и заполняет ID и время создания.
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	err := r.db.QueryRow(ctx, r.queries["CreateAPIKey"], key.Name, key.KeyHash, key.Roles, key.TenantID, key.ExpiresAt).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить ключ API: %w", err)
+	}
+	return nil
+}
+
+// Touch отмечает использование ключа в момент at, если предыдущая отметка старше notAfter.
+func (r *APIKeyRepository) Touch(ctx context.Context, id int, at, notAfter time.Time) error {
+	if _, err := r.db.Exec(ctx, r.queries["TouchAPIKey"], id, at, notAfter); err != nil {
+		return fmt.Errorf("не удалось отметить использование ключа API: %w", err)
+	}
+	return nil
+}
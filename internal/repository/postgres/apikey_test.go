@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"person-service/internal/models"
+	"testing"
+	"time"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	pool := testPool(t)
+	repo, err := NewAPIKeyRepository(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	hash := fmt.Sprintf("%064x", time.Now().UnixNano())
+	key := &models.APIKey{Name: "test", KeyHash: hash, Roles: []string{"editor"}}
+	if err := repo.Create(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if key.ID == 0 {
+		t.Fatal("ID ключа не заполнен")
+	}
+
+	stored, err := repo.GetByHash(ctx, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored == nil || stored.ID != key.ID || stored.Name != "test" || len(stored.Roles) != 1 || stored.Roles[0] != "editor" {
+		t.Fatalf("сохранённый ключ не совпадает: %+v", stored)
+	}
+	if stored.TenantID != nil || !stored.Active(time.Now().UTC()) {
+		t.Fatalf("ключ без арендатора и срока должен быть активен: %+v", stored)
+	}
+}
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"person-service/internal/models"
+	"person-service/internal/repository"
+	"person-service/internal/tenant"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Count возвращает число записей, подходящих под фильтры списка.
+func (r *PersonRepository) Count(ctx context.Context, filters map[string]string) (int, error) {
+	where, args, err := buildWhere(tenant.IDFromContext(ctx), filters, nil)
+	if err != nil {
+		return 0, err
+	}
+	query := strings.Replace(r.queries["CountPersons"], "{{if .Where}}WHERE {{.Where}}{{end}}", where, 1)
+	var count int
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("не удалось подсчитать записи: %w", err)
+	}
+	return count, nil
+}
+
+// BulkPatch применяет update ко всем записям, подходящим под фильтры, в одной транзакции.
+// Операция отменяется, если записей больше maxRows или их число отличается от expected.
+// Возвращает число изменённых записей.
+func (r *PersonRepository) BulkPatch(ctx context.Context, filters map[string]string, update *models.PersonUpdate, expected, maxRows int) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	persons, err := r.lockMatching(ctx, tx, filters, expected, maxRows)
+	if err != nil {
+		return 0, err
+	}
+
+	affected := 0
+	now := time.Now()
+	for _, person := range persons {
+		original := *person
+		update.Apply(person)
+		if person.SameData(&original) {
+			continue
+		}
+		person.UpdatedAt = &now
+		err := tx.QueryRow(ctx, r.queries["UpdatePerson"],
+			person.Name,
+			person.Surname,
+			person.Patronymic,
+			person.Age,
+			person.Gender,
+			person.Nationality,
+			now,
+			person.ID,
+			tenant.IDFromContext(ctx),
+		).Scan(&person.CreatedAt, &person.Version)
+		if err != nil {
+			return 0, fmt.Errorf("не удалось обновить запись %d: %w", person.ID, err)
+		}
+		if err := r.recordChange(ctx, tx, models.ActionPatch, &original, person, now); err != nil {
+			return 0, err
+		}
+		affected++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("не удалось зафиксировать транзакцию: %w", err)
+	}
+	return affected, nil
+}
+
+// BulkDelete помечает удалёнными все записи, подходящие под фильтры, в одной транзакции.
+// Операция отменяется, если записей больше maxRows или их число отличается от expected.
+// Возвращает число удалённых записей.
+func (r *PersonRepository) BulkDelete(ctx context.Context, filters map[string]string, expected, maxRows int) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	persons, err := r.lockMatching(ctx, tx, filters, expected, maxRows)
+	if err != nil {
+		return 0, err
+	}
+	ids := make([]int, len(persons))
+	for i, person := range persons {
+		ids[i] = person.ID
+	}
+
+	now := time.Now()
+	rows, err := tx.Query(ctx, r.queries["DeletePersons"], ids, now, tenant.IDFromContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("не удалось удалить записи: %w", err)
+	}
+	versions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Person, error) {
+		person := &models.Person{DeletedAt: &now}
+		return person, row.Scan(&person.ID, &person.Version)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("не удалось удалить записи: %w", err)
+	}
+	for _, after := range versions {
+		before := &models.Person{ID: after.ID}
+		if err := r.recordChange(ctx, tx, models.ActionDelete, before, after, now); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("не удалось зафиксировать транзакцию: %w", err)
+	}
+	return len(versions), nil
+}
+
+// lockMatching блокирует записи, подходящие под фильтры, и проверяет ограничения массовой операции.
+func (r *PersonRepository) lockMatching(ctx context.Context, tx pgx.Tx, filters map[string]string, expected, maxRows int) ([]*models.Person, error) {
+	where, args, err := buildWhere(tenant.IDFromContext(ctx), filters, []interface{}{maxRows + 1})
+	if err != nil {
+		return nil, err
+	}
+	query := strings.Replace(r.queries["SelectPersonsForUpdate"], "{{if .Where}}WHERE {{.Where}}{{end}}", where, 1)
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось выбрать записи: %w", err)
+	}
+	defer rows.Close()
+
+	var persons []*models.Person
+	for rows.Next() {
+		person, err := scanPerson(rows)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось отсканировать запись: %w", err)
+		}
+		persons = append(persons, person)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось выбрать записи: %w", err)
+	}
+
+	if len(persons) > maxRows {
+		return nil, fmt.Errorf("%w: больше %d записей", repository.ErrBulkLimitExceeded, maxRows)
+	}
+	if len(persons) != expected {
+		return nil, fmt.Errorf("%w: найдено %d, ожидалось %d", repository.ErrBulkCountMismatch, len(persons), expected)
+	}
+	return persons, nil
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"person-service/internal/audit"
+	"person-service/internal/models"
+	"person-service/internal/repository"
+	"person-service/internal/tenant"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// FindSimilar возвращает до limit неудалённых записей, полное имя которых похоже на имя person
+// по триграммам, начиная с самых похожих. Запись с ID excludeID не возвращается.
+func (r *PersonRepository) FindSimilar(ctx context.Context, person *models.Person, excludeID, limit int) ([]*models.DuplicateCandidate, error) {
+	rows, err := r.db.Query(ctx, r.queries["FindSimilarPersons"],
+		person.Name,
+		person.Surname,
+		person.Patronymic,
+		excludeID,
+		limit,
+		tenant.IDFromContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось найти похожие записи: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []*models.DuplicateCandidate{}
+	for rows.Next() {
+		var candidate models.DuplicateCandidate
+		var p models.Person
+		if err := rows.Scan(
+			&p.ID,
+			&p.Name,
+			&p.Surname,
+			&p.Patronymic,
+			&p.Age,
+			&p.Gender,
+			&p.Nationality,
+			&p.CreatedAt,
+			&p.UpdatedAt,
+			&p.Version,
+			&p.DeletedAt,
+			&candidate.Similarity,
+		); err != nil {
+			return nil, fmt.Errorf("не удалось отсканировать запись: %w", err)
+		}
+		candidate.Person = &p
+		candidates = append(candidates, &candidate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось найти похожие записи: %w", err)
+	}
+	return candidates, nil
+}
+
+// Merge сливает записи sourceIDs в запись targetID в одной транзакции.
+// Функция merge изменяет целевую запись по данным источников; источники помечаются удалёнными.
+// В историю целевой записи записывается merged_from, в историю источников — merged_into.
+func (r *PersonRepository) Merge(ctx context.Context, targetID int, sourceIDs []int,
+	merge func(target *models.Person, sources []*models.Person) error) (*models.Person, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Записи блокируются в порядке id, чтобы параллельные слияния не взаимоблокировались
+	ids := append([]int{targetID}, sourceIDs...)
+	rows, err := tx.Query(ctx, r.queries["GetPersonsByIDsForUpdate"], ids, tenant.IDFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить записи: %w", err)
+	}
+	locked, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Person, error) {
+		return scanPerson(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить записи: %w", err)
+	}
+	byID := make(map[int]*models.Person, len(locked))
+	for _, person := range locked {
+		byID[person.ID] = person
+	}
+	target, ok := byID[targetID]
+	if !ok {
+		return nil, &repository.NotFoundError{ID: targetID}
+	}
+	sources := make([]*models.Person, len(sourceIDs))
+	for i, id := range sourceIDs {
+		if sources[i], ok = byID[id]; !ok {
+			return nil, &repository.NotFoundError{ID: id}
+		}
+	}
+
+	original := *target
+	if err := merge(target, sources); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	target.UpdatedAt = &now
+	err = tx.QueryRow(ctx, r.queries["UpdatePerson"],
+		target.Name,
+		target.Surname,
+		target.Patronymic,
+		target.Age,
+		target.Gender,
+		target.Nationality,
+		now,
+		targetID,
+		tenant.IDFromContext(ctx),
+	).Scan(&target.CreatedAt, &target.Version)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось обновить запись: %w", err)
+	}
+	if err := r.recordMerge(ctx, tx, &original, target, "merged_from", sourceIDs, now); err != nil {
+		return nil, err
+	}
+
+	for _, source := range sources {
+		before := *source
+		if err := tx.QueryRow(ctx, r.queries["DeletePerson"], source.ID, now, tenant.IDFromContext(ctx)).Scan(&source.Version); err != nil {
+			return nil, fmt.Errorf("не удалось удалить запись %d: %w", source.ID, err)
+		}
+		source.DeletedAt = &now
+		if err := r.recordMerge(ctx, tx, &before, source, "merged_into", targetID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("не удалось зафиксировать транзакцию: %w", err)
+	}
+	return target, nil
+}
+
+// recordMerge записывает в историю изменение записи при слиянии вместе со связью key: link.
+func (r *PersonRepository) recordMerge(ctx context.Context, tx pgx.Tx, before, after *models.Person, key string, link any, changedAt time.Time) error {
+	oldValues, newValues, err := models.DiffPersons(before, after)
+	if err != nil {
+		return fmt.Errorf("не удалось вычислить изменения: %w", err)
+	}
+	linkJSON, err := json.Marshal(link)
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать историю: %w", err)
+	}
+	newValues[key] = linkJSON
+	return r.insertHistory(ctx, tx, after, models.ActionMerge, audit.FromContext(ctx), oldValues, newValues, changedAt)
+}
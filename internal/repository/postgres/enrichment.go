@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"person-service/internal/models"
+	"person-service/internal/tenant"
+	"time"
+)
+
+// ListStaleEnrichment возвращает до limit неудалённых записей с ID больше afterID, обогащение которых
+// проверялось раньше before, в порядке ID. Записи, которые ни разу не проверялись, тоже возвращаются.
+func (r *PersonRepository) ListStaleEnrichment(ctx context.Context, before time.Time, afterID, limit int) ([]*models.Person, error) {
+	rows, err := r.db.Query(ctx, r.queries["ListStaleEnrichment"], before, afterID, limit, tenant.IDFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить записи для обогащения: %w", err)
+	}
+	defer rows.Close()
+
+	var persons []*models.Person
+	for rows.Next() {
+		person, err := scanPerson(rows)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось отсканировать запись: %w", err)
+		}
+		persons = append(persons, person)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось получить записи для обогащения: %w", err)
+	}
+	return persons, nil
+}
+
+// MarkEnriched отмечает, что обогащение записи проверено в момент at, даже если данные не изменились.
+func (r *PersonRepository) MarkEnriched(ctx context.Context, id int, at time.Time) error {
+	if _, err := r.db.Exec(ctx, r.queries["MarkPersonEnriched"], id, at, tenant.IDFromContext(ctx)); err != nil {
+		return fmt.Errorf("не удалось отметить обогащение записи: %w", err)
+	}
+	return nil
+}
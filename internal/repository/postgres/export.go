@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"person-service/internal/models"
+	"person-service/internal/tenant"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Stream передаёт в fn все записи под фильтрами в порядке id.
+// Записи читаются серверным курсором порциями, поэтому память не зависит от размера выборки.
+func (r *PersonRepository) Stream(ctx context.Context, filters map[string]string, fn func(person *models.Person) error) error {
+	where, args, err := buildWhere(tenant.IDFromContext(ctx), filters, nil)
+	if err != nil {
+		return err
+	}
+	query := strings.Replace(r.queries["DeclareExportCursor"], "{{if .Where}}WHERE {{.Where}}{{end}}", where, 1)
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly, IsoLevel: pgx.RepeatableRead})
+	if err != nil {
+		return fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("не удалось открыть курсор: %w", err)
+	}
+	for {
+		fetched, err := r.fetchExportChunk(ctx, tx, fn)
+		if err != nil {
+			return err
+		}
+		if fetched == 0 {
+			break
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// fetchExportChunk читает очередную порцию курсора и возвращает число прочитанных записей.
+func (r *PersonRepository) fetchExportChunk(ctx context.Context, tx pgx.Tx, fn func(person *models.Person) error) (int, error) {
+	rows, err := tx.Query(ctx, r.queries["FetchExportCursor"])
+	if err != nil {
+		return 0, fmt.Errorf("не удалось прочитать курсор: %w", err)
+	}
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		person, err := scanPerson(rows)
+		if err != nil {
+			return 0, fmt.Errorf("не удалось отсканировать запись: %w", err)
+		}
+		if err := fn(person); err != nil {
+			return 0, err
+		}
+		fetched++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("не удалось прочитать курсор: %w", err)
+	}
+	return fetched, nil
+}
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"person-service/internal/audit"
+	"person-service/internal/models"
+	"person-service/internal/tenant"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ListHistory возвращает изменения записи, начиная с последних.
+func (r *PersonRepository) ListHistory(ctx context.Context, personID, limit, offset int) ([]*models.HistoryEntry, error) {
+	rows, err := r.db.Query(ctx, r.queries["ListHistory"], personID, limit, offset, tenant.IDFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить историю: %w", err)
+	}
+	return collectHistory(rows)
+}
+
+// ListHistorySince возвращает изменения записи, сделанные после since, начиная с последних.
+func (r *PersonRepository) ListHistorySince(ctx context.Context, personID int, since time.Time) ([]*models.HistoryEntry, error) {
+	rows, err := r.db.Query(ctx, r.queries["ListHistorySince"], personID, since, tenant.IDFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить историю: %w", err)
+	}
+	return collectHistory(rows)
+}
+
+// ListRecentHistory возвращает до perPerson последних изменений каждой из записей personIDs.
+func (r *PersonRepository) ListRecentHistory(ctx context.Context, personIDs []int, perPerson int) ([]*models.HistoryEntry, error) {
+	rows, err := r.db.Query(ctx, r.queries["ListRecentHistory"], personIDs, perPerson, tenant.IDFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить историю: %w", err)
+	}
+	return collectHistory(rows)
+}
+
+// ListLatestHistoryByAction возвращает последнее изменение вида action для каждой из записей personIDs.
+func (r *PersonRepository) ListLatestHistoryByAction(ctx context.Context, personIDs []int, action models.HistoryAction) ([]*models.HistoryEntry, error) {
+	rows, err := r.db.Query(ctx, r.queries["ListLatestHistoryByAction"], personIDs, string(action), tenant.IDFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить историю: %w", err)
+	}
+	return collectHistory(rows)
+}
+
+// GetHistoryEntryAsOf возвращает последнее изменение записи, сделанное не позже asOf.
+// Если таких изменений нет, возвращается nil.
+func (r *PersonRepository) GetHistoryEntryAsOf(ctx context.Context, personID int, asOf time.Time) (*models.HistoryEntry, error) {
+	rows, err := r.db.Query(ctx, r.queries["GetHistoryEntryAsOf"], personID, asOf, tenant.IDFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить историю: %w", err)
+	}
+	entries, err := collectHistory(rows)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return entries[0], nil
+}
+
+// recordCreate записывает в историю создание записи.
+// Поля, заполненные обогащением, записываются отдельным изменением с источником enrichment.
+func (r *PersonRepository) recordCreate(ctx context.Context, tx pgx.Tx, person *models.Person) error {
+	_, after, err := models.DiffPersons(nil, person)
+	if err != nil {
+		return fmt.Errorf("не удалось вычислить изменения: %w", err)
+	}
+	enriched := map[string]json.RawMessage{}
+	for field, value := range after {
+		if slices.Contains(models.EnrichedFields, field) {
+			enriched[field] = value
+			delete(after, field)
+		}
+	}
+
+	meta := audit.FromContext(ctx)
+	if err := r.insertHistory(ctx, tx, person, models.ActionCreate, meta, nil, after, person.CreatedAt); err != nil {
+		return err
+	}
+	if len(enriched) == 0 {
+		return nil
+	}
+	before := make(map[string]json.RawMessage, len(enriched))
+	for field := range enriched {
+		before[field] = json.RawMessage("null")
+	}
+	meta.Source = models.SourceEnrichment
+	return r.insertHistory(ctx, tx, person, models.ActionEnrich, meta, before, enriched, person.CreatedAt)
+}
+
+// recordChange записывает в историю разницу между before и after.
+// Если before равен nil, запись считается созданной. Изменение без разницы не записывается.
+func (r *PersonRepository) recordChange(ctx context.Context, tx pgx.Tx, action models.HistoryAction, before, after *models.Person, changedAt time.Time) error {
+	oldValues, newValues, err := models.DiffPersons(before, after)
+	if err != nil {
+		return fmt.Errorf("не удалось вычислить изменения: %w", err)
+	}
+	if len(newValues) == 0 {
+		return nil
+	}
+	if before == nil {
+		oldValues = nil
+	}
+	meta := audit.FromContext(ctx)
+	if action == models.ActionEnrich {
+		meta.Source = models.SourceEnrichment
+	}
+	return r.insertHistory(ctx, tx, after, action, meta, oldValues, newValues, changedAt)
+}
+
+// insertHistory добавляет строку в person_history в рамках транзакции tx.
+func (r *PersonRepository) insertHistory(ctx context.Context, tx pgx.Tx, person *models.Person, action models.HistoryAction,
+	meta audit.Meta, before, after map[string]json.RawMessage, changedAt time.Time) error {
+	var beforeJSON, afterJSON []byte
+	var err error
+	if before != nil {
+		if beforeJSON, err = json.Marshal(before); err != nil {
+			return fmt.Errorf("не удалось сериализовать историю: %w", err)
+		}
+	}
+	if afterJSON, err = json.Marshal(after); err != nil {
+		return fmt.Errorf("не удалось сериализовать историю: %w", err)
+	}
+	if meta.Source == "" {
+		meta.Source = audit.SourceAPI
+	}
+
+	_, err = tx.Exec(ctx, r.queries["CreateHistoryEntry"],
+		person.ID,
+		action,
+		nullString(meta.Actor),
+		nullString(meta.RequestID),
+		meta.Source,
+		beforeJSON,
+		afterJSON,
+		person.Version,
+		changedAt,
+		tenant.IDFromContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("не удалось записать историю: %w", err)
+	}
+	return nil
+}
+
+// collectHistory считывает строки person_history.
+func collectHistory(rows pgx.Rows) ([]*models.HistoryEntry, error) {
+	defer rows.Close()
+	entries := []*models.HistoryEntry{}
+	for rows.Next() {
+		var entry models.HistoryEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.PersonID,
+			&entry.Action,
+			&entry.Actor,
+			&entry.RequestID,
+			&entry.Source,
+			&entry.Before,
+			&entry.After,
+			&entry.Version,
+			&entry.ChangedAt,
+		); err != nil {
+			return nil, fmt.Errorf("не удалось отсканировать запись истории: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось получить историю: %w", err)
+	}
+	return entries, nil
+}
+
+// nullString преобразует пустую строку в NULL.
+func nullString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
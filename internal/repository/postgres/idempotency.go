@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"person-service/internal/models"
+	"person-service/internal/tenant"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IdempotencyRepository хранит ключи идемпотентности в таблице idempotency_keys отдельно для каждого арендатора.
+type IdempotencyRepository struct {
+	db      *pgxpool.Pool
+	queries map[string]string
+}
+
+// NewIdempotencyRepository создаёт новый репозиторий ключей идемпотентности.
+func NewIdempotencyRepository(db *pgxpool.Pool) (*IdempotencyRepository, error) {
+	queries, err := loadQueries()
+	if err != nil {
+		return nil, err
+	}
+	return &IdempotencyRepository{db: db, queries: queries}, nil
+}
+
+// Reserve резервирует ключ за запросом с хэшем requestHash до lockedUntil.
+// Истёкший ключ и ключ, запрос которого не завершился до locked_until, резервируются заново.
+// Если ключ занят, возвращается существующая запись и reserved=false;
+// nil вместе с reserved=false означает, что занявший ключ запрос только что освободил его.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, key, requestHash string, now, lockedUntil, expiresAt time.Time) (*models.IdempotencyRecord, bool, error) {
+	var reservedKey string
+	err := r.db.QueryRow(ctx, r.queries["ReserveIdempotencyKey"], key, requestHash, now, lockedUntil, expiresAt, tenant.IDFromContext(ctx)).Scan(&reservedKey)
+	if err == nil {
+		return nil, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, fmt.Errorf("не удалось зарезервировать ключ идемпотентности: %w", err)
+	}
+
+	var record models.IdempotencyRecord
+	err = r.db.QueryRow(ctx, r.queries["GetIdempotencyKey"], key, tenant.IDFromContext(ctx)).Scan(
+		&record.Key,
+		&record.RequestHash,
+		&record.StatusCode,
+		&record.Headers,
+		&record.Body,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("не удалось получить ключ идемпотентности: %w", err)
+	}
+	return &record, false, nil
+}
+
+// Complete сохраняет ответ на запрос с ключом key, зарезервированным в момент reservedAt.
+// Если резервирование истекло и ключ занял другой запрос, возвращается ошибка.
+func (r *IdempotencyRepository) Complete(ctx context.Context, key string, reservedAt time.Time, statusCode int, headers map[string]string, body []byte) error {
+	tag, err := r.db.Exec(ctx, r.queries["CompleteIdempotencyKey"], key, statusCode, headers, body, reservedAt, tenant.IDFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить ответ для ключа идемпотентности: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("не удалось сохранить ответ для ключа идемпотентности %s: резервирование истекло", key)
+	}
+	return nil
+}
+
+// Extend переносит срок резервирования ключа, зарезервированного в момент reservedAt, на lockedUntil.
+func (r *IdempotencyRepository) Extend(ctx context.Context, key string, reservedAt, lockedUntil time.Time) error {
+	if _, err := r.db.Exec(ctx, r.queries["ExtendIdempotencyKey"], key, reservedAt, lockedUntil, tenant.IDFromContext(ctx)); err != nil {
+		return fmt.Errorf("не удалось продлить резервирование ключа идемпотентности: %w", err)
+	}
+	return nil
+}
+
+// Release освобождает ключ, зарезервированный в момент reservedAt, ответ для которого не был сохранён.
+// Ключ, занятый заново после истечения резервирования, не освобождается.
+func (r *IdempotencyRepository) Release(ctx context.Context, key string, reservedAt time.Time) error {
+	if _, err := r.db.Exec(ctx, r.queries["ReleaseIdempotencyKey"], key, reservedAt, tenant.IDFromContext(ctx)); err != nil {
+		return fmt.Errorf("не удалось освободить ключ идемпотентности: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired удаляет ключи всех арендаторов, истёкшие не позже before, и возвращает их число.
+func (r *IdempotencyRepository) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, r.queries["PurgeExpiredIdempotencyKeys"], before)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось удалить истёкшие ключи идемпотентности: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
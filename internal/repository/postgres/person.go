@@ -3,11 +3,16 @@ package postgres
 import (
 	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"person-service/internal/models"
+	"person-service/internal/repository"
+	"person-service/internal/tenant"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -22,7 +27,15 @@ type PersonRepository struct {
 
 // NewPersonRepository создаёт новый репозиторий для работы с persons.
 func NewPersonRepository(db *pgxpool.Pool) (*PersonRepository, error) {
-	// Загрузка SQL-запросов
+	queries, err := loadQueries()
+	if err != nil {
+		return nil, err
+	}
+	return &PersonRepository{db: db, queries: queries}, nil
+}
+
+// loadQueries загружает именованные SQL-запросы из queries.sql.
+func loadQueries() (map[string]string, error) {
 	content, err := queriesFS.ReadFile("queries.sql")
 	if err != nil {
 		return nil, fmt.Errorf("не удалось прочитать queries.sql: %w", err)
@@ -43,7 +56,8 @@ func NewPersonRepository(db *pgxpool.Pool) (*PersonRepository, error) {
 				queries[currentName] = strings.TrimSpace(currentQuery)
 			}
 			currentName = strings.TrimPrefix(line, "-- name: ")
-			currentQuery = ""
+			// Строка с именем остаётся комментарием запроса: по ней метрики различают запросы
+			currentQuery = line + "\n"
 		} else {
 			currentQuery += line + "\n"
 		}
@@ -52,13 +66,74 @@ func NewPersonRepository(db *pgxpool.Pool) (*PersonRepository, error) {
 		queries[currentName] = strings.TrimSpace(currentQuery)
 	}
 
-	return &PersonRepository{db: db, queries: queries}, nil
+	return queries, nil
 }
 
 // Create создаёт новую запись в таблице persons.
+// В историю записываются создание и, отдельно, поля, полученные через обогащение.
 func (r *PersonRepository) Create(ctx context.Context, person *models.Person) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.createInTx(ctx, tx, person); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("не удалось зафиксировать транзакцию: %w", err)
+	}
+	return nil
+}
+
+// CreateBatch создаёт записи в одной транзакции и возвращает ошибки по элементам.
+// При atomic=true первая ошибка отменяет весь пакет и возвращается вместе с repository.ErrBatchAborted.
+// Иначе каждый элемент создаётся в собственной точке сохранения, и ошибки элементов не влияют на остальные.
+func (r *PersonRepository) CreateBatch(ctx context.Context, persons []*models.Person, atomic bool) ([]error, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	itemErrs := make([]error, len(persons))
+	for i, person := range persons {
+		if atomic {
+			if err := r.createInTx(ctx, tx, person); err != nil {
+				itemErrs[i] = err
+				return itemErrs, repository.ErrBatchAborted
+			}
+			continue
+		}
+
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось создать точку сохранения: %w", err)
+		}
+		if err := r.createInTx(ctx, savepoint, person); err != nil {
+			itemErrs[i] = err
+			if err := savepoint.Rollback(ctx); err != nil {
+				return nil, fmt.Errorf("не удалось откатить точку сохранения: %w", err)
+			}
+			continue
+		}
+		if err := savepoint.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("не удалось освободить точку сохранения: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("не удалось зафиксировать транзакцию: %w", err)
+	}
+	return itemErrs, nil
+}
+
+// createInTx добавляет запись и её историю в рамках транзакции tx.
+func (r *PersonRepository) createInTx(ctx context.Context, tx pgx.Tx, person *models.Person) error {
 	query := r.queries["CreatePerson"]
-	err := r.db.QueryRow(ctx, query,
+	err := tx.QueryRow(ctx, query,
 		person.Name,
 		person.Surname,
 		person.Patronymic,
@@ -66,100 +141,287 @@ func (r *PersonRepository) Create(ctx context.Context, person *models.Person) er
 		person.Gender,
 		person.Nationality,
 		person.CreatedAt,
-	).Scan(&person.ID)
+		tenant.IDFromContext(ctx),
+	).Scan(&person.ID, &person.Version)
 	if err != nil {
 		return fmt.Errorf("не удалось создать запись: %w", err)
 	}
-	return nil
+	return r.recordCreate(ctx, tx, person)
 }
 
-// GetByID возвращает запись по ID.
-func (r *PersonRepository) GetByID(ctx context.Context, id int) (*models.Person, error) {
-	query := r.queries["GetPersonByID"]
-	var person models.Person
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&person.ID,
-		&person.Name,
-		&person.Surname,
-		&person.Patronymic,
-		&person.Age,
-		&person.Gender,
-		&person.Nationality,
-		&person.CreatedAt,
-		&person.UpdatedAt,
-	)
+// GetByID возвращает запись по ID. Удалённые записи возвращаются, только если includeDeleted=true.
+func (r *PersonRepository) GetByID(ctx context.Context, id int, includeDeleted bool, fields []string) (*models.Person, error) {
+	columns, err := selectColumns(fields)
 	if err != nil {
-		return nil, fmt.Errorf("запись с id %d не найдена", id)
+		return nil, err
 	}
-	return &person, nil
+	query := strings.Replace(r.queries["GetPersonByID"], "{{.Columns}}", columns, 1)
+	person, err := scanPersonFields(r.db.QueryRow(ctx, query, id, includeDeleted, tenant.IDFromContext(ctx)), fields)
+	if err != nil {
+		return nil, &repository.NotFoundError{ID: id}
+	}
+	return person, nil
 }
 
-// Update обновляет запись в таблице persons.
-func (r *PersonRepository) Update(ctx context.Context, person *models.Person) error {
-	query := r.queries["UpdatePerson"]
-	result, err := r.db.Exec(ctx, query,
+// GetByIDs возвращает записи с указанными ID в произвольном порядке; отсутствующие ID пропускаются.
+// Удалённые записи возвращаются только при includeDeleted.
+func (r *PersonRepository) GetByIDs(ctx context.Context, ids []int, includeDeleted bool) ([]*models.Person, error) {
+	rows, err := r.db.Query(ctx, r.queries["GetPersonsByIDs"], ids, includeDeleted, tenant.IDFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить записи: %w", err)
+	}
+	defer rows.Close()
+
+	var persons []*models.Person
+	for rows.Next() {
+		person, err := scanPerson(rows)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось отсканировать запись: %w", err)
+		}
+		persons = append(persons, person)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось получить записи: %w", err)
+	}
+	return persons, nil
+}
+
+// ApplyPatch атомарно применяет изменения к записи внутри транзакции и записывает их в историю как action.
+// Запись блокируется на время применения apply, поэтому параллельные PATCH не теряют изменения.
+// Если apply не изменил данные, запись не сохраняется и её версия не увеличивается.
+func (r *PersonRepository) ApplyPatch(ctx context.Context, id int, action models.HistoryAction, apply func(person *models.Person) error) (*models.Person, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	person, err := scanPerson(tx.QueryRow(ctx, r.queries["GetPersonByIDForUpdate"], id, tenant.IDFromContext(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &repository.NotFoundError{ID: id}
+		}
+		return nil, fmt.Errorf("не удалось получить запись: %w", err)
+	}
+
+	original := *person
+	if err := apply(person); err != nil {
+		return nil, err
+	}
+	if person.SameData(&original) {
+		return &original, nil
+	}
+
+	now := time.Now()
+	person.ID = id
+	person.UpdatedAt = &now
+	err = tx.QueryRow(ctx, r.queries["UpdatePerson"],
 		person.Name,
 		person.Surname,
 		person.Patronymic,
 		person.Age,
 		person.Gender,
 		person.Nationality,
-		time.Now(),
-		person.ID,
-	)
+		now,
+		id,
+		tenant.IDFromContext(ctx),
+	).Scan(&person.CreatedAt, &person.Version)
 	if err != nil {
-		return fmt.Errorf("не удалось обновить запись: %w", err)
+		return nil, fmt.Errorf("не удалось обновить запись: %w", err)
 	}
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("запись с id %d не найдена", person.ID)
+	if err := r.recordChange(ctx, tx, action, &original, person, now); err != nil {
+		return nil, err
 	}
-	return nil
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("не удалось зафиксировать транзакцию: %w", err)
+	}
+	return person, nil
+}
+
+// Replace полностью заменяет запись в таблице persons.
+// Если записи нет и createIfAbsent=true, она создаётся с ID из person; created сообщает о создании.
+// Последовательность ID сдвигается за созданный ID в той же транзакции. На это время вставки в persons
+// блокируются, чтобы последовательность не выдала тот же ID параллельному созданию. Для ID, занятого
+// другим арендатором, возвращается ErrIDUnavailable.
+func (r *PersonRepository) Replace(ctx context.Context, person *models.Person, createIfAbsent bool) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	existing, err := scanPerson(tx.QueryRow(ctx, r.queries["GetPersonByIDForUpdate"], person.ID, tenant.IDFromContext(ctx)))
+	switch {
+	case err == nil:
+		now := time.Now()
+		err = tx.QueryRow(ctx, r.queries["UpdatePerson"],
+			person.Name,
+			person.Surname,
+			person.Patronymic,
+			person.Age,
+			person.Gender,
+			person.Nationality,
+			now,
+			person.ID,
+			tenant.IDFromContext(ctx),
+		).Scan(&person.CreatedAt, &person.Version)
+		if err != nil {
+			return false, fmt.Errorf("не удалось обновить запись: %w", err)
+		}
+		person.UpdatedAt = &now
+		if err := r.recordChange(ctx, tx, models.ActionUpdate, existing, person, now); err != nil {
+			return false, err
+		}
+	case errors.Is(err, pgx.ErrNoRows) && createIfAbsent:
+		if _, err := tx.Exec(ctx, r.queries["LockPersonsForIDAssignment"]); err != nil {
+			return false, fmt.Errorf("не удалось заблокировать выдачу id: %w", err)
+		}
+		person.CreatedAt = time.Now()
+		person.UpdatedAt = nil
+		err = tx.QueryRow(ctx, r.queries["CreatePersonWithID"],
+			person.ID,
+			person.Name,
+			person.Surname,
+			person.Patronymic,
+			person.Age,
+			person.Gender,
+			person.Nationality,
+			person.CreatedAt,
+			tenant.IDFromContext(ctx),
+		).Scan(&person.Version)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, r.unavailableID(ctx, tx, person.ID)
+		}
+		if err != nil {
+			return false, fmt.Errorf("не удалось создать запись: %w", err)
+		}
+		if _, err := tx.Exec(ctx, r.queries["AdvancePersonsIDSequence"], person.ID); err != nil {
+			return false, fmt.Errorf("не удалось сдвинуть последовательность id: %w", err)
+		}
+		if err := r.recordChange(ctx, tx, models.ActionCreate, nil, person, person.CreatedAt); err != nil {
+			return false, err
+		}
+	case errors.Is(err, pgx.ErrNoRows):
+		return false, &repository.NotFoundError{ID: person.ID}
+	default:
+		return false, fmt.Errorf("не удалось получить запись: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("не удалось зафиксировать транзакцию: %w", err)
+	}
+	return existing == nil, nil
 }
 
-// Delete удаляет запись по ID.
+// unavailableID объясняет, почему запись нельзя создать с занятым ID: запись арендатора, помеченную удалённой,
+// нужно сначала восстановить, а о записи другого арендатора сообщается только ErrIDUnavailable.
+func (r *PersonRepository) unavailableID(ctx context.Context, tx pgx.Tx, id int) error {
+	var own bool
+	if err := tx.QueryRow(ctx, r.queries["PersonIDExists"], id, tenant.IDFromContext(ctx)).Scan(&own); err != nil {
+		return fmt.Errorf("не удалось проверить запись: %w", err)
+	}
+	if own {
+		return &repository.NotFoundError{ID: id}
+	}
+	return repository.ErrIDUnavailable
+}
+
+// Delete помечает запись удалённой. Физически запись удаляется позже через PurgeDeleted.
 func (r *PersonRepository) Delete(ctx context.Context, id int) error {
-	query := r.queries["DeletePerson"]
-	result, err := r.db.Exec(ctx, query, id)
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	var version int
+	err = tx.QueryRow(ctx, r.queries["DeletePerson"], id, now, tenant.IDFromContext(ctx)).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &repository.NotFoundError{ID: id}
+		}
 		return fmt.Errorf("не удалось удалить запись: %w", err)
 	}
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("запись с id %d не найдена", id)
+	before := &models.Person{ID: id}
+	after := &models.Person{ID: id, DeletedAt: &now, Version: version}
+	if err := r.recordChange(ctx, tx, models.ActionDelete, before, after, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("не удалось зафиксировать транзакцию: %w", err)
 	}
 	return nil
 }
 
-// List возвращает список записей с пагинацией и фильтрами.
-func (r *PersonRepository) List(ctx context.Context, limit, offset int, filters map[string]string) ([]*models.Person, error) {
-	queryTemplate := r.queries["ListPersons"]
-	args := []interface{}{limit, offset}
-	whereClauses := []string{}
+// Restore снимает пометку удаления с записи и возвращает её.
+func (r *PersonRepository) Restore(ctx context.Context, id int) (*models.Person, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-	if name, ok := filters["name"]; ok {
-		whereClauses = append(whereClauses, fmt.Sprintf("name ILIKE '%s'", "%"+name+"%"))
+	var deletedAt time.Time
+	err = tx.QueryRow(ctx, r.queries["GetPersonDeletedAtForUpdate"], id, tenant.IDFromContext(ctx)).Scan(&deletedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &repository.NotFoundError{ID: id}
+		}
+		return nil, fmt.Errorf("не удалось получить запись: %w", err)
 	}
-	if surname, ok := filters["surname"]; ok {
-		whereClauses = append(whereClauses, fmt.Sprintf("surname ILIKE '%s'", "%"+surname+"%"))
+
+	now := time.Now()
+	person, err := scanPerson(tx.QueryRow(ctx, r.queries["RestorePerson"], id, now, tenant.IDFromContext(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось восстановить запись: %w", err)
 	}
-	if patronymic, ok := filters["patronymic"]; ok {
-		whereClauses = append(whereClauses, fmt.Sprintf("patronymic ILIKE '%s'", "%"+patronymic+"%"))
+	before := &models.Person{ID: id, DeletedAt: &deletedAt}
+	after := &models.Person{ID: id, Version: person.Version}
+	if err := r.recordChange(ctx, tx, models.ActionRestore, before, after, now); err != nil {
+		return nil, err
 	}
-	if age, ok := filters["age"]; ok {
-		whereClauses = append(whereClauses, fmt.Sprintf("age = %s", age))
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("не удалось зафиксировать транзакцию: %w", err)
 	}
-	if gender, ok := filters["gender"]; ok {
-		whereClauses = append(whereClauses, fmt.Sprintf("gender = '%s'", gender))
+	return person, nil
+}
+
+// PurgeDeleted физически удаляет записи всех арендаторов, помеченные удалёнными раньше before.
+// История изменений очищенных записей сохраняется.
+func (r *PersonRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	query := r.queries["PurgeDeletedPersons"]
+	result, err := r.db.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось очистить удалённые записи: %w", err)
 	}
-	if nationality, ok := filters["nationality"]; ok {
-		whereClauses = append(whereClauses, fmt.Sprintf("nationality ILIKE '%s'", "%"+nationality+"%"))
+	return result.RowsAffected(), nil
+}
+
+// List возвращает список записей с пагинацией и фильтрами.
+// Удалённые записи исключаются, если в filters не указано include_deleted=true.
+// Порядок задаётся ключом sort, например "surname,-age"; по умолчанию записи упорядочены по id.
+func (r *PersonRepository) List(ctx context.Context, limit, offset int, filters map[string]string, fields []string) ([]*models.Person, error) {
+	columns, err := selectColumns(fields)
+	if err != nil {
+		return nil, err
+	}
+	queryTemplate := strings.Replace(r.queries["ListPersons"], "{{.Columns}}", columns, 1)
+	where, args, err := buildWhere(tenant.IDFromContext(ctx), filters, []interface{}{limit, offset})
+	if err != nil {
+		return nil, err
 	}
 
-	var where string
-	if len(whereClauses) > 0 {
-		where = strings.Join(whereClauses, " AND ")
+	orderBy, err := buildOrderBy(filters["sort"])
+	if err != nil {
+		return nil, err
 	}
 
 	query := strings.Replace(queryTemplate, "{{if .Where}}WHERE {{.Where}}{{end}}", where, 1)
+	query = strings.Replace(query, "{{.OrderBy}}", orderBy, 1)
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("не удалось получить список записей: %w", err)
@@ -168,88 +430,175 @@ func (r *PersonRepository) List(ctx context.Context, limit, offset int, filters
 
 	var persons []*models.Person
 	for rows.Next() {
-		var person models.Person
-		if err := rows.Scan(
-			&person.ID,
-			&person.Name,
-			&person.Surname,
-			&person.Patronymic,
-			&person.Age,
-			&person.Gender,
-			&person.Nationality,
-			&person.CreatedAt,
-			&person.UpdatedAt,
-		); err != nil {
+		person, err := scanPersonFields(rows, fields)
+		if err != nil {
 			return nil, fmt.Errorf("не удалось отсканировать запись: %w", err)
 		}
-		persons = append(persons, &person)
+		persons = append(persons, person)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось получить список записей: %w", err)
 	}
 
 	return persons, nil
 }
 
-func (r *PersonRepository) Patch(ctx context.Context, id int, update *models.PersonUpdate) error {
-	var fields []string
-	var args []interface{}
-	argIndex := 1
+// buildWhere формирует условие WHERE по арендатору tenantID и фильтрам списка.
+// Значения фильтров передаются параметрами, нумерация продолжает уже переданные args.
+func buildWhere(tenantID string, filters map[string]string, args []interface{}) (string, []interface{}, error) {
+	var whereClauses []string
+	addClause := func(format string, value interface{}) {
+		args = append(args, value)
+		whereClauses = append(whereClauses, fmt.Sprintf(format, len(args)))
+	}
 
-	// Формируем список полей для обновления
-	if update.Name != nil {
-		fields = append(fields, fmt.Sprintf("name = $%d", argIndex))
-		args = append(args, *update.Name)
-		argIndex++
+	addClause("tenant_id = $%d", tenantID)
+	if name, ok := filters["name"]; ok {
+		addClause("name ILIKE $%d", "%"+name+"%")
 	}
-	if update.Surname != nil {
-		fields = append(fields, fmt.Sprintf("surname = $%d", argIndex))
-		args = append(args, *update.Surname)
-		argIndex++
+	if surname, ok := filters["surname"]; ok {
+		addClause("surname ILIKE $%d", "%"+surname+"%")
+	}
+	if patronymic, ok := filters["patronymic"]; ok {
+		addClause("patronymic ILIKE $%d", "%"+patronymic+"%")
 	}
-	if update.Patronymic != nil {
-		fields = append(fields, fmt.Sprintf("patronymic = $%d", argIndex))
-		args = append(args, *update.Patronymic)
-		argIndex++
+	if ageStr, ok := filters["age"]; ok {
+		age, err := strconv.Atoi(ageStr)
+		if err != nil {
+			return "", nil, fmt.Errorf("некорректный фильтр age: %s", ageStr)
+		}
+		addClause("age = $%d", age)
 	}
-	if update.Age != nil {
-		fields = append(fields, fmt.Sprintf("age = $%d", argIndex))
-		args = append(args, *update.Age)
-		argIndex++
+	if gender, ok := filters["gender"]; ok {
+		addClause("gender::text = $%d", gender)
 	}
-	if update.Gender != nil {
-		fields = append(fields, fmt.Sprintf("gender = $%d", argIndex))
-		args = append(args, *update.Gender)
-		argIndex++
+	if nationality, ok := filters["nationality"]; ok {
+		addClause("nationality ILIKE $%d", "%"+nationality+"%")
 	}
-	if update.Nationality != nil {
-		fields = append(fields, fmt.Sprintf("nationality = $%d", argIndex))
-		args = append(args, *update.Nationality)
-		argIndex++
+	if filters["include_deleted"] != "true" {
+		whereClauses = append(whereClauses, "deleted_at IS NULL")
 	}
 
-	// Добавляем updated_at
-	fields = append(fields, fmt.Sprintf("updated_at = $%d", argIndex))
-	args = append(args, time.Now())
-	argIndex++
+	return "WHERE " + strings.Join(whereClauses, " AND "), args, nil
+}
 
-	// Добавляем ID
-	args = append(args, id)
+// sortableFields перечисляет поля, по которым можно упорядочить список.
+var sortableFields = map[string]bool{
+	"id":          true,
+	"name":        true,
+	"surname":     true,
+	"patronymic":  true,
+	"age":         true,
+	"gender":      true,
+	"nationality": true,
+	"created_at":  true,
+	"updated_at":  true,
+}
 
-	// Формируем SQL-запрос
-	query := fmt.Sprintf(`
-        UPDATE persons
-        SET %s
-        WHERE id = $%d
-        RETURNING id
-    `, strings.Join(fields, ", "), argIndex)
+// buildOrderBy формирует выражение ORDER BY из списка полей через запятую; "-" перед полем означает убывание.
+// В конец всегда добавляется id, чтобы порядок страниц был устойчивым.
+func buildOrderBy(sort string) (string, error) {
+	var terms []string
+	seenID := false
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		direction := "ASC"
+		if strings.HasPrefix(field, "-") {
+			field, direction = field[1:], "DESC"
+		}
+		if !sortableFields[field] {
+			return "", fmt.Errorf("некорректная сортировка: %s", field)
+		}
+		seenID = seenID || field == "id"
+		terms = append(terms, fmt.Sprintf("%s %s NULLS LAST", field, direction))
+	}
+	if !seenID {
+		terms = append(terms, "id ASC")
+	}
+	return strings.Join(terms, ", "), nil
+}
 
-	// Выполняем запрос
-	var updatedID int
-	err := r.db.QueryRow(ctx, query, args...).Scan(&updatedID)
+// scanPerson считывает запись из строки результата в порядке столбцов таблицы persons.
+func scanPerson(row pgx.Row) (*models.Person, error) {
+	var person models.Person
+	err := row.Scan(
+		&person.ID,
+		&person.Name,
+		&person.Surname,
+		&person.Patronymic,
+		&person.Age,
+		&person.Gender,
+		&person.Nationality,
+		&person.CreatedAt,
+		&person.UpdatedAt,
+		&person.Version,
+		&person.DeletedAt,
+	)
 	if err != nil {
-		if strings.Contains(err.Error(), "no rows in result set") {
-			return fmt.Errorf("запись с id %d не найдена", id)
+		return nil, err
+	}
+	return &person, nil
+}
+
+// selectColumns возвращает список колонок для выборки полей fields; nil означает все поля.
+func selectColumns(fields []string) (string, error) {
+	if fields == nil {
+		fields = models.PersonFields
+	}
+	var person models.Person
+	for _, field := range fields {
+		if personFieldDest(&person, field) == nil {
+			return "", fmt.Errorf("неизвестное поле: %s", field)
 		}
-		return fmt.Errorf("не удалось обновить запись: %w", err)
 	}
+	return strings.Join(fields, ", "), nil
+}
 
-	return nil
+// scanPersonFields сканирует строку, содержащую только колонки fields; nil означает все поля.
+func scanPersonFields(row pgx.Row, fields []string) (*models.Person, error) {
+	if fields == nil {
+		return scanPerson(row)
+	}
+	var person models.Person
+	dest := make([]any, len(fields))
+	for i, field := range fields {
+		dest[i] = personFieldDest(&person, field)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// personFieldDest возвращает указатель на поле записи, соответствующее колонке, или nil.
+func personFieldDest(person *models.Person, field string) any {
+	switch field {
+	case "id":
+		return &person.ID
+	case "name":
+		return &person.Name
+	case "surname":
+		return &person.Surname
+	case "patronymic":
+		return &person.Patronymic
+	case "age":
+		return &person.Age
+	case "gender":
+		return &person.Gender
+	case "nationality":
+		return &person.Nationality
+	case "created_at":
+		return &person.CreatedAt
+	case "updated_at":
+		return &person.UpdatedAt
+	case "version":
+		return &person.Version
+	case "deleted_at":
+		return &person.DeletedAt
+	default:
+		return nil
+	}
 }
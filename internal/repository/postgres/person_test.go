@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestReplaceCreatesWithClientID(t *testing.T) {
+	pool := testPool(t)
+	repo, err := NewPersonRepository(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, _ := tenantContexts()
+	name := fmt.Sprintf("Клиентский%d", time.Now().UnixNano())
+
+	var lastID int
+	if err := pool.QueryRow(context.Background(), "SELECT COALESCE(MAX(id), 0) FROM persons").Scan(&lastID); err != nil {
+		t.Fatal(err)
+	}
+	// ID выше всех выданных последовательностью
+	id := lastID + 1000
+	person := createPerson(t, ctx, repo, name)
+	person.ID = id
+	created, err := repo.Replace(ctx, person, true)
+	if err != nil {
+		t.Fatalf("запись с ID %d не создана: %v", id, err)
+	}
+	if !created {
+		t.Fatal("Replace сообщил об обновлении вместо создания")
+	}
+
+	next := createPerson(t, ctx, repo, name)
+	if next.ID <= id {
+		t.Fatalf("последовательность выдала ID %d, не больше созданного клиентом %d", next.ID, id)
+	}
+}
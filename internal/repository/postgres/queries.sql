@@ -1,25 +1,254 @@
 -- name: CreatePerson
-INSERT INTO persons (name, surname, patronymic, age, gender, nationality, created_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id;
+INSERT INTO persons (name, surname, patronymic, age, gender, nationality, created_at, tenant_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, version;
+
+-- name: CreatePersonWithID
+INSERT INTO persons (id, name, surname, patronymic, age, gender, nationality, created_at, tenant_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (id) DO NOTHING
+RETURNING version;
+
+-- name: LockPersonsForIDAssignment
+-- Блокирует вставки в persons, а значит и выдачу ID последовательностью, до конца транзакции
+LOCK TABLE persons IN SHARE ROW EXCLUSIVE MODE;
+
+-- name: AdvancePersonsIDSequence
+-- Сдвигает последовательность за ID, созданный клиентом, чтобы она не выдала его повторно; меньший ID её не сдвигает
+SELECT setval(pg_get_serial_sequence('persons', 'id'),
+              GREATEST($1::bigint, COALESCE(pg_sequence_last_value(pg_get_serial_sequence('persons', 'id')::regclass), 0)));
+
+-- name: PersonIDExists
+-- Запись ищется и среди удалённых
+SELECT EXISTS (SELECT 1 FROM persons WHERE id = $1 AND tenant_id = $2);
 
 -- name: GetPersonByID
-SELECT id, name, surname, patronymic, age, gender, nationality, created_at, updated_at
+SELECT {{.Columns}}
 FROM persons
-WHERE id = $1;
+WHERE id = $1 AND (deleted_at IS NULL OR $2) AND tenant_id = $3;
+
+-- name: GetPersonsByIDs
+SELECT id, name, surname, patronymic, age, gender, nationality, created_at, updated_at, version, deleted_at
+FROM persons
+WHERE id = ANY($1) AND (deleted_at IS NULL OR $2) AND tenant_id = $3;
 
 -- name: UpdatePerson
 UPDATE persons
-SET name = $1, surname = $2, patronymic = $3, age = $4, gender = $5, nationality = $6, updated_at = $7
-WHERE id = $8;
+SET name = $1, surname = $2, patronymic = $3, age = $4, gender = $5, nationality = $6, updated_at = $7, version = version + 1
+WHERE id = $8 AND deleted_at IS NULL AND tenant_id = $9
+RETURNING created_at, version;
 
 -- name: DeletePerson
+UPDATE persons
+SET deleted_at = $2, version = version + 1
+WHERE id = $1 AND deleted_at IS NULL AND tenant_id = $3
+RETURNING version;
+
+-- name: GetPersonDeletedAtForUpdate
+SELECT deleted_at
+FROM persons
+WHERE id = $1 AND deleted_at IS NOT NULL AND tenant_id = $2
+FOR UPDATE;
+
+-- name: RestorePerson
+UPDATE persons
+SET deleted_at = NULL, updated_at = $2, version = version + 1
+WHERE id = $1 AND deleted_at IS NOT NULL AND tenant_id = $3
+RETURNING id, name, surname, patronymic, age, gender, nationality, created_at, updated_at, version, deleted_at;
+
+-- name: PurgeDeletedPersons
+-- Очистка выполняется для всех арендаторов
 DELETE FROM persons
-WHERE id = $1;
+WHERE deleted_at IS NOT NULL AND deleted_at < $1;
 
 -- name: ListPersons
-SELECT id, name, surname, patronymic, age, gender, nationality, created_at, updated_at
+SELECT {{.Columns}}
+FROM persons
+{{if .Where}}WHERE {{.Where}}{{end}}
+ORDER BY {{.OrderBy}}
+LIMIT $1 OFFSET $2;
+
+-- name: GetPersonByIDForUpdate
+SELECT id, name, surname, patronymic, age, gender, nationality, created_at, updated_at, version, deleted_at
+FROM persons
+WHERE id = $1 AND deleted_at IS NULL AND tenant_id = $2
+FOR UPDATE;
+
+-- name: CreateHistoryEntry
+INSERT INTO person_history (person_id, action, actor, request_id, source, before, after, version, changed_at, tenant_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+-- name: ListHistory
+SELECT id, person_id, action, actor, request_id, source, before, after, version, changed_at
+FROM person_history
+WHERE person_id = $1 AND tenant_id = $4
+ORDER BY changed_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ListRecentHistory
+SELECT id, person_id, action, actor, request_id, source, before, after, version, changed_at
+FROM (
+    SELECT *, row_number() OVER (PARTITION BY person_id ORDER BY changed_at DESC, id DESC) AS position
+    FROM person_history
+    WHERE person_id = ANY($1) AND tenant_id = $3
+) recent
+WHERE position <= $2
+ORDER BY person_id, changed_at DESC, id DESC;
+
+-- name: ListLatestHistoryByAction
+SELECT DISTINCT ON (person_id) id, person_id, action, actor, request_id, source, before, after, version, changed_at
+FROM person_history
+WHERE person_id = ANY($1) AND action = $2 AND tenant_id = $3
+ORDER BY person_id, changed_at DESC, id DESC;
+
+-- name: ListStaleEnrichment
+-- Записи, не проверенные обогащением позже $1, по возрастанию id после $2
+SELECT p.id, p.name, p.surname, p.patronymic, p.age, p.gender, p.nationality, p.created_at, p.updated_at, p.version, p.deleted_at
+FROM persons p
+WHERE p.deleted_at IS NULL AND p.id > $2 AND p.tenant_id = $4
+  AND (p.enriched_at IS NULL OR p.enriched_at < $1)
+ORDER BY p.id
+LIMIT $3;
+
+-- name: MarkPersonEnriched
+-- Версия и updated_at не меняются: проверка обогащения не изменяет данные записи
+UPDATE persons
+SET enriched_at = $2
+WHERE id = $1 AND tenant_id = $3;
+
+-- name: ListHistorySince
+SELECT id, person_id, action, actor, request_id, source, before, after, version, changed_at
+FROM person_history
+WHERE person_id = $1 AND changed_at > $2 AND tenant_id = $3
+ORDER BY changed_at DESC, id DESC;
+
+-- name: GetHistoryEntryAsOf
+SELECT id, person_id, action, actor, request_id, source, before, after, version, changed_at
+FROM person_history
+WHERE person_id = $1 AND changed_at <= $2 AND tenant_id = $3
+ORDER BY changed_at DESC, id DESC
+LIMIT 1;
+
+-- name: CountPersons
+SELECT COUNT(*)
+FROM persons
+{{if .Where}}WHERE {{.Where}}{{end}};
+
+-- name: SelectPersonsForUpdate
+SELECT id, name, surname, patronymic, age, gender, nationality, created_at, updated_at, version, deleted_at
 FROM persons
 {{if .Where}}WHERE {{.Where}}{{end}}
 ORDER BY id
-LIMIT $1 OFFSET $2;
\ No newline at end of file
+LIMIT $1
+FOR UPDATE;
+
+-- name: DeletePersons
+UPDATE persons
+SET deleted_at = $2, version = version + 1
+WHERE id = ANY($1) AND deleted_at IS NULL AND tenant_id = $3
+RETURNING id, version;
+
+-- name: DeclareExportCursor
+DECLARE persons_export NO SCROLL CURSOR FOR
+SELECT id, name, surname, patronymic, age, gender, nationality, created_at, updated_at, version, deleted_at
+FROM persons
+{{if .Where}}WHERE {{.Where}}{{end}}
+ORDER BY id;
+
+-- name: FetchExportCursor
+FETCH FORWARD 1000 FROM persons_export;
+
+-- name: ReserveIdempotencyKey
+-- Ключ занимается заново, если он истёк или запрос, занявший его, не завершился до locked_until
+INSERT INTO idempotency_keys (key, request_hash, created_at, locked_until, expires_at, tenant_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (tenant_id, key) DO UPDATE
+SET request_hash = EXCLUDED.request_hash, status_code = NULL, headers = NULL, body = NULL,
+    created_at = EXCLUDED.created_at, locked_until = EXCLUDED.locked_until, expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+   OR (idempotency_keys.status_code IS NULL
+       AND COALESCE(idempotency_keys.locked_until, idempotency_keys.created_at) <= EXCLUDED.created_at)
+RETURNING key;
+
+-- name: GetIdempotencyKey
+SELECT key, request_hash, COALESCE(status_code, 0), headers, body, created_at, expires_at
+FROM idempotency_keys
+WHERE key = $1 AND tenant_id = $2;
+
+-- name: CompleteIdempotencyKey
+-- created_at отличает резервирование запроса от повторного резервирования после истечения locked_until
+UPDATE idempotency_keys
+SET status_code = $2, headers = $3, body = $4, locked_until = NULL
+WHERE key = $1 AND created_at = $5 AND status_code IS NULL AND tenant_id = $6;
+
+-- name: ExtendIdempotencyKey
+-- Продлевает резервирование выполняющегося запроса, если ключ не занят заново и ответ ещё не сохранён
+UPDATE idempotency_keys
+SET locked_until = $3
+WHERE key = $1 AND created_at = $2 AND status_code IS NULL AND tenant_id = $4;
+
+-- name: ReleaseIdempotencyKey
+DELETE FROM idempotency_keys
+WHERE key = $1 AND created_at = $2 AND status_code IS NULL AND tenant_id = $3;
+
+-- name: PurgeExpiredIdempotencyKeys
+-- Очистка выполняется для всех арендаторов
+DELETE FROM idempotency_keys
+WHERE expires_at <= $1;
+
+-- name: FindSimilarPersons
+WITH target AS (
+    SELECT lower(translate(btrim($1::text) || ' ' || coalesce(btrim($2::text), '') || ' ' || coalesce(btrim($3::text), ''), 'Ёё', 'Ее')) AS full_name
+)
+SELECT p.id, p.name, p.surname, p.patronymic, p.age, p.gender, p.nationality, p.created_at, p.updated_at, p.version, p.deleted_at,
+       similarity(p.full_name_normalized, target.full_name) AS similarity
+FROM persons p, target
+WHERE p.tenant_id = $6 AND p.deleted_at IS NULL AND p.id <> $4 AND p.full_name_normalized % target.full_name
+ORDER BY similarity DESC, p.id
+LIMIT $5;
+
+-- name: GetPersonsByIDsForUpdate
+SELECT id, name, surname, patronymic, age, gender, nationality, created_at, updated_at, version, deleted_at
+FROM persons
+WHERE id = ANY($1) AND deleted_at IS NULL AND tenant_id = $2
+ORDER BY id
+FOR UPDATE;
+
+-- name: GetAPIKeyByHash
+SELECT id, name, key_hash, roles, tenant_id, created_at, expires_at, revoked_at, last_used_at
+FROM api_keys
+WHERE key_hash = $1;
+
+-- name: CreateAPIKey
+INSERT INTO api_keys (name, key_hash, roles, tenant_id, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, created_at;
+
+-- name: TouchAPIKey
+UPDATE api_keys
+SET last_used_at = $2
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $3);
+
+-- name: TakeRateLimitToken
+-- Ведро пополняется на $2 токенов в секунду до ёмкости $3; строка не обновляется, если токена нет.
+-- clock_timestamp() не зависит от TimeZone сессии и начала транзакции, поэтому реплики считают время одинаково
+INSERT INTO rate_limit_buckets AS b (key, tokens, updated_at)
+VALUES ($1, $3::float8 - 1, clock_timestamp())
+ON CONFLICT (key) DO UPDATE
+SET tokens = LEAST($3::float8, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at)::float8 * $2::float8) - 1,
+    updated_at = clock_timestamp()
+WHERE LEAST($3::float8, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at)::float8 * $2::float8) >= 1
+RETURNING tokens;
+
+-- name: GetRateLimitTokens
+SELECT LEAST($3::float8, tokens + EXTRACT(EPOCH FROM clock_timestamp() - updated_at)::float8 * $2::float8)
+FROM rate_limit_buckets
+WHERE key = $1;
+
+-- name: PurgeIdleRateLimitBuckets
+DELETE FROM rate_limit_buckets
+WHERE updated_at < clock_timestamp() - $1::interval;
+
+-- name: GetSchemaVersion
+-- Версия последней применённой миграции goose
+SELECT COALESCE(MAX(version_id), 0) FROM goose_db_version WHERE is_applied;
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RateLimitRepository хранит ведра ограничения частоты запросов в таблице rate_limit_buckets.
+// Время пополнения берётся из часов Postgres, поэтому расхождение часов экземпляров не влияет на лимиты.
+type RateLimitRepository struct {
+	db      *pgxpool.Pool
+	queries map[string]string
+}
+
+// NewRateLimitRepository создаёт новый репозиторий ведер ограничения частоты запросов.
+func NewRateLimitRepository(db *pgxpool.Pool) (*RateLimitRepository, error) {
+	queries, err := loadQueries()
+	if err != nil {
+		return nil, err
+	}
+	return &RateLimitRepository{db: db, queries: queries}, nil
+}
+
+// Take забирает токен из ведра key одним запросом; при отказе дополнительно читает число токенов в ведре.
+func (r *RateLimitRepository) Take(ctx context.Context, key string, rate float64, burst int) (float64, bool, error) {
+	var tokens float64
+	err := r.db.QueryRow(ctx, r.queries["TakeRateLimitToken"], key, rate, burst).Scan(&tokens)
+	if err == nil {
+		return tokens, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, fmt.Errorf("не удалось получить токен ограничения запросов: %w", err)
+	}
+	if err := r.db.QueryRow(ctx, r.queries["GetRateLimitTokens"], key, rate, burst).Scan(&tokens); err != nil {
+		return 0, false, fmt.Errorf("не удалось получить состояние ограничения запросов: %w", err)
+	}
+	return tokens, false, nil
+}
+
+// PurgeIdle удаляет ведра, не использовавшиеся дольше idle, и возвращает их число.
+func (r *RateLimitRepository) PurgeIdle(ctx context.Context, idle time.Duration) (int64, error) {
+	tag, err := r.db.Exec(ctx, r.queries["PurgeIdleRateLimitBuckets"], idle)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось удалить неиспользуемые ведра ограничения запросов: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
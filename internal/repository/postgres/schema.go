@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SchemaRepository читает версию схемы из таблицы goose_db_version.
+type SchemaRepository struct {
+	db      *pgxpool.Pool
+	queries map[string]string
+}
+
+// NewSchemaRepository создаёт новый репозиторий версии схемы.
+func NewSchemaRepository(db *pgxpool.Pool) (*SchemaRepository, error) {
+	queries, err := loadQueries()
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaRepository{db: db, queries: queries}, nil
+}
+
+// Version возвращает версию последней применённой миграции.
+func (r *SchemaRepository) Version(ctx context.Context) (int64, error) {
+	var version int64
+	if err := r.db.QueryRow(ctx, r.queries["GetSchemaVersion"]).Scan(&version); err != nil {
+		return 0, fmt.Errorf("не удалось получить версию схемы: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"person-service/internal/models"
+	"person-service/internal/repository"
+	"person-service/internal/tenant"
+	pg "person-service/pkg/postgres"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testDSNEnv — переменная окружения с DSN базы данных для тестов; без неё тесты с Postgres пропускаются.
+const testDSNEnv = "PERSON_SERVICE_TEST_DSN"
+
+// testPool подключается к тестовой базе из PERSON_SERVICE_TEST_DSN и применяет к ней миграции.
+func testPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s не задан, тест с Postgres пропущен", testDSNEnv)
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("не удалось подключиться к тестовой базе: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	migrator, err := pg.NewMigrator(&pg.Pool{Pool: pool})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer migrator.Close()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+// tenantContexts возвращает контексты двух арендаторов, уникальных для запуска теста.
+func tenantContexts() (a, b context.Context) {
+	suffix := time.Now().UnixNano()
+	a = tenant.WithID(context.Background(), fmt.Sprintf("isolation-a-%d", suffix))
+	b = tenant.WithID(context.Background(), fmt.Sprintf("isolation-b-%d", suffix))
+	return a, b
+}
+
+// createPerson создаёт запись с именем name от имени арендатора из ctx.
+func createPerson(t *testing.T, ctx context.Context, repo *PersonRepository, name string) *models.Person {
+	t.Helper()
+	surname := "Изоляцияев"
+	person := &models.Person{Name: name, Surname: &surname}
+	if err := repo.Create(ctx, person); err != nil {
+		t.Fatalf("не удалось создать запись: %v", err)
+	}
+	return person
+}
+
+func TestTenantIsolation(t *testing.T) {
+	repo, err := NewPersonRepository(testPool(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctxA, ctxB := tenantContexts()
+	name := fmt.Sprintf("Арендатор%d", time.Now().UnixNano())
+	filters := map[string]string{"name": name}
+
+	person := createPerson(t, ctxA, repo, name)
+	source := createPerson(t, ctxA, repo, name)
+	own := createPerson(t, ctxB, repo, "Собственный")
+
+	// unchanged проверяет, что запись арендатора A не изменена операциями арендатора B
+	unchanged := func(t *testing.T, id int) {
+		t.Helper()
+		got, err := repo.GetByID(ctxA, id, true, nil)
+		if err != nil {
+			t.Fatalf("запись арендатора A недоступна ему самому: %v", err)
+		}
+		if got.Name != name || got.DeletedAt != nil || got.Version != 1 {
+			t.Fatalf("запись арендатора A изменена другим арендатором: %+v", got)
+		}
+	}
+	notFound := func(t *testing.T, err error) {
+		t.Helper()
+		if !repository.IsNotFound(err) {
+			t.Fatalf("ожидалась NotFoundError, получено %v", err)
+		}
+	}
+
+	t.Run("GetByID", func(t *testing.T) {
+		_, err := repo.GetByID(ctxB, person.ID, true, nil)
+		notFound(t, err)
+	})
+
+	t.Run("GetByIDs", func(t *testing.T) {
+		persons, err := repo.GetByIDs(ctxB, []int{person.ID, source.ID}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(persons) != 0 {
+			t.Fatalf("арендатору B видны %d записей арендатора A", len(persons))
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		persons, err := repo.List(ctxB, 100, 0, map[string]string{"name": name, "include_deleted": "true"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(persons) != 0 {
+			t.Fatalf("арендатору B видны %d записей арендатора A", len(persons))
+		}
+		count, err := repo.Count(ctxB, filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Fatalf("арендатору B подсчитаны %d записей арендатора A", count)
+		}
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		replacement := &models.Person{ID: person.ID, Name: "Чужой"}
+		_, err := repo.Replace(ctxB, replacement, false)
+		notFound(t, err)
+
+		replacement = &models.Person{ID: person.ID, Name: "Чужой"}
+		if _, err := repo.Replace(ctxB, replacement, true); !errors.Is(err, repository.ErrIDUnavailable) {
+			t.Fatalf("ожидалась ErrIDUnavailable при создании с ID арендатора A, получено %v", err)
+		}
+		unchanged(t, person.ID)
+	})
+
+	t.Run("ApplyPatch", func(t *testing.T) {
+		_, err := repo.ApplyPatch(ctxB, person.ID, models.ActionUpdate, func(p *models.Person) error {
+			t.Error("изменение применено к записи арендатора A")
+			p.Name = "Чужой"
+			return nil
+		})
+		notFound(t, err)
+		unchanged(t, person.ID)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		notFound(t, repo.Delete(ctxB, person.ID))
+		unchanged(t, person.ID)
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		deleted := createPerson(t, ctxA, repo, name+"Удалённый")
+		if err := repo.Delete(ctxA, deleted.ID); err != nil {
+			t.Fatal(err)
+		}
+		_, err := repo.Restore(ctxB, deleted.ID)
+		notFound(t, err)
+		got, err := repo.GetByID(ctxA, deleted.ID, true, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.DeletedAt == nil {
+			t.Fatal("запись арендатора A восстановлена другим арендатором")
+		}
+	})
+
+	t.Run("BulkPatch", func(t *testing.T) {
+		surname := "Чужой"
+		affected, err := repo.BulkPatch(ctxB, filters, &models.PersonUpdate{Surname: &surname}, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if affected != 0 {
+			t.Fatalf("арендатор B изменил %d записей арендатора A", affected)
+		}
+		unchanged(t, person.ID)
+	})
+
+	t.Run("BulkDelete", func(t *testing.T) {
+		affected, err := repo.BulkDelete(ctxB, filters, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if affected != 0 {
+			t.Fatalf("арендатор B удалил %d записей арендатора A", affected)
+		}
+		unchanged(t, person.ID)
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		merge := func(target *models.Person, sources []*models.Person) error {
+			t.Error("слияние выполнено с записью арендатора A")
+			return nil
+		}
+		_, err := repo.Merge(ctxB, own.ID, []int{person.ID}, merge)
+		notFound(t, err)
+		_, err = repo.Merge(ctxB, person.ID, []int{source.ID}, merge)
+		notFound(t, err)
+		unchanged(t, person.ID)
+		unchanged(t, source.ID)
+	})
+
+	t.Run("FindSimilar", func(t *testing.T) {
+		candidates, err := repo.FindSimilar(ctxB, person, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, candidate := range candidates {
+			if candidate.Person.ID == person.ID || candidate.Person.ID == source.ID {
+				t.Fatalf("арендатору B предложена запись арендатора A %d", candidate.Person.ID)
+			}
+		}
+	})
+
+	t.Run("History", func(t *testing.T) {
+		entries, err := repo.ListHistory(ctxB, person.ID, 100, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Fatalf("арендатору B видны %d изменений записи арендатора A", len(entries))
+		}
+		entries, err = repo.ListRecentHistory(ctxB, []int{person.ID}, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Fatalf("арендатору B видны %d изменений записи арендатора A", len(entries))
+		}
+		entry, err := repo.GetHistoryEntryAsOf(ctxB, person.ID, time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry != nil {
+			t.Fatal("арендатору B видно состояние записи арендатора A на момент времени")
+		}
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		err := repo.Stream(ctxB, map[string]string{"include_deleted": "true"}, func(p *models.Person) error {
+			if p.ID != own.ID {
+				t.Errorf("в выгрузку арендатора B попала запись %d", p.ID)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
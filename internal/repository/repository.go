@@ -2,15 +2,128 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"person-service/internal/models"
+	"time"
 )
 
+// ErrBatchAborted возвращается, если пакетная операция отменена из-за ошибки одного из элементов.
+var ErrBatchAborted = errors.New("пакет отменён из-за ошибки элемента")
+
+// ErrBulkLimitExceeded возвращается, если массовая операция затрагивает больше записей, чем разрешено.
+var ErrBulkLimitExceeded = errors.New("превышен лимит записей массовой операции")
+
+// ErrIDUnavailable возвращается, если запись нельзя создать с указанным ID, так как он занят записью
+// другого арендатора. Сама запись не раскрывается.
+var ErrIDUnavailable = errors.New("ID недоступен для создания записи: он уже занят")
+
+// ErrBulkCountMismatch возвращается, если число записей под фильтром изменилось после предпросмотра.
+var ErrBulkCountMismatch = errors.New("число записей не совпадает с предпросмотром")
+
+// NotFoundError сообщает об отсутствии записи с указанным ID.
+type NotFoundError struct {
+	ID int
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("запись с id %d не найдена", e.ID)
+}
+
+// IsNotFound проверяет, вызвана ли ошибка отсутствием записи.
+func IsNotFound(err error) bool {
+	var nf *NotFoundError
+	return errors.As(err, &nf)
+}
+
 // PersonRepository определяет методы для работы с записями о людях.
 type PersonRepository interface {
 	Create(ctx context.Context, person *models.Person) error
-	GetByID(ctx context.Context, id int) (*models.Person, error)
-	Update(ctx context.Context, person *models.Person) error
-	Patch(ctx context.Context, id int, update *models.PersonUpdate) error
+	// CreateBatch создаёт записи в одной транзакции и возвращает ошибки по элементам.
+	CreateBatch(ctx context.Context, persons []*models.Person, atomic bool) ([]error, error)
+	// GetByID возвращает запись по ID; удалённые записи возвращаются только при includeDeleted.
+	// Из базы читаются только поля fields, nil означает все поля.
+	GetByID(ctx context.Context, id int, includeDeleted bool, fields []string) (*models.Person, error)
+	// GetByIDs возвращает записи с указанными ID; отсутствующие ID пропускаются.
+	GetByIDs(ctx context.Context, ids []int, includeDeleted bool) ([]*models.Person, error)
+	// Replace полностью заменяет запись; при createIfAbsent отсутствующая запись создаётся с ID из person,
+	// а последовательность ID сдвигается за него. Для ID, занятого другим арендатором, возвращается ErrIDUnavailable.
+	Replace(ctx context.Context, person *models.Person, createIfAbsent bool) (created bool, err error)
+	// ApplyPatch атомарно читает запись, изменяет её функцией apply и сохраняет результат в записи и истории.
+	ApplyPatch(ctx context.Context, id int, action models.HistoryAction, apply func(person *models.Person) error) (*models.Person, error)
+	// Delete помечает запись удалённой.
 	Delete(ctx context.Context, id int) error
-	List(ctx context.Context, limit, offset int, filters map[string]string) ([]*models.Person, error)
+	// Restore снимает пометку удаления с записи.
+	Restore(ctx context.Context, id int) (*models.Person, error)
+	// PurgeDeleted физически удаляет записи, помеченные удалёнными раньше before.
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	// List возвращает страницу записей под фильтрами в порядке filters["sort"]; из базы читаются только поля fields, nil означает все поля.
+	List(ctx context.Context, limit, offset int, filters map[string]string, fields []string) ([]*models.Person, error)
+	// Stream передаёт в fn все записи под фильтрами, не загружая выборку в память целиком.
+	Stream(ctx context.Context, filters map[string]string, fn func(person *models.Person) error) error
+	// Count возвращает число записей, подходящих под фильтры списка.
+	Count(ctx context.Context, filters map[string]string) (int, error)
+	// BulkPatch применяет update ко всем записям под фильтрами в одной транзакции и возвращает число изменённых.
+	BulkPatch(ctx context.Context, filters map[string]string, update *models.PersonUpdate, expected, maxRows int) (int, error)
+	// BulkDelete помечает удалёнными все записи под фильтрами в одной транзакции и возвращает их число.
+	BulkDelete(ctx context.Context, filters map[string]string, expected, maxRows int) (int, error)
+	// FindSimilar возвращает до limit записей, полное имя которых похоже на имя person, кроме записи excludeID.
+	FindSimilar(ctx context.Context, person *models.Person, excludeID, limit int) ([]*models.DuplicateCandidate, error)
+	// Merge сливает записи sourceIDs в запись targetID: merge изменяет целевую запись, источники помечаются удалёнными.
+	Merge(ctx context.Context, targetID int, sourceIDs []int, merge func(target *models.Person, sources []*models.Person) error) (*models.Person, error)
+	// ListHistory возвращает изменения записи, начиная с последних.
+	ListHistory(ctx context.Context, personID, limit, offset int) ([]*models.HistoryEntry, error)
+	// ListHistorySince возвращает изменения записи, сделанные после since, начиная с последних.
+	ListHistorySince(ctx context.Context, personID int, since time.Time) ([]*models.HistoryEntry, error)
+	// ListRecentHistory возвращает до perPerson последних изменений каждой из записей personIDs.
+	ListRecentHistory(ctx context.Context, personIDs []int, perPerson int) ([]*models.HistoryEntry, error)
+	// ListLatestHistoryByAction возвращает последнее изменение вида action для каждой из записей personIDs.
+	ListLatestHistoryByAction(ctx context.Context, personIDs []int, action models.HistoryAction) ([]*models.HistoryEntry, error)
+	// ListStaleEnrichment возвращает до limit неудалённых записей с ID больше afterID, обогащение которых
+	// не проверялось с момента before, в порядке ID.
+	ListStaleEnrichment(ctx context.Context, before time.Time, afterID, limit int) ([]*models.Person, error)
+	// MarkEnriched отмечает, что обогащение записи проверено в момент at.
+	MarkEnriched(ctx context.Context, id int, at time.Time) error
+	// GetHistoryEntryAsOf возвращает последнее изменение записи не позже asOf или nil.
+	GetHistoryEntryAsOf(ctx context.Context, personID int, asOf time.Time) (*models.HistoryEntry, error)
+}
+
+// IdempotencyRepository хранит ключи идемпотентности и сохранённые ответы.
+type IdempotencyRepository interface {
+	// Reserve резервирует ключ за запросом до lockedUntil; если ключ занят, не истёк и запрос с ним
+	// выполняется не дольше lockedUntil, возвращает существующую запись и reserved=false.
+	Reserve(ctx context.Context, key, requestHash string, now, lockedUntil, expiresAt time.Time) (*models.IdempotencyRecord, bool, error)
+	// Complete сохраняет ответ на запрос с ключом key, зарезервированным в момент reservedAt.
+	Complete(ctx context.Context, key string, reservedAt time.Time, statusCode int, headers map[string]string, body []byte) error
+	// Extend переносит срок резервирования ключа, зарезервированного в момент reservedAt, на lockedUntil.
+	Extend(ctx context.Context, key string, reservedAt, lockedUntil time.Time) error
+	// Release освобождает ключ, зарезервированный в момент reservedAt, ответ для которого не был сохранён.
+	Release(ctx context.Context, key string, reservedAt time.Time) error
+	// PurgeExpired удаляет ключи, истёкшие не позже before.
+	PurgeExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// APIKeyRepository хранит хэши ключей API.
+type APIKeyRepository interface {
+	// GetByHash возвращает ключ по SHA-256 хэшу или nil, если ключа нет.
+	GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	// Create сохраняет ключ по его хэшу и заполняет ID и время создания.
+	Create(ctx context.Context, key *models.APIKey) error
+	// Touch отмечает использование ключа в момент at, если предыдущая отметка старше notAfter.
+	Touch(ctx context.Context, id int, at, notAfter time.Time) error
+}
+
+// RateLimitRepository хранит ведра ограничения частоты запросов, общие для всех экземпляров сервиса.
+type RateLimitRepository interface {
+	// Take забирает токен из ведра key, которое пополняется на rate токенов в секунду до ёмкости burst.
+	// Возвращает число токенов, оставшихся в ведре, и allowed=false, если токена не было.
+	Take(ctx context.Context, key string, rate float64, burst int) (tokens float64, allowed bool, err error)
+	// PurgeIdle удаляет ведра, не использовавшиеся дольше idle, и возвращает их число.
+	PurgeIdle(ctx context.Context, idle time.Duration) (int64, error)
+}
+
+// SchemaRepository сообщает версию схемы базы данных.
+type SchemaRepository interface {
+	// Version возвращает версию последней применённой миграции.
+	Version(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"person-service/internal/auth"
+	"person-service/internal/models"
+	"person-service/internal/ratelimit"
+	"person-service/internal/repository"
+	"person-service/internal/tenant"
+	"person-service/internal/tracing"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// enrichConcurrency ограничивает число имён, обогащаемых одновременно при пакетном создании.
+const enrichConcurrency = 4
+
+// CreateBatch создаёт записи пакетом, все записи сохраняются в одной транзакции.
+// Если enrich=true, обогащение выполняется один раз для каждого уникального имени, а каждое имя вне кэша
+// расходует токен лимита create из контекста (ratelimit.WithScope). Элементы с именами, на которые токенов
+// не хватило, завершаются ошибкой; если не хватило ни на одно имя или режим all_or_nothing, возвращается
+// *ratelimit.LimitedError.
+// В режиме all_or_nothing ошибка любого элемента отменяет весь пакет, в режиме partial сохраняются корректные элементы.
+func (s *PersonService) CreateBatch(ctx context.Context, inputs []models.PersonInput, mode models.BatchMode, enrich bool) (_ *models.BatchResult, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.CreateBatch")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermBulk); err != nil {
+		return nil, err
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("пакет не содержит записей")
+	}
+
+	result := &models.BatchResult{Mode: mode, Items: make([]models.BatchItemResult, len(inputs))}
+	names := make(map[string]struct{})
+	for i := range inputs {
+		result.Items[i].Index = i
+		if err := inputs[i].Validate(); err != nil {
+			result.Items[i].Status = models.BatchItemFailed
+			result.Items[i].Error = fmt.Sprintf("валидация входных данных: %v", err)
+			continue
+		}
+		names[inputs[i].Name] = struct{}{}
+	}
+
+	enriched := map[string]enrichResult{}
+	if enrich {
+		denied, limitErr := s.chargeEnrichment(ctx, names)
+		if limitErr != nil && (len(denied) == len(names) || mode == models.BatchModeAllOrNothing) {
+			s.log(ctx).Info("Пакет отклонён: превышен лимит обогащений", zap.Int("names", len(names)))
+			return nil, limitErr
+		}
+		for name := range denied {
+			delete(names, name)
+		}
+		enriched = s.enrichNames(ctx, names)
+		for name := range denied {
+			enriched[name] = enrichResult{err: limitErr}
+		}
+	}
+
+	var persons []*models.Person
+	var indexes []int
+	now := time.Now()
+	for i := range inputs {
+		item := &result.Items[i]
+		if item.Status == models.BatchItemFailed {
+			continue
+		}
+		person := inputs[i].ToPerson(now)
+		if enrich {
+			res := enriched[inputs[i].Name]
+			if res.err != nil {
+				item.Status = models.BatchItemFailed
+				item.Error = res.err.Error()
+				continue
+			}
+			res.data.apply(person)
+		}
+		if err := person.Validate(); err != nil {
+			item.Status = models.BatchItemFailed
+			item.Error = fmt.Sprintf("валидация обогащённых данных: %v", err)
+			continue
+		}
+		persons = append(persons, person)
+		indexes = append(indexes, i)
+	}
+
+	atomic := mode == models.BatchModeAllOrNothing
+	if atomic && len(persons) < len(inputs) {
+		s.finishBatch(result, indexes)
+		s.log(ctx).Info("Пакет отменён: есть некорректные элементы", zap.Int("failed", result.Failed))
+		return result, nil
+	}
+	if len(persons) == 0 {
+		s.finishBatch(result, nil)
+		return result, nil
+	}
+
+	itemErrs, err := s.repo.CreateBatch(ctx, persons, atomic)
+	if err != nil && !errors.Is(err, repository.ErrBatchAborted) {
+		return nil, fmt.Errorf("не удалось создать записи: %w", err)
+	}
+	for j, i := range indexes {
+		if itemErrs[j] != nil {
+			result.Items[i].Status = models.BatchItemFailed
+			result.Items[i].Error = itemErrs[j].Error()
+		} else if err == nil {
+			result.Items[i].Status = models.BatchItemCreated
+			result.Items[i].Person = persons[j]
+		}
+	}
+	s.finishBatch(result, indexes)
+
+	s.log(ctx).Info("Пакет записей обработан",
+		zap.String("mode", string(mode)), zap.Int("created", result.Created), zap.Int("failed", result.Failed))
+	return result, nil
+}
+
+// finishBatch помечает необработанные элементы пропущенными и подсчитывает итоги.
+func (s *PersonService) finishBatch(result *models.BatchResult, indexes []int) {
+	for _, i := range indexes {
+		if result.Items[i].Status == "" {
+			result.Items[i].Status = models.BatchItemSkipped
+		}
+	}
+	for _, item := range result.Items {
+		switch item.Status {
+		case models.BatchItemCreated:
+			result.Created++
+		case models.BatchItemFailed:
+			result.Failed++
+		}
+	}
+}
+
+// enrichResult содержит результат обогащения одного имени.
+type enrichResult struct {
+	data *enrichment
+	err  error
+}
+
+// chargeEnrichment расходует по токену лимита create на каждое имя, которого нет в кэше обогащения арендатора:
+// такое имя означает платные запросы к внешним API, как и одиночное создание. Имена перебираются по алфавиту;
+// после первого отказа токены больше не расходуются. Возвращает имена, на которые токенов не хватило, и ошибку лимита.
+func (s *PersonService) chargeEnrichment(ctx context.Context, names map[string]struct{}) (map[string]struct{}, *ratelimit.LimitedError) {
+	now := time.Now()
+	tenantID := tenant.IDFromContext(ctx)
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		if s.cache.get(enrichCacheKey{tenant: tenantID, name: name}, now) == nil {
+			sorted = append(sorted, name)
+		}
+	}
+	slices.Sort(sorted)
+
+	for i, name := range sorted {
+		result := ratelimit.AllowContext(ctx, ratelimit.ClassCreate)
+		if result.Allowed {
+			continue
+		}
+		denied := make(map[string]struct{}, len(sorted)-i)
+		for _, name := range sorted[i:] {
+			denied[name] = struct{}{}
+		}
+		s.log(ctx).Info("Лимит create исчерпан при обогащении пакета",
+			zap.String("name", name), zap.Int("denied", len(denied)))
+		return denied, &ratelimit.LimitedError{Class: ratelimit.ClassCreate, Result: result}
+	}
+	return nil, nil
+}
+
+// enrichNames обогащает уникальные имена параллельно, не более enrichConcurrency одновременно.
+func (s *PersonService) enrichNames(ctx context.Context, names map[string]struct{}) map[string]enrichResult {
+	results := make(map[string]enrichResult, len(names))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, enrichConcurrency)
+	for name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			data, err := s.enrich(ctx, name)
+			mu.Lock()
+			results[name] = enrichResult{data: data, err: err}
+			mu.Unlock()
+		}(name)
+	}
+	wg.Wait()
+	s.log(ctx).Info("Имена пакета обогащены", zap.Int("names", len(names)))
+	return results
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"person-service/internal/auth"
+	"person-service/internal/models"
+	"person-service/internal/tracing"
+
+	"go.uber.org/zap"
+)
+
+// PreviewBulk возвращает число записей, которые затронет массовая операция с указанными фильтрами.
+func (s *PersonService) PreviewBulk(ctx context.Context, filters map[string]string) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.PreviewBulk")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermBulk); err != nil {
+		return 0, err
+	}
+	if len(filters) == 0 {
+		return 0, fmt.Errorf("для массовой операции нужен хотя бы один фильтр")
+	}
+	count, err := s.repo.Count(ctx, filters)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось подсчитать записи: %w", err)
+	}
+	return count, nil
+}
+
+// BulkPatch частично обновляет все записи под фильтрами.
+// expected должен совпадать с числом записей из предпросмотра, иначе операция отменяется.
+func (s *PersonService) BulkPatch(ctx context.Context, filters map[string]string, update *models.PersonUpdate, expected, maxRows int) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.BulkPatch")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermBulk); err != nil {
+		return 0, err
+	}
+	if len(filters) == 0 {
+		return 0, fmt.Errorf("для массовой операции нужен хотя бы один фильтр")
+	}
+	if update.Name == nil && update.Surname == nil && update.Patronymic == nil &&
+		update.Age == nil && update.Gender == nil && update.Nationality == nil {
+		return 0, fmt.Errorf("не указано ни одного поля для обновления")
+	}
+	if err := update.Validate(); err != nil {
+		return 0, fmt.Errorf("валидация данных: %w", err)
+	}
+
+	affected, err := s.repo.BulkPatch(ctx, filters, update, expected, maxRows)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось обновить записи: %w", err)
+	}
+	s.log(ctx).Info("Записи массово обновлены", zap.Any("filters", filters), zap.Int("affected", affected))
+	return affected, nil
+}
+
+// BulkDelete помечает удалёнными все записи под фильтрами.
+// expected должен совпадать с числом записей из предпросмотра, иначе операция отменяется.
+func (s *PersonService) BulkDelete(ctx context.Context, filters map[string]string, expected, maxRows int) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.BulkDelete")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermBulk); err != nil {
+		return 0, err
+	}
+	if len(filters) == 0 {
+		return 0, fmt.Errorf("для массовой операции нужен хотя бы один фильтр")
+	}
+
+	affected, err := s.repo.BulkDelete(ctx, filters, expected, maxRows)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось удалить записи: %w", err)
+	}
+	s.log(ctx).Info("Записи массово удалены", zap.Any("filters", filters), zap.Int("affected", affected))
+	return affected, nil
+}
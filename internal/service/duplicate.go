@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"person-service/internal/auth"
+	"person-service/internal/models"
+	"person-service/internal/tracing"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// duplicateCandidatesLimit ограничивает число похожих записей, которые оцениваются при поиске дубликатов.
+const duplicateCandidatesLimit = 50
+
+// DuplicateError сообщает, что создаваемая запись дублирует существующие.
+type DuplicateError struct {
+	IDs []int
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("найдены дубликаты записи: %v", e.IDs)
+}
+
+// IsDuplicate проверяет, вызвана ли ошибка найденными дубликатами.
+func IsDuplicate(err error) bool {
+	var de *DuplicateError
+	return errors.As(err, &de)
+}
+
+// FindDuplicates возвращает до limit записей, похожих на запись id, с оценкой не ниже minScore,
+// начиная с самых похожих.
+func (s *PersonService) FindDuplicates(ctx context.Context, id, limit int, minScore float64) (_ []*models.DuplicateCandidate, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.FindDuplicates")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermRead); err != nil {
+		return nil, err
+	}
+	person, err := s.repo.GetByID(ctx, id, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить запись: %w", err)
+	}
+	candidates, err := s.scoreDuplicates(ctx, person, id, minScore)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	s.log(ctx).Info("Поиск дубликатов выполнен", zap.Int("id", id), zap.Int("count", len(candidates)))
+	return candidates, nil
+}
+
+// checkDuplicates возвращает DuplicateError, если у записи есть дубликаты с оценкой не ниже порога из настроек.
+func (s *PersonService) checkDuplicates(ctx context.Context, person *models.Person) error {
+	candidates, err := s.scoreDuplicates(ctx, person, 0, s.duplicates.Threshold)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	ids := make([]int, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = candidate.Person.ID
+	}
+	s.log(ctx).Info("Создание записи отклонено из-за дубликатов", zap.Ints("duplicates", ids))
+	return &DuplicateError{IDs: ids}
+}
+
+// scoreDuplicates оценивает похожие записи и возвращает те, чья оценка не ниже minScore.
+func (s *PersonService) scoreDuplicates(ctx context.Context, person *models.Person, excludeID int, minScore float64) ([]*models.DuplicateCandidate, error) {
+	candidates, err := s.repo.FindSimilar(ctx, person, excludeID, duplicateCandidatesLimit)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось найти дубликаты: %w", err)
+	}
+	result := candidates[:0]
+	for _, candidate := range candidates {
+		candidate.Score, candidate.MatchedFields = models.ScoreDuplicate(person, candidate.Person, candidate.Similarity)
+		if candidate.Score >= minScore {
+			result = append(result, candidate)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	return result, nil
+}
+
+// Merge сливает записи-источники в целевую запись по правилам выбора значений и возвращает результат.
+// Источники помечаются удалёнными, связь между записями сохраняется в истории изменений.
+func (s *PersonService) Merge(ctx context.Context, req *models.MergeRequest) (_ *models.Person, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.Merge")
+	defer tracing.End(span, &err)
+
+	// Слияние изменяет целевую запись и удаляет источники
+	if err := s.authorize(ctx, auth.PermWrite); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, auth.PermDelete); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("валидация запроса: %w", err)
+	}
+	person, err := s.repo.Merge(ctx, req.TargetID, req.SourceIDs, func(target *models.Person, sources []*models.Person) error {
+		if err := models.MergePersons(target, sources, req.Rules); err != nil {
+			return err
+		}
+		if err := target.Validate(); err != nil {
+			return fmt.Errorf("валидация данных: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("не удалось слить записи: %w", err)
+	}
+	s.log(ctx).Info("Записи слиты", zap.Int("target_id", req.TargetID), zap.Ints("source_ids", req.SourceIDs))
+	return person, nil
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"person-service/internal/auth"
+	"person-service/internal/config"
+	"person-service/internal/models"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestMergeRequiresWrite(t *testing.T) {
+	policy, err := auth.NewPolicy(map[string][]string{"cleaner": {string(auth.PermDelete)}}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Репозиторий не нужен: проверка прав выполняется до обращения к нему
+	svc := NewPersonService(nil, zap.NewNop(), &config.APIs{}, &config.Duplicates{}, &config.Enrichment{}, policy)
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "cleaner", Roles: []string{"cleaner"}})
+
+	_, err = svc.Merge(ctx, &models.MergeRequest{TargetID: 1, SourceIDs: []int{2}})
+	if !errors.Is(err, auth.ErrForbidden) {
+		t.Fatalf("слияние без persons:write: ожидалась ошибка %v, получено %v", auth.ErrForbidden, err)
+	}
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"person-service/internal/config"
+	"person-service/internal/metrics"
+	"person-service/internal/tenant"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrEnrichmentQuotaExceeded возвращается, если арендатор исчерпал дневную квоту запросов к внешним API.
+var ErrEnrichmentQuotaExceeded = errors.New("исчерпана дневная квота обогащения")
+
+// enrichCacheKey — ключ кэша обогащения: имя в пределах арендатора.
+type enrichCacheKey struct {
+	tenant string
+	name   string
+}
+
+// enrichCacheEntry — результат обогащения и момент, после которого он устаревает.
+type enrichCacheEntry struct {
+	data    *enrichment
+	expires time.Time
+}
+
+// enrichCache хранит результаты обогащения отдельно для каждого арендатора.
+// Размер ограничен для каждого арендатора, чтобы один арендатор не вытеснял данные других,
+// а число арендаторов — maxTenants, чтобы память кэша была ограничена.
+type enrichCache struct {
+	ttl        time.Duration
+	size       int
+	maxTenants int
+	mu         sync.Mutex
+	entries    map[string]map[string]enrichCacheEntry
+}
+
+// newEnrichCache создаёт кэш; при нулевом ttl или size кэш отключён и возвращается nil,
+// нулевой maxTenants не ограничивает число арендаторов.
+func newEnrichCache(ttl time.Duration, size, maxTenants int) *enrichCache {
+	if ttl <= 0 || size <= 0 {
+		return nil
+	}
+	return &enrichCache{ttl: ttl, size: size, maxTenants: maxTenants, entries: make(map[string]map[string]enrichCacheEntry)}
+}
+
+// get возвращает неустаревший результат обогащения или nil.
+func (c *enrichCache) get(key enrichCacheKey, now time.Time) *enrichment {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key.tenant][key.name]
+	if !ok {
+		return nil
+	}
+	if !now.Before(entry.expires) {
+		delete(c.entries[key.tenant], key.name)
+		return nil
+	}
+	return entry.data
+}
+
+// put сохраняет результат обогащения. Если кэш арендатора заполнен, сначала удаляются устаревшие записи,
+// а если их нет — запись, которая устареет раньше всех. Для нового арендатора сверх maxTenants
+// результат не сохраняется, пока не устареют все записи какого-либо другого арендатора.
+func (c *enrichCache) put(key enrichCacheKey, data *enrichment, now time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := c.entries[key.tenant]
+	if entries == nil {
+		if c.maxTenants > 0 && len(c.entries) >= c.maxTenants && !c.evictTenant(now) {
+			return
+		}
+		entries = make(map[string]enrichCacheEntry)
+		c.entries[key.tenant] = entries
+	}
+	if _, ok := entries[key.name]; !ok && len(entries) >= c.size {
+		var oldest string
+		var oldestExpires time.Time
+		for name, entry := range entries {
+			if !now.Before(entry.expires) {
+				delete(entries, name)
+				continue
+			}
+			if oldest == "" || entry.expires.Before(oldestExpires) {
+				oldest, oldestExpires = name, entry.expires
+			}
+		}
+		if len(entries) >= c.size {
+			delete(entries, oldest)
+		}
+	}
+	entries[key.name] = enrichCacheEntry{data: data, expires: now.Add(c.ttl)}
+}
+
+// evictTenant удаляет кэш арендатора, все записи которого устарели; false, если такого нет.
+func (c *enrichCache) evictTenant(now time.Time) bool {
+	for tenantID, entries := range c.entries {
+		expired := true
+		for _, entry := range entries {
+			if now.Before(entry.expires) {
+				expired = false
+				break
+			}
+		}
+		if expired {
+			delete(c.entries, tenantID)
+			return true
+		}
+	}
+	return false
+}
+
+// enrichQuota считает обогащения каждого арендатора за текущие сутки UTC.
+// Счётчики хранятся в памяти процесса, поэтому квота действует на каждый экземпляр сервиса отдельно.
+// За сутки счётчики заводятся не более чем для maxTenants арендаторов без собственной квоты;
+// остальным обогащение в эти сутки не выполняется, чтобы смена арендатора не давала новую квоту.
+type enrichQuota struct {
+	daily      int
+	tenants    map[string]int
+	maxTenants int
+	mu         sync.Mutex
+	day        time.Time
+	used       map[string]int
+	// others — число арендаторов без собственной квоты, для которых за сутки заведены счётчики.
+	others int
+}
+
+// newEnrichQuota создаёт квоту; нулевой лимит означает отсутствие ограничения, нулевой maxTenants —
+// отсутствие ограничения числа арендаторов.
+func newEnrichQuota(daily int, tenants map[string]int, maxTenants int) *enrichQuota {
+	return &enrichQuota{daily: daily, tenants: tenants, maxTenants: maxTenants, used: make(map[string]int)}
+}
+
+// limit возвращает дневной лимит арендатора.
+func (q *enrichQuota) limit(tenantID string) int {
+	if limit, ok := q.tenants[tenantID]; ok {
+		return limit
+	}
+	return q.daily
+}
+
+// take расходует одно обогащение из квоты арендатора; false означает, что квота исчерпана.
+func (q *enrichQuota) take(tenantID string, now time.Time) bool {
+	limit := q.limit(tenantID)
+	if limit <= 0 {
+		return true
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if day := now.UTC().Truncate(24 * time.Hour); !day.Equal(q.day) {
+		q.day = day
+		q.others = 0
+		clear(q.used)
+	}
+	used, tracked := q.used[tenantID]
+	_, configured := q.tenants[tenantID]
+	counted := !tracked && !configured
+	if counted && q.maxTenants > 0 && q.others >= q.maxTenants {
+		return false
+	}
+	if used >= limit {
+		return false
+	}
+	if counted {
+		q.others++
+	}
+	q.used[tenantID] = used + 1
+	return true
+}
+
+// enrich возвращает возраст, пол и национальность по имени из кэша арендатора или из внешних API.
+// Запрос к внешним API расходует квоту арендатора, попадание в кэш — нет. Арендатор берётся из контекста,
+// куда его помещают только после проверки учётных данных (tenantScope, tenantInterceptor) или CLI.
+func (s *PersonService) enrich(ctx context.Context, name string) (*enrichment, error) {
+	now := time.Now()
+	key := enrichCacheKey{tenant: tenant.IDFromContext(ctx), name: name}
+	if s.cache != nil {
+		if data := s.cache.get(key, now); data != nil {
+			metrics.EnrichmentCache.WithLabelValues("hit").Inc()
+			return data, nil
+		}
+		metrics.EnrichmentCache.WithLabelValues("miss").Inc()
+	}
+	if !s.quota.take(key.tenant, now) {
+		s.log(ctx).Warn("Квота обогащения исчерпана", zap.String("tenant", key.tenant))
+		return nil, fmt.Errorf("%w: арендатор %s", ErrEnrichmentQuotaExceeded, key.tenant)
+	}
+
+	data, err := s.fetchEnrichment(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.put(key, data, now)
+	return data, nil
+}
+
+// newEnrichment создаёт кэш и квоту обогащения по настройкам.
+func newEnrichment(cfg *config.Enrichment) (*enrichCache, *enrichQuota) {
+	return newEnrichCache(cfg.CacheTTL, cfg.CacheSize, cfg.MaxTenants), newEnrichQuota(cfg.DailyQuota, cfg.TenantQuotas, cfg.MaxTenants)
+}
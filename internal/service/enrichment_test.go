@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestEnrichQuotaPerTenant(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	q := newEnrichQuota(2, map[string]int{"acme": 3}, 10)
+
+	for i := range 2 {
+		if !q.take("globex", now) {
+			t.Fatalf("globex: обогащение %d отклонено в пределах квоты", i+1)
+		}
+	}
+	if q.take("globex", now) {
+		t.Fatal("globex: квота не ограничила обогащения")
+	}
+	for i := range 3 {
+		if !q.take("acme", now) {
+			t.Fatalf("acme: обогащение %d отклонено в пределах собственной квоты", i+1)
+		}
+	}
+	if q.take("acme", now) {
+		t.Fatal("acme: собственная квота не ограничила обогащения")
+	}
+	if !q.take("globex", now.Add(24*time.Hour)) {
+		t.Fatal("globex: квота не обновилась в новые сутки")
+	}
+}
+
+func TestEnrichQuotaMaxTenants(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	q := newEnrichQuota(1, map[string]int{"acme": 1}, 3)
+
+	for i := range 3 {
+		if !q.take(fmt.Sprintf("tenant-%d", i), now) {
+			t.Fatalf("tenant-%d: обогащение отклонено до исчерпания числа арендаторов", i)
+		}
+	}
+	if q.take("tenant-3", now) {
+		t.Fatal("новый арендатор сверх max_tenants получил квоту")
+	}
+	if !q.take("acme", now) {
+		t.Fatal("арендатор с собственной квотой отклонён из-за max_tenants")
+	}
+	if !q.take("tenant-3", now.Add(24*time.Hour)) {
+		t.Fatal("число арендаторов не сбросилось в новые сутки")
+	}
+}
+
+func TestEnrichCachePerTenant(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	c := newEnrichCache(time.Hour, 10, 2)
+	data := &enrichment{}
+
+	c.put(enrichCacheKey{tenant: "acme", name: "Ivan"}, data, now)
+	if c.get(enrichCacheKey{tenant: "acme", name: "Ivan"}, now) != data {
+		t.Fatal("результат обогащения не найден в кэше арендатора")
+	}
+	if c.get(enrichCacheKey{tenant: "globex", name: "Ivan"}, now) != nil {
+		t.Fatal("результат обогащения виден другому арендатору")
+	}
+
+	c.put(enrichCacheKey{tenant: "globex", name: "Ivan"}, data, now)
+	c.put(enrichCacheKey{tenant: "initech", name: "Ivan"}, data, now)
+	if len(c.entries) != 2 {
+		t.Fatalf("кэш хранит %d арендаторов при max_tenants=2", len(c.entries))
+	}
+
+	later := now.Add(2 * time.Hour)
+	c.put(enrichCacheKey{tenant: "initech", name: "Ivan"}, data, later)
+	if c.get(enrichCacheKey{tenant: "initech", name: "Ivan"}, later) != data {
+		t.Fatal("арендатор не занял место арендатора с устаревшим кэшем")
+	}
+	if len(c.entries) != 2 {
+		t.Fatalf("кэш хранит %d арендаторов при max_tenants=2", len(c.entries))
+	}
+}
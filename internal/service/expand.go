@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"person-service/internal/auth"
+	"person-service/internal/models"
+	"person-service/internal/tracing"
+	"sort"
+	"strings"
+)
+
+// expandHistoryLimit ограничивает число изменений истории, раскрываемых для каждой записи.
+const expandHistoryLimit = 10
+
+// expander загружает связанные данные сразу для нескольких записей и возвращает их по ID записи.
+type expander func(ctx context.Context, personIDs []int) (map[int]any, error)
+
+// expanders возвращает поддерживаемые раскрытия по имени параметра expand.
+func (s *PersonService) expanders() map[string]expander {
+	return map[string]expander{
+		"history":    s.expandHistory,
+		"enrichment": s.expandEnrichment,
+	}
+}
+
+// ParseExpand разбирает список раскрытий из параметра expand, например "history,enrichment".
+func (s *PersonService) ParseExpand(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	available := s.expanders()
+	seen := map[string]bool{}
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := available[name]; !ok {
+			supported := make([]string, 0, len(available))
+			for key := range available {
+				supported = append(supported, key)
+			}
+			sort.Strings(supported)
+			return nil, fmt.Errorf("неизвестное раскрытие: %s, ожидается %s", name, strings.Join(supported, ", "))
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Expand загружает связанные данные names для записей persons.
+// Результат сопоставляет имени раскрытия данные по ID записи; у записей без данных значение nil.
+func (s *PersonService) Expand(ctx context.Context, persons []*models.Person, names []string) (_ map[string]map[int]any, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.Expand")
+	defer tracing.End(span, &err)
+
+	if len(names) == 0 || len(persons) == 0 {
+		return nil, nil
+	}
+	ids := make([]int, len(persons))
+	for i, person := range persons {
+		ids[i] = person.ID
+	}
+
+	available := s.expanders()
+	expanded := make(map[string]map[int]any, len(names))
+	for _, name := range names {
+		expand, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("неизвестное раскрытие: %s", name)
+		}
+		values, err := expand(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось раскрыть %s: %w", name, err)
+		}
+		expanded[name] = values
+	}
+	return expanded, nil
+}
+
+// RecentHistory возвращает до expandHistoryLimit последних изменений каждой из записей personIDs.
+// У записей без изменений в результате пустой список.
+func (s *PersonService) RecentHistory(ctx context.Context, personIDs []int) (_ map[int][]*models.HistoryEntry, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.RecentHistory")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermHistory); err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListRecentHistory(ctx, personIDs, expandHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить историю: %w", err)
+	}
+	grouped := make(map[int][]*models.HistoryEntry, len(personIDs))
+	for _, id := range personIDs {
+		grouped[id] = []*models.HistoryEntry{}
+	}
+	for _, entry := range entries {
+		grouped[entry.PersonID] = append(grouped[entry.PersonID], entry)
+	}
+	return grouped, nil
+}
+
+// LatestEnrichment возвращает последнее обогащение каждой из записей personIDs: полученные значения, источник и время.
+// Записи без обогащения в результат не попадают.
+func (s *PersonService) LatestEnrichment(ctx context.Context, personIDs []int) (_ map[int]*models.HistoryEntry, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.LatestEnrichment")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermRead); err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListLatestHistoryByAction(ctx, personIDs, models.ActionEnrich)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить обогащение: %w", err)
+	}
+	latest := make(map[int]*models.HistoryEntry, len(entries))
+	for _, entry := range entries {
+		latest[entry.PersonID] = entry
+	}
+	return latest, nil
+}
+
+// expandHistory раскрывает последние изменения записей.
+func (s *PersonService) expandHistory(ctx context.Context, personIDs []int) (map[int]any, error) {
+	grouped, err := s.RecentHistory(ctx, personIDs)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[int]any, len(grouped))
+	for id, list := range grouped {
+		values[id] = list
+	}
+	return values, nil
+}
+
+// expandEnrichment раскрывает последнее обогащение записей.
+func (s *PersonService) expandEnrichment(ctx context.Context, personIDs []int) (map[int]any, error) {
+	latest, err := s.LatestEnrichment(ctx, personIDs)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[int]any, len(latest))
+	for id, entry := range latest {
+		values[id] = entry
+	}
+	return values, nil
+}
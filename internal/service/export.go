@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"person-service/internal/auth"
+	"person-service/internal/models"
+	"person-service/internal/tracing"
+
+	"go.uber.org/zap"
+)
+
+// Export передаёт в fn все записи под фильтрами списка без ограничения по числу.
+func (s *PersonService) Export(ctx context.Context, filters map[string]string, fn func(person *models.Person) error) (err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.Export")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermRead); err != nil {
+		return err
+	}
+	if err := s.authorizeDeleted(ctx, filters["include_deleted"] == "true"); err != nil {
+		return err
+	}
+	count := 0
+	err = s.repo.Stream(ctx, filters, func(person *models.Person) error {
+		count++
+		return fn(person)
+	})
+	if err != nil {
+		return fmt.Errorf("не удалось выгрузить записи: %w", err)
+	}
+	s.log(ctx).Info("Записи выгружены", zap.Int("count", count))
+	return nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"person-service/internal/auth"
+	"person-service/internal/models"
+	"person-service/internal/repository"
+	"person-service/internal/tracing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// History возвращает историю изменений записи, начиная с последних.
+func (s *PersonService) History(ctx context.Context, id, limit, offset int) (_ []*models.HistoryEntry, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.History")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermHistory); err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListHistory(ctx, id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить историю: %w", err)
+	}
+	s.log(ctx).Info("История записи получена", zap.Int("id", id), zap.Int("count", len(entries)))
+	return entries, nil
+}
+
+// GetByIDAsOf возвращает состояние записи на момент asOf.
+// Состояние восстанавливается откатом изменений из истории, сделанных после asOf.
+func (s *PersonService) GetByIDAsOf(ctx context.Context, id int, asOf time.Time, includeDeleted bool) (_ *models.Person, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.GetByIDAsOf")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermHistory); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeDeleted(ctx, includeDeleted); err != nil {
+		return nil, err
+	}
+	person, err := s.repo.GetByID(ctx, id, true, nil)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить запись: %w", err)
+	}
+	entries, err := s.repo.ListHistorySince(ctx, id, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить историю: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Action == models.ActionCreate {
+			return nil, fmt.Errorf("запись ещё не существовала: %w", &repository.NotFoundError{ID: id})
+		}
+		if err := models.RevertChange(person, entry); err != nil {
+			return nil, err
+		}
+		person.Version = entry.Version - 1
+	}
+
+	if len(entries) > 0 {
+		last, err := s.repo.GetHistoryEntryAsOf(ctx, id, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось получить историю: %w", err)
+		}
+		if last != nil {
+			person.Version = last.Version
+			person.UpdatedAt = nil
+			if last.Version > 1 {
+				person.UpdatedAt = &last.ChangedAt
+			}
+		}
+	}
+	if person.DeletedAt != nil && !includeDeleted {
+		return nil, fmt.Errorf("запись была удалена: %w", &repository.NotFoundError{ID: id})
+	}
+
+	s.log(ctx).Info("Запись получена на момент времени", zap.Int("id", id), zap.Time("as_of", asOf))
+	return person, nil
+}
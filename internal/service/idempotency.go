@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"person-service/internal/models"
+	"person-service/internal/repository"
+	"person-service/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxIdempotencyKeyLength ограничивает длину заголовка Idempotency-Key.
+const maxIdempotencyKeyLength = 255
+
+// ErrIdempotencyKeyReused возвращается, если ключ уже использован для запроса с другим содержимым.
+var ErrIdempotencyKeyReused = errors.New("Idempotency-Key уже использован для другого запроса")
+
+// ErrIdempotencyInProgress возвращается, если запрос с тем же ключом ещё выполняется.
+var ErrIdempotencyInProgress = errors.New("запрос с этим Idempotency-Key ещё выполняется")
+
+// IdempotencyService обеспечивает однократное выполнение запросов с заголовком Idempotency-Key.
+type IdempotencyService struct {
+	repo        repository.IdempotencyRepository
+	logger      *zap.Logger
+	ttl         time.Duration
+	lockTimeout time.Duration
+}
+
+// NewIdempotencyService создаёт новый экземпляр IdempotencyService; ключи хранятся ttl.
+// Ключ запроса, не завершившегося за lockTimeout, например из-за падения процесса, можно занять заново.
+func NewIdempotencyService(repo repository.IdempotencyRepository, logger *zap.Logger, ttl, lockTimeout time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		repo:        repo,
+		logger:      logger,
+		ttl:         ttl,
+		lockTimeout: lockTimeout,
+	}
+}
+
+// Begin резервирует ключ за запросом с хэшем requestHash.
+// Для нового ключа возвращается незавершённая запись: запрос нужно выполнить и передать запись в Complete
+// или Release. Для повтора возвращается запись с сохранённым ответом (Completed).
+func (s *IdempotencyService) Begin(ctx context.Context, key, requestHash string) (*models.IdempotencyRecord, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, fmt.Errorf("Idempotency-Key должен содержать от 1 до %d символов", maxIdempotencyKeyLength)
+	}
+	// Postgres хранит время с точностью до микросекунды, а момент резервирования сравнивается точно
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	expiresAt := now.Add(s.ttl)
+	record, reserved, err := s.repo.Reserve(ctx, key, requestHash, now, now.Add(s.lockTimeout), expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return &models.IdempotencyRecord{Key: key, RequestHash: requestHash, CreatedAt: now, ExpiresAt: expiresAt}, nil
+	}
+	switch {
+	case record == nil:
+		return nil, ErrIdempotencyInProgress
+	case record.RequestHash != requestHash:
+		return nil, ErrIdempotencyKeyReused
+	case !record.Completed():
+		return nil, ErrIdempotencyInProgress
+	}
+	logger.FromContext(ctx, s.logger).Info("Повтор запроса по Idempotency-Key", zap.String("key", key), zap.Int("status", record.StatusCode))
+	return record, nil
+}
+
+// Complete сохраняет ответ на запрос, зарезервированный Begin, для последующих повторов.
+func (s *IdempotencyService) Complete(ctx context.Context, record *models.IdempotencyRecord, statusCode int, headers map[string]string, body []byte) error {
+	return s.repo.Complete(ctx, record.Key, record.CreatedAt, statusCode, headers, body)
+}
+
+// Hold продлевает резервирование ключа record, пока выполняется запрос: каждую треть lockTimeout срок
+// переносится на lockTimeout вперёд, поэтому повтор долгого запроса получает ErrIdempotencyInProgress,
+// а не выполняет его второй раз. Возвращённая функция прекращает продление.
+func (s *IdempotencyService) Hold(ctx context.Context, record *models.IdempotencyRecord) (stop func()) {
+	// Продление не зависит от отмены запроса клиентом: обработчик может ещё выполняться
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.lockTimeout / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			lockedUntil := time.Now().UTC().Add(s.lockTimeout)
+			if err := s.repo.Extend(ctx, record.Key, record.CreatedAt, lockedUntil); err != nil && ctx.Err() == nil {
+				logger.FromContext(ctx, s.logger).Error("Ошибка продления Idempotency-Key", zap.String("key", record.Key), zap.Error(err))
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// Release освобождает ключ запроса, зарезервированного Begin, чтобы запрос можно было повторить,
+// например после внутренней ошибки.
+func (s *IdempotencyService) Release(ctx context.Context, record *models.IdempotencyRecord) error {
+	return s.repo.Release(ctx, record.Key, record.CreatedAt)
+}
+
+// RunCleanup периодически удаляет истёкшие ключи до отмены ctx.
+func (s *IdempotencyService) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		purged, err := s.repo.PurgeExpired(ctx, time.Now().UTC())
+		if err != nil {
+			s.logger.Error("Ошибка очистки ключей идемпотентности", zap.Error(err))
+		} else if purged > 0 {
+			s.logger.Info("Истёкшие ключи идемпотентности удалены", zap.Int64("count", purged))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
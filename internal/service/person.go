@@ -5,42 +5,92 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"person-service/internal/auth"
 	"person-service/internal/config"
+	"person-service/internal/metrics"
 	"person-service/internal/models"
 	"person-service/internal/repository"
+	"person-service/internal/tracing"
+	"person-service/pkg/logger"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 // PersonService предоставляет бизнес-логику для работы с записями о людях.
 type PersonService struct {
-	repo   repository.PersonRepository
-	logger *zap.Logger
-	apis   *config.APIs
+	repo       repository.PersonRepository
+	logger     *zap.Logger
+	apis       *config.APIs
+	duplicates *config.Duplicates
+	policy     *auth.Policy
+	cache      *enrichCache
+	quota      *enrichQuota
+	httpClient *http.Client
 }
 
 // NewPersonService создаёт новый экземпляр PersonService.
-func NewPersonService(repo repository.PersonRepository, logger *zap.Logger, apis *config.APIs) *PersonService {
+// Если policy не nil, каждая операция проверяет права клиента из контекста.
+// Кэш и квота обогащения из enrichment ведутся отдельно для каждого арендатора.
+func NewPersonService(repo repository.PersonRepository, logger *zap.Logger, apis *config.APIs, duplicates *config.Duplicates, enrichment *config.Enrichment, policy *auth.Policy) *PersonService {
+	cache, quota := newEnrichment(enrichment)
 	return &PersonService{
-		repo:   repo,
-		logger: logger,
-		apis:   apis,
+		repo:       repo,
+		logger:     logger,
+		apis:       apis,
+		duplicates: duplicates,
+		policy:     policy,
+		cache:      cache,
+		quota:      quota,
+		httpClient: &http.Client{Transport: tracing.Transport(http.DefaultTransport)},
 	}
 }
 
+// log возвращает логгер с идентификаторами трассировки из ctx.
+func (s *PersonService) log(ctx context.Context) *zap.Logger {
+	return logger.FromContext(ctx, s.logger)
+}
+
+// authorize проверяет право клиента из контекста на операцию; без политики проверка не выполняется.
+func (s *PersonService) authorize(ctx context.Context, perm auth.Permission) error {
+	if s.policy == nil {
+		return nil
+	}
+	return s.policy.Authorize(ctx, perm)
+}
+
+// authorizeDeleted проверяет право на просмотр удалённых записей: как и восстановление, он доступен
+// только с auth.PermDelete. Без includeDeleted проверка не выполняется.
+func (s *PersonService) authorizeDeleted(ctx context.Context, includeDeleted bool) error {
+	if !includeDeleted {
+		return nil
+	}
+	return s.authorize(ctx, auth.PermDelete)
+}
+
+// get выполняет GET-запрос к API обогащения с контекстом запроса, чтобы передать трассировку и отмену.
+func (s *PersonService) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.httpClient.Do(req)
+}
+
 // fetchAge запрашивает возраст по имени через API.
 func (s *PersonService) fetchAge(ctx context.Context, name string) (*int, error) {
 	url := fmt.Sprintf("%s/?name=%s", s.apis.Agify, name)
-	resp, err := http.Get(url)
+	resp, err := s.get(ctx, url)
 	if err != nil {
-		s.logger.Error("Ошибка запроса к Agify API", zap.Error(err))
+		s.log(ctx).Error("Ошибка запроса к Agify API", zap.Error(err))
 		return nil, fmt.Errorf("не удалось получить возраст: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		s.logger.Error("Неуспешный ответ от Agify API", zap.Int("status", resp.StatusCode))
+		s.log(ctx).Error("Неуспешный ответ от Agify API", zap.Int("status", resp.StatusCode))
 		return nil, fmt.Errorf("неуспешный ответ от Agify API: %d", resp.StatusCode)
 	}
 
@@ -48,7 +98,7 @@ func (s *PersonService) fetchAge(ctx context.Context, name string) (*int, error)
 		Age *int `json:"age"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		s.logger.Error("Ошибка декодирования ответа Agify API", zap.Error(err))
+		s.log(ctx).Error("Ошибка декодирования ответа Agify API", zap.Error(err))
 		return nil, fmt.Errorf("не удалось декодировать ответ: %w", err)
 	}
 	return result.Age, nil
@@ -57,15 +107,15 @@ func (s *PersonService) fetchAge(ctx context.Context, name string) (*int, error)
 // fetchGender запрашивает пол по имени через API.
 func (s *PersonService) fetchGender(ctx context.Context, name string) (*models.GenderType, error) {
 	url := fmt.Sprintf("%s/?name=%s", s.apis.Genderize, name)
-	resp, err := http.Get(url)
+	resp, err := s.get(ctx, url)
 	if err != nil {
-		s.logger.Error("Ошибка запроса к Genderize API", zap.Error(err))
+		s.log(ctx).Error("Ошибка запроса к Genderize API", zap.Error(err))
 		return nil, fmt.Errorf("не удалось получить пол: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		s.logger.Error("Неуспешный ответ от Genderize API", zap.Int("status", resp.StatusCode))
+		s.log(ctx).Error("Неуспешный ответ от Genderize API", zap.Int("status", resp.StatusCode))
 		return nil, fmt.Errorf("неуспешный ответ от Genderize API: %d", resp.StatusCode)
 	}
 
@@ -73,7 +123,7 @@ func (s *PersonService) fetchGender(ctx context.Context, name string) (*models.G
 		Gender *string `json:"gender"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		s.logger.Error("Ошибка декодирования ответа Genderize API", zap.Error(err))
+		s.log(ctx).Error("Ошибка декодирования ответа Genderize API", zap.Error(err))
 		return nil, fmt.Errorf("не удалось декодировать ответ: %w", err)
 	}
 	if result.Gender == nil {
@@ -89,15 +139,15 @@ func (s *PersonService) fetchGender(ctx context.Context, name string) (*models.G
 // fetchNationality запрашивает национальность по имени через API.
 func (s *PersonService) fetchNationality(ctx context.Context, name string) (*string, error) {
 	url := fmt.Sprintf("%s/?name=%s", s.apis.Nationalize, name)
-	resp, err := http.Get(url)
+	resp, err := s.get(ctx, url)
 	if err != nil {
-		s.logger.Error("Ошибка запроса к Nationalize API", zap.Error(err))
+		s.log(ctx).Error("Ошибка запроса к Nationalize API", zap.Error(err))
 		return nil, fmt.Errorf("не удалось получить национальность: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		s.logger.Error("Неуспешный ответ от Nationalize API", zap.Int("status", resp.StatusCode))
+		s.log(ctx).Error("Неуспешный ответ от Nationalize API", zap.Int("status", resp.StatusCode))
 		return nil, fmt.Errorf("неуспешный ответ от Nationalize API: %d", resp.StatusCode)
 	}
 
@@ -107,7 +157,7 @@ func (s *PersonService) fetchNationality(ctx context.Context, name string) (*str
 		} `json:"country"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		s.logger.Error("Ошибка декодирования ответа Nationalize API", zap.Error(err))
+		s.log(ctx).Error("Ошибка декодирования ответа Nationalize API", zap.Error(err))
 		return nil, fmt.Errorf("не удалось декодировать ответ: %w", err)
 	}
 	if len(result.Country) == 0 {
@@ -116,37 +166,88 @@ func (s *PersonService) fetchNationality(ctx context.Context, name string) (*str
 	return &result.Country[0].CountryID, nil
 }
 
-// Create создаёт новую запись о человеке с обогащением данных.
-func (s *PersonService) Create(ctx context.Context, input *models.PersonInput) (*models.Person, error) {
-	if err := input.Validate(); err != nil {
-		return nil, fmt.Errorf("валидация входных данных: %w", err)
-	}
-
-	person := &models.Person{
-		Name:       input.Name,
-		Surname:    input.Surname,
-		Patronymic: input.Patronymic,
-		CreatedAt:  time.Now(),
-	}
+// enrichment содержит данные, полученные из внешних API для одного имени.
+type enrichment struct {
+	age         *int
+	gender      *models.GenderType
+	nationality *string
+}
 
-	// Обогащение данных
-	age, err := s.fetchAge(ctx, input.Name)
+// fetchEnrichment запрашивает возраст, пол и национальность по имени во внешних API.
+func (s *PersonService) fetchEnrichment(ctx context.Context, name string) (*enrichment, error) {
+	age, err := observeProvider(ctx, "agify", func(ctx context.Context) (*int, error) { return s.fetchAge(ctx, name) })
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения возраста: %w", err)
 	}
-	person.Age = age
-
-	gender, err := s.fetchGender(ctx, input.Name)
+	gender, err := observeProvider(ctx, "genderize", func(ctx context.Context) (*models.GenderType, error) { return s.fetchGender(ctx, name) })
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения пола: %w", err)
 	}
-	person.Gender = gender
-
-	nationality, err := s.fetchNationality(ctx, input.Name)
+	nationality, err := observeProvider(ctx, "nationalize", func(ctx context.Context) (*string, error) { return s.fetchNationality(ctx, name) })
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения национальности: %w", err)
 	}
-	person.Nationality = nationality
+	return &enrichment{age: age, gender: gender, nationality: nationality}, nil
+}
+
+// observeProvider выполняет запрос к API обогащения provider в отдельном span
+// и записывает его длительность и ошибку в метрики.
+func observeProvider[T any](ctx context.Context, provider string, fetch func(ctx context.Context) (T, error)) (_ T, err error) {
+	ctx, span := tracing.Start(ctx, "enrichment "+provider, trace.WithAttributes(attribute.String("enrichment.provider", provider)))
+	defer tracing.End(span, &err)
+
+	start := time.Now()
+	value, err := fetch(ctx)
+	metrics.EnrichmentDuration.WithLabelValues(provider).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.EnrichmentErrors.WithLabelValues(provider).Inc()
+	}
+	return value, err
+}
+
+// apply копирует данные обогащения в запись.
+func (e *enrichment) apply(p *models.Person) {
+	p.Age = copyPtr(e.age)
+	p.Gender = copyPtr(e.gender)
+	p.Nationality = copyPtr(e.nationality)
+}
+
+// copyPtr возвращает указатель на копию значения, чтобы записи не разделяли общие данные.
+func copyPtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+// Create создаёт новую запись о человеке с обогащением данных.
+func (s *PersonService) Create(ctx context.Context, input *models.PersonInput) (_ *models.Person, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.Create")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermWrite); err != nil {
+		return nil, err
+	}
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("валидация входных данных: %w", err)
+	}
+
+	person := input.ToPerson(time.Now())
+
+	// Проверка дубликатов выполняется до обогащения, чтобы не тратить запросы к внешним API
+	if s.duplicates.RejectOnCreate {
+		if err := s.checkDuplicates(ctx, person); err != nil {
+			return nil, err
+		}
+	}
+
+	// Обогащение данных
+	data, err := s.enrich(ctx, input.Name)
+	if err != nil {
+		return nil, err
+	}
+	data.apply(person)
 
 	if err := person.Validate(); err != nil {
 		return nil, fmt.Errorf("валидация обогащённых данных: %w", err)
@@ -156,101 +257,230 @@ func (s *PersonService) Create(ctx context.Context, input *models.PersonInput) (
 		return nil, fmt.Errorf("не удалось создать запись: %w", err)
 	}
 
-	s.logger.Info("Запись создана", zap.Int("id", person.ID))
+	s.log(ctx).Info("Запись создана", zap.Int("id", person.ID))
 	return person, nil
 }
 
-// GetByID возвращает запись по ID.
-func (s *PersonService) GetByID(ctx context.Context, id int) (*models.Person, error) {
-	person, err := s.repo.GetByID(ctx, id)
+// GetByID возвращает запись по ID. Удалённые записи возвращаются, только если includeDeleted=true.
+// Заполняются только поля fields, nil означает все поля.
+func (s *PersonService) GetByID(ctx context.Context, id int, includeDeleted bool, fields []string) (_ *models.Person, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.GetByID")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermRead); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeDeleted(ctx, includeDeleted); err != nil {
+		return nil, err
+	}
+	person, err := s.repo.GetByID(ctx, id, includeDeleted, fields)
 	if err != nil {
 		return nil, fmt.Errorf("не удалось получить запись: %w", err)
 	}
-	s.logger.Info("Запись получена", zap.Int("id", id))
+	s.log(ctx).Info("Запись получена", zap.Int("id", id))
 	return person, nil
 }
 
-// Update обновляет запись.
-func (s *PersonService) Update(ctx context.Context, person *models.Person) error {
+// GetByIDs возвращает записи с указанными ID по ID записи; отсутствующие записи в результат не попадают.
+func (s *PersonService) GetByIDs(ctx context.Context, ids []int, includeDeleted bool) (_ map[int]*models.Person, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.GetByIDs")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermRead); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeDeleted(ctx, includeDeleted); err != nil {
+		return nil, err
+	}
+	persons, err := s.repo.GetByIDs(ctx, ids, includeDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить записи: %w", err)
+	}
+	byID := make(map[int]*models.Person, len(persons))
+	for _, person := range persons {
+		byID[person.ID] = person
+	}
+	return byID, nil
+}
+
+// Replace полностью заменяет запись и возвращает её новое состояние.
+// Если записи нет и createIfAbsent=true, она создаётся с указанным ID; created сообщает о создании.
+func (s *PersonService) Replace(ctx context.Context, id int, input *models.PersonReplace, createIfAbsent bool) (_ *models.Person, _ bool, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.Replace")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermWrite); err != nil {
+		return nil, false, err
+	}
+	if id <= 0 {
+		return nil, false, fmt.Errorf("id должен быть положительным")
+	}
+	person := input.ToPerson(id)
 	if err := person.Validate(); err != nil {
-		return fmt.Errorf("валидация данных: %w", err)
+		return nil, false, fmt.Errorf("валидация данных: %w", err)
 	}
-	if err := s.repo.Update(ctx, person); err != nil {
-		return fmt.Errorf("не удалось обновить запись: %w", err)
+	created, err := s.repo.Replace(ctx, person, createIfAbsent)
+	if err != nil {
+		return nil, false, fmt.Errorf("не удалось обновить запись: %w", err)
 	}
-	s.logger.Info("Запись обновлена", zap.Int("id", person.ID))
-	return nil
+	if created {
+		s.log(ctx).Info("Запись создана через PUT", zap.Int("id", id))
+	} else {
+		s.log(ctx).Info("Запись обновлена", zap.Int("id", id), zap.Int("version", person.Version))
+	}
+	return person, created, nil
 }
 
-// Patch частично обновляет запись.
-func (s *PersonService) Patch(ctx context.Context, id int, update *models.PersonUpdate) error {
+// Patch частично обновляет запись и возвращает её новое состояние.
+// Если переданные значения совпадают с текущими, запись не изменяется.
+func (s *PersonService) Patch(ctx context.Context, id int, update *models.PersonUpdate) (_ *models.Person, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.Patch")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermWrite); err != nil {
+		return nil, err
+	}
 	// Проверяем, указано ли хотя бы одно поле для обновления
 	if update.Name == nil && update.Surname == nil && update.Patronymic == nil &&
 		update.Age == nil && update.Gender == nil && update.Nationality == nil {
-		return fmt.Errorf("не указано ни одного поля для обновления")
+		return nil, fmt.Errorf("не указано ни одного поля для обновления")
 	}
 
 	// Валидация входных данных
 	if err := update.Validate(); err != nil {
-		return fmt.Errorf("валидация данных: %w", err)
+		return nil, fmt.Errorf("валидация данных: %w", err)
 	}
 
-	// Получаем существующую запись
-	existing, err := s.repo.GetByID(ctx, id)
+	person, err := s.repo.ApplyPatch(ctx, id, models.ActionPatch, func(p *models.Person) error {
+		update.Apply(p)
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("не удалось найти запись: %w", err)
+		return nil, fmt.Errorf("не удалось обновить запись: %w", err)
 	}
 
-	// Проверяем, есть ли изменения
-	noChanges := true
-	if update.Name != nil && *update.Name != existing.Name {
-		noChanges = false
-	}
-	if update.Surname != nil && (existing.Surname == nil || *update.Surname != *existing.Surname) {
-		noChanges = false
-	}
-	if update.Patronymic != nil && (existing.Patronymic == nil || *update.Patronymic != *existing.Patronymic) {
-		noChanges = false
-	}
-	if update.Age != nil && (existing.Age == nil || *update.Age != *existing.Age) {
-		noChanges = false
-	}
-	if update.Gender != nil && (existing.Gender == nil || *update.Gender != *existing.Gender) {
-		noChanges = false
-	}
-	if update.Nationality != nil && (existing.Nationality == nil || *update.Nationality != *existing.Nationality) {
-		noChanges = false
-	}
+	s.log(ctx).Info("Запись частично обновлена", zap.Int("id", id), zap.Int("version", person.Version))
+	return person, nil
+}
 
-	if noChanges {
-		s.logger.Info("Данные не изменены, так как они совпадают с текущими", zap.Int("id", id))
-		return fmt.Errorf("данные не изменены, так как они совпадают с текущими")
+// MergePatch применяет JSON Merge Patch (RFC 7396) к записи и возвращает результат.
+func (s *PersonService) MergePatch(ctx context.Context, id int, patch map[string]json.RawMessage) (_ *models.Person, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.MergePatch")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermWrite); err != nil {
+		return nil, err
+	}
+	person, err := s.repo.ApplyPatch(ctx, id, models.ActionPatch, func(p *models.Person) error {
+		if err := models.ApplyMergePatch(p, patch); err != nil {
+			return err
+		}
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("валидация данных: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("не удалось обновить запись: %w", err)
 	}
+	s.log(ctx).Info("Запись обновлена через merge patch", zap.Int("id", id))
+	return person, nil
+}
 
-	// Вызываем метод Patch в репозитории
-	if err := s.repo.Patch(ctx, id, update); err != nil {
-		return fmt.Errorf("не удалось обновить запись: %w", err)
+// JSONPatch применяет операции JSON Patch (RFC 6902) к записи и возвращает результат.
+func (s *PersonService) JSONPatch(ctx context.Context, id int, ops []models.PatchOperation) (_ *models.Person, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.JSONPatch")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermWrite); err != nil {
+		return nil, err
+	}
+	for i := range ops {
+		if err := ops[i].Validate(); err != nil {
+			return nil, fmt.Errorf("операция %d: %w", i, err)
+		}
+	}
+	person, err := s.repo.ApplyPatch(ctx, id, models.ActionPatch, func(p *models.Person) error {
+		if err := models.ApplyJSONPatch(p, ops); err != nil {
+			return err
+		}
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("валидация данных: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("не удалось обновить запись: %w", err)
 	}
-
-	s.logger.Info("Запись частично обновлена", zap.Int("id", id))
-	return nil
+	s.log(ctx).Info("Запись обновлена через json patch", zap.Int("id", id), zap.Int("operations", len(ops)))
+	return person, nil
 }
 
-// Delete удаляет запись.
-func (s *PersonService) Delete(ctx context.Context, id int) error {
+// Delete помечает запись удалённой. Её можно восстановить до очистки через PurgeDeleted.
+func (s *PersonService) Delete(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.Delete")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermDelete); err != nil {
+		return err
+	}
 	if err := s.repo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("не удалось удалить запись: %w", err)
 	}
-	s.logger.Info("Запись удалена", zap.Int("id", id))
+	s.log(ctx).Info("Запись удалена", zap.Int("id", id))
 	return nil
 }
 
+// Restore восстанавливает удалённую запись.
+func (s *PersonService) Restore(ctx context.Context, id int) (_ *models.Person, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.Restore")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermDelete); err != nil {
+		return nil, err
+	}
+	person, err := s.repo.Restore(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось восстановить запись: %w", err)
+	}
+	s.log(ctx).Info("Запись восстановлена", zap.Int("id", id))
+	return person, nil
+}
+
 // List возвращает список записей с пагинацией и фильтрами.
-func (s *PersonService) List(ctx context.Context, limit, offset int, filters map[string]string) ([]*models.Person, error) {
-	persons, err := s.repo.List(ctx, limit, offset, filters)
+// Заполняются только поля fields, nil означает все поля.
+func (s *PersonService) List(ctx context.Context, limit, offset int, filters map[string]string, fields []string) (_ []*models.Person, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.List")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermRead); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeDeleted(ctx, filters["include_deleted"] == "true"); err != nil {
+		return nil, err
+	}
+	persons, err := s.repo.List(ctx, limit, offset, filters, fields)
 	if err != nil {
 		return nil, fmt.Errorf("не удалось получить список: %w", err)
 	}
-	s.logger.Info("Список записей получен", zap.Int("count", len(persons)))
+	s.log(ctx).Info("Список записей получен", zap.Int("count", len(persons)))
 	return persons, nil
 }
+
+// Count возвращает число записей под фильтрами списка.
+func (s *PersonService) Count(ctx context.Context, filters map[string]string) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.Count")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermRead); err != nil {
+		return 0, err
+	}
+	if err := s.authorizeDeleted(ctx, filters["include_deleted"] == "true"); err != nil {
+		return 0, err
+	}
+	count, err := s.repo.Count(ctx, filters)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось подсчитать записи: %w", err)
+	}
+	return count, nil
+}
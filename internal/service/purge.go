@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"person-service/internal/auth"
+	"person-service/internal/tracing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PurgeDeleted физически удаляет записи, помеченные удалёнными дольше retention назад.
+func (s *PersonService) PurgeDeleted(ctx context.Context, retention time.Duration) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.PurgeDeleted")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermBulk); err != nil {
+		return 0, err
+	}
+	if retention <= 0 {
+		return 0, fmt.Errorf("срок хранения удалённых записей должен быть положительным")
+	}
+	purged, err := s.repo.PurgeDeleted(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("не удалось очистить удалённые записи: %w", err)
+	}
+	s.log(ctx).Info("Удалённые записи очищены", zap.Int64("count", purged), zap.Duration("retention", retention))
+	return purged, nil
+}
+
+// RunPurge периодически очищает удалённые записи до отмены ctx.
+// Ошибки очистки логируются, следующая попытка выполняется через interval.
+func (s *PersonService) RunPurge(ctx context.Context, interval, retention time.Duration) {
+	s.logger.Info("Запуск фоновой очистки удалённых записей",
+		zap.Duration("interval", interval), zap.Duration("retention", retention))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.PurgeDeleted(ctx, retention); err != nil {
+			s.logger.Error("Ошибка фоновой очистки удалённых записей", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Фоновая очистка удалённых записей остановлена")
+			return
+		case <-ticker.C:
+		}
+	}
+}
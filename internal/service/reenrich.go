@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"person-service/internal/auth"
+	"person-service/internal/models"
+	"person-service/internal/repository"
+	"person-service/internal/tracing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// reenrichPageSize — число записей, читаемых за один запрос при повторном обогащении.
+const reenrichPageSize = 100
+
+// Reenrich повторно обогащает до limit записей (0 — все), которые не обогащались с момента before.
+// Изменившиеся данные сохраняются с записью в историю как обогащение, а время проверки отмечается у записи
+// и без изменений, чтобы следующий запуск не запрашивал внешние API для неё повторно. Ошибка отдельной записи
+// не прерывает обработку остальных, а исчерпание квоты обогащения прерывает её с уже подсчитанным итогом.
+func (s *PersonService) Reenrich(ctx context.Context, before time.Time, limit int) (_ *models.ReenrichResult, err error) {
+	ctx, span := tracing.Start(ctx, "PersonService.Reenrich")
+	defer tracing.End(span, &err)
+
+	if err := s.authorize(ctx, auth.PermBulk); err != nil {
+		return nil, err
+	}
+
+	result := &models.ReenrichResult{}
+	afterID := 0
+	for limit <= 0 || result.Checked < limit {
+		pageSize := reenrichPageSize
+		if limit > 0 {
+			pageSize = min(pageSize, limit-result.Checked)
+		}
+		persons, err := s.repo.ListStaleEnrichment(ctx, before, afterID, pageSize)
+		if err != nil {
+			return result, err
+		}
+		if len(persons) == 0 {
+			break
+		}
+		for _, person := range persons {
+			afterID = person.ID
+			result.Checked++
+			updated, err := s.reenrichPerson(ctx, person)
+			if errors.Is(err, ErrEnrichmentQuotaExceeded) {
+				return result, err
+			}
+			if err != nil {
+				result.Failed++
+				s.log(ctx).Warn("Не удалось повторно обогатить запись", zap.Int("id", person.ID), zap.Error(err))
+				continue
+			}
+			if updated {
+				result.Updated++
+			}
+		}
+	}
+
+	s.log(ctx).Info("Повторное обогащение завершено",
+		zap.Int("checked", result.Checked), zap.Int("updated", result.Updated), zap.Int("failed", result.Failed))
+	return result, nil
+}
+
+// enrichedFields — поля записи, которые заполняет обогащение, в документе истории.
+var enrichedFields = []string{"age", "gender", "nationality"}
+
+// reenrichPerson запрашивает обогащение для имени записи и сохраняет изменившиеся данные.
+// Пустые ответы внешних API не стирают значения, а поля, которые последним изменило не обогащение,
+// например оператор через PATCH, не перезаписываются.
+func (s *PersonService) reenrichPerson(ctx context.Context, person *models.Person) (bool, error) {
+	checkedAt := time.Now()
+	data, err := s.enrich(ctx, person.Name)
+	if err != nil {
+		return false, err
+	}
+	manual, err := s.manualFields(ctx, person.ID)
+	if err != nil {
+		return false, err
+	}
+	saved, err := s.repo.ApplyPatch(ctx, person.ID, models.ActionEnrich, func(p *models.Person) error {
+		if data.age != nil && !manual["age"] {
+			p.Age = copyPtr(data.age)
+		}
+		if data.gender != nil && !manual["gender"] {
+			p.Gender = copyPtr(data.gender)
+		}
+		if data.nationality != nil && !manual["nationality"] {
+			p.Nationality = copyPtr(data.nationality)
+		}
+		return p.Validate()
+	})
+	if repository.IsNotFound(err) {
+		// Запись удалена после выборки
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("не удалось сохранить обогащение: %w", err)
+	}
+	if err := s.repo.MarkEnriched(ctx, person.ID, checkedAt); err != nil {
+		return false, err
+	}
+	return saved.Version != person.Version, nil
+}
+
+// manualFields возвращает поля обогащения, которые последним изменило не создание и не обогащение.
+// История просматривается от новых изменений к старым, пока не найдено последнее изменение каждого поля.
+func (s *PersonService) manualFields(ctx context.Context, personID int) (map[string]bool, error) {
+	manual := make(map[string]bool, len(enrichedFields))
+	for offset := 0; len(manual) < len(enrichedFields); offset += reenrichPageSize {
+		entries, err := s.repo.ListHistory(ctx, personID, reenrichPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось получить историю записи: %w", err)
+		}
+		for _, entry := range entries {
+			if len(entry.After) == 0 {
+				continue
+			}
+			var after map[string]json.RawMessage
+			if err := json.Unmarshal(entry.After, &after); err != nil {
+				return nil, fmt.Errorf("некорректная запись истории %d: %w", entry.ID, err)
+			}
+			for _, field := range enrichedFields {
+				if _, decided := manual[field]; decided {
+					continue
+				}
+				if _, changed := after[field]; changed {
+					manual[field] = entry.Action != models.ActionCreate && entry.Action != models.ActionEnrich
+				}
+			}
+		}
+		if len(entries) < reenrichPageSize {
+			break
+		}
+	}
+	return manual, nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"person-service/internal/config"
+	"person-service/internal/models"
+	"person-service/internal/repository"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// reenrichRepository хранит одну запись и её историю; методы, не нужные повторному обогащению, не реализованы.
+type reenrichRepository struct {
+	repository.PersonRepository
+	person     models.Person
+	history    []*models.HistoryEntry
+	enrichedAt time.Time
+}
+
+func (r *reenrichRepository) ListStaleEnrichment(_ context.Context, before time.Time, afterID, _ int) ([]*models.Person, error) {
+	if r.person.ID <= afterID || !r.enrichedAt.Before(before) {
+		return nil, nil
+	}
+	person := r.person
+	return []*models.Person{&person}, nil
+}
+
+func (r *reenrichRepository) ListHistory(_ context.Context, _ int, limit, offset int) ([]*models.HistoryEntry, error) {
+	// История хранится от старых изменений к новым, а возвращается от новых к старым
+	var entries []*models.HistoryEntry
+	for i := len(r.history) - 1 - offset; i >= 0 && len(entries) < limit; i-- {
+		entries = append(entries, r.history[i])
+	}
+	return entries, nil
+}
+
+func (r *reenrichRepository) ApplyPatch(_ context.Context, _ int, action models.HistoryAction, apply func(person *models.Person) error) (*models.Person, error) {
+	updated := r.person
+	if err := apply(&updated); err != nil {
+		return nil, err
+	}
+	before, after, err := models.DiffPersons(&r.person, &updated)
+	if err != nil {
+		return nil, err
+	}
+	if len(after) > 0 {
+		updated.Version++
+		r.record(action, before, after)
+		r.person = updated
+	}
+	person := r.person
+	return &person, nil
+}
+
+func (r *reenrichRepository) MarkEnriched(_ context.Context, _ int, at time.Time) error {
+	r.enrichedAt = at
+	return nil
+}
+
+// record добавляет в историю изменение полей.
+func (r *reenrichRepository) record(action models.HistoryAction, before, after map[string]json.RawMessage) {
+	beforeDoc, _ := json.Marshal(before)
+	afterDoc, _ := json.Marshal(after)
+	r.history = append(r.history, &models.HistoryEntry{
+		ID: int64(len(r.history) + 1), PersonID: r.person.ID, Action: action, Before: beforeDoc, After: afterDoc,
+	})
+}
+
+func TestReenrichKeepsManualAndMissingValues(t *testing.T) {
+	// Внешние API возвращают только возраст: пол и национальность не определены
+	enrichment := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"age": 50, "gender": null, "country": []}`)
+	}))
+	defer enrichment.Close()
+	apis := &config.APIs{Agify: enrichment.URL, Genderize: enrichment.URL, Nationalize: enrichment.URL}
+
+	gender, nationality := models.GenderType("male"), "RU"
+	repo := &reenrichRepository{person: models.Person{ID: 1, Name: "Иван", Age: ptr(25), Gender: &gender, Nationality: &nationality, Version: 1}}
+	_, created, err := models.DiffPersons(nil, &repo.person)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.record(models.ActionCreate, nil, created)
+	// Оператор исправил возраст вручную
+	if _, err := repo.ApplyPatch(context.Background(), 1, models.ActionPatch, func(p *models.Person) error {
+		p.Age = ptr(30)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewPersonService(repo, zap.NewNop(), apis, &config.Duplicates{}, &config.Enrichment{}, nil)
+	result, err := svc.Reenrich(context.Background(), time.Now(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Checked != 1 || result.Updated != 0 {
+		t.Fatalf("ожидалась одна проверенная запись без изменений, получено %+v", result)
+	}
+	if p := repo.person; *p.Age != 30 || p.Gender == nil || *p.Gender != gender || p.Nationality == nil || *p.Nationality != nationality {
+		t.Fatalf("повторное обогащение перезаписало данные: возраст %v, пол %v, национальность %v", *p.Age, p.Gender, p.Nationality)
+	}
+
+	// Проверка без изменений отмечена, и следующий запуск не обращается к внешним API
+	result, err = svc.Reenrich(context.Background(), time.Now().Add(-time.Minute), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Checked != 0 {
+		t.Fatalf("запись без изменений проверена повторно: %+v", result)
+	}
+
+	// Возраст, заданный обогащением, обновляется
+	repo.history = repo.history[:1]
+	result, err = svc.Reenrich(context.Background(), time.Now().Add(time.Minute), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Updated != 1 || *repo.person.Age != 50 {
+		t.Fatalf("возраст из обогащения не обновлён: %+v, возраст %d", result, *repo.person.Age)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
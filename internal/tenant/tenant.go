@@ -0,0 +1,69 @@
+// Package tenant хранит в контексте арендатора, к данным которого относится запрос.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// Default — арендатор запросов, для которых арендатор не указан, и записей, созданных до разделения данных.
+const Default = "default"
+
+// ErrInvalidID возвращается для идентификатора арендатора недопустимого вида.
+var ErrInvalidID = errors.New("некорректный идентификатор арендатора: ожидаются строчные латинские буквы, цифры, - и _, до 64 символов")
+
+// ErrMismatch возвращается, если запрошен арендатор, отличный от указанного в учётных данных.
+var ErrMismatch = errors.New("учётные данные не дают доступа к запрошенному арендатору")
+
+// idPattern задаёт допустимый вид идентификатора арендатора.
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+type tenantKey struct{}
+
+// WithID возвращает контекст с арендатором id.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext возвращает арендатора из контекста; ok=false, если он не задан.
+func FromContext(ctx context.Context) (id string, ok bool) {
+	id, ok = ctx.Value(tenantKey{}).(string)
+	return id, ok
+}
+
+// IDFromContext возвращает арендатора из контекста или Default, если он не задан,
+// например для фоновых задач и команд CLI.
+func IDFromContext(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok {
+		return id
+	}
+	return Default
+}
+
+// Resolve выбирает арендатора запроса. bound — арендатор из учётных данных, requested — из заголовка или метаданных.
+// Учётные данные, привязанные к арендатору, не дают доступа к другим; пустой bound означает, что вызывающему
+// разрешено выбирать арендатора, и тогда используется запрошенный, а если он не указан — Default.
+func Resolve(bound, requested string) (string, error) {
+	if bound != "" {
+		if requested != "" && requested != bound {
+			return "", ErrMismatch
+		}
+		return bound, nil
+	}
+	if requested == "" {
+		return Default, nil
+	}
+	if err := Validate(requested); err != nil {
+		return "", err
+	}
+	return requested, nil
+}
+
+// Validate проверяет вид идентификатора арендатора.
+func Validate(id string) error {
+	if !idPattern.MatchString(id) {
+		return ErrInvalidID
+	}
+	return nil
+}
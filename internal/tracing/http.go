@@ -0,0 +1,38 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader — заголовок ответа с идентификатором трассировки запроса.
+const TraceIDHeader = "X-Trace-ID"
+
+// HTTPMiddleware создаёт span для каждого запроса, продолжая трассировку из заголовка traceparent.
+// Span называется по методу и шаблону маршрута chi, например "GET /api/v1/persons/{id}",
+// а идентификатор трассировки возвращается клиенту в заголовке X-Trace-ID.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if traceID := TraceID(r.Context()); traceID != "" {
+			w.Header().Set(TraceIDHeader, traceID)
+		}
+		next.ServeHTTP(w, r)
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(r.Method + " " + rctx.RoutePattern())
+			span.SetAttributes(semconv.HTTPRoute(rctx.RoutePattern()))
+		}
+	})
+	return otelhttp.NewHandler(inner, "http",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string { return r.Method }))
+}
+
+// Transport оборачивает исходящие HTTP-запросы в spans и передаёт контекст трассировки в traceparent.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(base,
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string { return r.Method + " " + r.URL.Host }))
+}
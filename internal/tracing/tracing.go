@@ -0,0 +1,109 @@
+// Package tracing настраивает распределённую трассировку OpenTelemetry.
+//
+// Контекст трассировки передаётся между сервисами в заголовках W3C traceparent и tracestate.
+// Spans экспортируются по OTLP/gRPC, в stdout или в файл; без экспорта идентификаторы трассировки
+// всё равно создаются, чтобы связывать логи и ответы с ошибками.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"person-service/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serviceName — имя сервиса в трассировках.
+const serviceName = "person-service"
+
+// tracerName — имя инструментирующей библиотеки для spans сервиса.
+const tracerName = "person-service"
+
+// Setup настраивает глобальный TracerProvider и W3C-пропагатор по cfg.
+// Возвращённая функция отправляет накопленные spans и останавливает экспорт.
+func Setup(ctx context.Context, cfg *config.Tracing) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	res := resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	var closer io.Closer
+	switch cfg.Exporter {
+	case config.TracingNone:
+		opts = append(opts, sdktrace.WithSampler(sdktrace.NeverSample()))
+	case config.TracingOTLP:
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось создать OTLP-экспортёр: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter), sampler(cfg))
+	case config.TracingStdout, config.TracingFile:
+		var out io.Writer = os.Stdout
+		if cfg.Exporter == config.TracingFile {
+			file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return nil, fmt.Errorf("не удалось открыть файл трассировки: %w", err)
+			}
+			out, closer = file, file
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(out))
+		if err != nil {
+			return nil, fmt.Errorf("не удалось создать экспортёр трассировки: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter), sampler(cfg))
+	default:
+		return nil, fmt.Errorf("неизвестный экспортёр трассировки: %s", cfg.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		return err
+	}, nil
+}
+
+// sampler сохраняет решение вызывающего сервиса, а для новых трассировок сохраняет долю SampleRatio.
+func sampler(cfg *config.Tracing) sdktrace.TracerProviderOption {
+	return sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio)))
+}
+
+// Start начинает span name дочерним к span из ctx.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// End завершает span, отмечая его ошибкой, если *err не nil.
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
+
+// TraceID возвращает идентификатор трассировки из ctx или пустую строку.
+func TraceID(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE persons ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_persons_deleted_at ON persons (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_persons_deleted_at;
+ALTER TABLE persons DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
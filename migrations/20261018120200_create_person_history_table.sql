@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS person_history (
+    id BIGSERIAL PRIMARY KEY,
+    person_id INT NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    actor VARCHAR(255),
+    request_id VARCHAR(255),
+    source VARCHAR(64) NOT NULL,
+    before JSONB,
+    after JSONB,
+    version INT NOT NULL,
+    -- Момент времени с часовым поясом, чтобы as_of с любым смещением сравнивался верно
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_person_history_person_id ON person_history (person_id, changed_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS person_history;
+-- +goose StatementEnd
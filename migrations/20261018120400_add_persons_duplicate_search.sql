@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE persons ADD COLUMN full_name_normalized TEXT GENERATED ALWAYS AS (
+    lower(translate(btrim(name) || ' ' || coalesce(btrim(surname), '') || ' ' || coalesce(btrim(patronymic), ''), 'Ёё', 'Ее'))
+) STORED;
+
+CREATE INDEX idx_persons_full_name_trgm ON persons USING GIN (full_name_normalized gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_persons_full_name_trgm;
+ALTER TABLE persons DROP COLUMN IF EXISTS full_name_normalized;
+-- +goose StatementEnd
@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE persons ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+CREATE INDEX idx_persons_tenant_id ON persons (tenant_id, id);
+
+ALTER TABLE person_history ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+DROP INDEX IF EXISTS idx_person_history_person_id;
+CREATE INDEX idx_person_history_person_id ON person_history (tenant_id, person_id, changed_at);
+
+ALTER TABLE idempotency_keys ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (tenant_id, key);
+
+-- Арендатор, к которому привязан ключ; NULL позволяет выбирать арендатора заголовком X-Tenant-ID
+ALTER TABLE api_keys ADD COLUMN tenant_id VARCHAR(64);
+
+-- Политики действуют, только если приложение задало app.tenant_id (database.row_level_security=true);
+-- без него, например для миграций и обслуживания, строки всех арендаторов доступны.
+ALTER TABLE persons ENABLE ROW LEVEL SECURITY;
+ALTER TABLE persons FORCE ROW LEVEL SECURITY;
+CREATE POLICY persons_tenant_isolation ON persons
+    USING (coalesce(current_setting('app.tenant_id', true), '') = '' OR tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (coalesce(current_setting('app.tenant_id', true), '') = '' OR tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE person_history ENABLE ROW LEVEL SECURITY;
+ALTER TABLE person_history FORCE ROW LEVEL SECURITY;
+CREATE POLICY person_history_tenant_isolation ON person_history
+    USING (coalesce(current_setting('app.tenant_id', true), '') = '' OR tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (coalesce(current_setting('app.tenant_id', true), '') = '' OR tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE idempotency_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE idempotency_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY idempotency_keys_tenant_isolation ON idempotency_keys
+    USING (coalesce(current_setting('app.tenant_id', true), '') = '' OR tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (coalesce(current_setting('app.tenant_id', true), '') = '' OR tenant_id = current_setting('app.tenant_id', true));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY IF EXISTS idempotency_keys_tenant_isolation ON idempotency_keys;
+ALTER TABLE idempotency_keys NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE idempotency_keys DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS person_history_tenant_isolation ON person_history;
+ALTER TABLE person_history NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE person_history DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS persons_tenant_isolation ON persons;
+ALTER TABLE persons NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE persons DISABLE ROW LEVEL SECURITY;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+
+DELETE FROM idempotency_keys WHERE tenant_id <> 'default';
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (key);
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_person_history_person_id;
+CREATE INDEX idx_person_history_person_id ON person_history (person_id, changed_at);
+ALTER TABLE person_history DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_persons_tenant_id;
+ALTER TABLE persons DROP COLUMN IF EXISTS tenant_id;
+-- +goose StatementEnd
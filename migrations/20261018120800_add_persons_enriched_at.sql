@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Время последней проверки обогащения, в том числе не изменившей данные и потому не попавшей в историю
+ALTER TABLE persons ADD COLUMN enriched_at TIMESTAMPTZ;
+
+UPDATE persons p
+SET enriched_at = h.enriched_at
+FROM (
+    SELECT person_id, MAX(changed_at) AS enriched_at
+    FROM person_history
+    WHERE action = 'enrich'
+    GROUP BY person_id
+) h
+WHERE h.person_id = p.id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE persons DROP COLUMN IF EXISTS enriched_at;
+-- +goose StatementEnd
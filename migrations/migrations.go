@@ -0,0 +1,27 @@
+// Package migrations встраивает SQL-миграции схемы goose в бинарный файл сервиса.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// FS содержит файлы миграций вида <версия>_<описание>.sql.
+//
+//go:embed *.sql
+var FS embed.FS
+
+// Latest возвращает версию последней встроенной миграции — версию схемы, на которую рассчитан код.
+func Latest() int64 {
+	names, _ := fs.Glob(FS, "*.sql")
+	var latest int64
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(name, "_")
+		if version, err := strconv.ParseInt(prefix, 10, 64); err == nil && version > latest {
+			latest = version
+		}
+	}
+	return latest
+}
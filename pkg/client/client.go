@@ -0,0 +1,310 @@
+// Package client реализует типизированный Go-клиент REST API сервиса записей о людях.
+//
+// Клиент повторяет запросы при сетевых ошибках и ответах 429, 502, 503 и 504.
+// Повторяются только безопасные запросы: GET, PUT, DELETE и запросы с Idempotency-Key,
+// который клиент сам добавляет к созданию, слиянию и массовым операциям.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	mathrand "math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// userAgent передаётся в заголовке User-Agent всех запросов.
+const userAgent = "person-service-client/1.0"
+
+// RetryPolicy задаёт повторы запросов.
+type RetryPolicy struct {
+	// MaxAttempts — максимальное число попыток, включая первую; 1 отключает повторы.
+	MaxAttempts int
+	// MinBackoff — пауза перед первым повтором; каждая следующая удваивается.
+	MinBackoff time.Duration
+	// MaxBackoff ограничивает паузу между попытками.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy используется, если политика не задана через WithRetry.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  100 * time.Millisecond,
+	MaxBackoff:  2 * time.Second,
+}
+
+// Client выполняет запросы к API сервиса.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	retry      RetryPolicy
+	headers    http.Header
+}
+
+// Option настраивает клиент.
+type Option func(*Client)
+
+// WithHTTPClient задаёт HTTP-клиент, например с таймаутом или собственным транспортом.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetry задаёт политику повторов.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithHeader добавляет заголовок ко всем запросам.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithBearerToken передаёт JWT в заголовке Authorization всех запросов.
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.headers.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// WithAPIKey передаёт ключ API в заголовке X-API-Key всех запросов.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.headers.Set("X-API-Key", key)
+	}
+}
+
+// WithTenant передаёт арендатора в заголовке X-Tenant-ID всех запросов.
+// Для учётных данных, привязанных к арендатору, заголовок можно не передавать.
+func WithTenant(id string) Option {
+	return func(c *Client) {
+		c.headers.Set("X-Tenant-ID", id)
+	}
+}
+
+// New создаёт клиент для сервиса с адресом baseURL, например http://localhost:8081.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("некорректный адрес сервиса: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("некорректный адрес сервиса: %s", baseURL)
+	}
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+		headers:    http.Header{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c, nil
+}
+
+// RequestOption настраивает отдельный запрос.
+type RequestOption func(*request)
+
+// WithIdempotencyKey задаёт Idempotency-Key запроса вместо сгенерированного клиентом.
+// Повтор запроса с тем же ключом возвращает сохранённый сервером ответ.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(r *request) {
+		r.header.Set("Idempotency-Key", key)
+	}
+}
+
+// request описывает запрос до отправки; тело хранится целиком, чтобы его можно было отправить повторно.
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	body        []byte
+	idempotent  bool
+	contentType string
+}
+
+// newRequest создаёт запрос; idempotent означает, что запрос можно безопасно повторить.
+func newRequest(method, path string, query url.Values) *request {
+	return &request{
+		method:     method,
+		path:       path,
+		query:      query,
+		header:     http.Header{},
+		idempotent: method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete,
+	}
+}
+
+// setJSON кодирует тело запроса в JSON.
+func (r *request) setJSON(v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("не удалось закодировать запрос: %w", err)
+	}
+	r.body = body
+	r.contentType = "application/json"
+	return nil
+}
+
+// withIdempotencyKey добавляет сгенерированный Idempotency-Key, если он не задан опциями, и разрешает повторы.
+func (r *request) withIdempotencyKey(opts []RequestOption) {
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.header.Get("Idempotency-Key") == "" {
+		r.header.Set("Idempotency-Key", newIdempotencyKey())
+	}
+	r.idempotent = true
+}
+
+// newIdempotencyKey возвращает случайный ключ идемпотентности.
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// do выполняет запрос с повторами и возвращает ответ со статусом 2xx; тело ответа закрывает вызывающий.
+// Ответы с другими статусами преобразуются в *APIError.
+func (c *Client) do(ctx context.Context, req *request) (*http.Response, error) {
+	attempts := 1
+	if req.idempotent {
+		attempts = c.retry.MaxAttempts
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, c.backoff(attempt, lastErr)); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := c.send(ctx, req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
+
+		lastErr = readAPIError(resp)
+		if !retryableStatus(resp.StatusCode) {
+			return nil, lastErr
+		}
+	}
+	return nil, lastErr
+}
+
+// send отправляет запрос один раз.
+func (c *Client) send(ctx context.Context, req *request) (*http.Response, error) {
+	u := *c.baseURL
+	u.Path += req.path
+	u.RawQuery = req.query.Encode()
+
+	var body io.Reader
+	if req.body != nil {
+		body = bytes.NewReader(req.body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось создать запрос: %w", err)
+	}
+	for key, values := range c.headers {
+		httpReq.Header[key] = values
+	}
+	for key, values := range req.header {
+		httpReq.Header[key] = values
+	}
+	httpReq.Header.Set("User-Agent", userAgent)
+	if httpReq.Header.Get("Accept") == "" {
+		httpReq.Header.Set("Accept", "application/json")
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", req.method, req.path, err)
+	}
+	return resp, nil
+}
+
+// backoff возвращает паузу перед попыткой attempt: Retry-After из ответа 429 или 503,
+// иначе экспоненциальную паузу со случайным разбросом.
+func (c *Client) backoff(attempt int, lastErr error) time.Duration {
+	var apiErr *APIError
+	if errors.As(lastErr, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+	delay := float64(c.retry.MinBackoff) * math.Pow(2, float64(attempt-1))
+	if c.retry.MaxBackoff > 0 && delay > float64(c.retry.MaxBackoff) {
+		delay = float64(c.retry.MaxBackoff)
+	}
+	// Разброс от половины до полной паузы, чтобы клиенты не повторяли запросы одновременно
+	return time.Duration(delay/2 + mathrand.Float64()*delay/2)
+}
+
+// retryableStatus сообщает, имеет ли смысл повторить запрос с таким статусом ответа.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// sleep ждёт d или отмены ctx.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// doJSON выполняет запрос и декодирует JSON-ответ в out; out может быть nil.
+func (c *Client) doJSON(ctx context.Context, req *request, out any) (*http.Response, error) {
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return resp, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("%s %s: не удалось декодировать ответ: %w", req.method, req.path, err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,314 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"person-service/internal/config"
+	"person-service/internal/models"
+	"slices"
+	"testing"
+	"time"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestTypedErrors(t *testing.T) {
+	ts := newTestServer(t, func(cfg *config.Config) {
+		cfg.Duplicates = config.Duplicates{RejectOnCreate: true, Threshold: 0.8}
+	})
+	c := newTestClient(t, ts)
+	ctx := context.Background()
+
+	t.Run("404", func(t *testing.T) {
+		_, err := c.Get(ctx, 404, nil)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			t.Fatalf("ожидалась APIError со статусом 404, получено %v", err)
+		}
+		if !errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) {
+			t.Fatalf("ошибка 404 неверно сопоставлена с категориями: %v", err)
+		}
+	})
+
+	t.Run("409", func(t *testing.T) {
+		input := PersonInput{Name: "Иван", Surname: ptr("Иванов")}
+		created, err := c.Create(ctx, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = c.Create(ctx, input)
+		if !errors.Is(err, ErrDuplicate) || !errors.Is(err, ErrConflict) {
+			t.Fatalf("ожидалась ErrDuplicate, получено %v", err)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !slices.Equal(apiErr.Duplicates, []int{created.ID}) {
+			t.Fatalf("ожидались дубликаты [%d], получено %+v", created.ID, apiErr)
+		}
+	})
+
+	t.Run("422", func(t *testing.T) {
+		key := WithIdempotencyKey("typed-errors-422")
+		if _, err := c.Create(ctx, PersonInput{Name: "Пётр"}, key); err != nil {
+			t.Fatal(err)
+		}
+		_, err := c.Create(ctx, PersonInput{Name: "Павел"}, key)
+		if !errors.Is(err, ErrUnprocessable) {
+			t.Fatalf("ожидалась ErrUnprocessable при повторе ключа с другим телом, получено %v", err)
+		}
+	})
+}
+
+func TestRateLimitedRetryAfter(t *testing.T) {
+	ts := newTestServer(t, func(cfg *config.Config) {
+		cfg.RateLimit.Read = config.RateLimitRule{Requests: 1, Period: time.Hour, Burst: 1}
+	})
+	ctx := context.Background()
+
+	c := newTestClient(t, ts, WithRetry(RetryPolicy{MaxAttempts: 1}))
+	if _, err := c.Get(ctx, 1, nil); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("первый запрос должен пройти лимит, получено %v", err)
+	}
+	_, err := c.Get(ctx, 1, nil)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("ожидалась ErrRateLimited, получено %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter < time.Minute {
+		t.Fatalf("ожидалась пауза Retry-After около часа, получено %+v", apiErr)
+	}
+	ts.received()
+
+	// С повторами клиент ждёт Retry-After, а не повторяет запрос сразу
+	c = newTestClient(t, ts)
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, err := c.Get(ctx, 1, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ожидалось ожидание Retry-After до отмены контекста, получено %v", err)
+	}
+	if got := len(ts.received()); got != 1 {
+		t.Fatalf("до истечения Retry-After отправлено %d запросов, ожидался 1", got)
+	}
+}
+
+func TestBatchEnrichmentRateLimit(t *testing.T) {
+	ts := newTestServer(t, func(cfg *config.Config) {
+		cfg.RateLimit.Create = config.RateLimitRule{Requests: 1, Period: time.Hour, Burst: 2}
+		cfg.Enrichment = config.Enrichment{CacheTTL: time.Hour, CacheSize: 10, MaxTenants: 10}
+	})
+	c := newTestClient(t, ts, WithRetry(RetryPolicy{MaxAttempts: 1}))
+	ctx := context.Background()
+	batch := func(names ...string) []PersonInput {
+		inputs := make([]PersonInput, len(names))
+		for i, name := range names {
+			inputs[i] = PersonInput{Name: name}
+		}
+		return inputs
+	}
+
+	// Токенов create хватает на два имени из трёх; имена обогащаются по алфавиту
+	result, err := c.CreateBatch(ctx, batch("Анна", "Борис", "Вера", "Анна"), BatchPartial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := make([]string, len(result.Items))
+	for i, item := range result.Items {
+		statuses[i] = item.Status
+	}
+	if want := []string{"created", "created", "failed", "created"}; !slices.Equal(statuses, want) {
+		t.Fatalf("ожидались статусы %v, получены %v", want, statuses)
+	}
+
+	// Имена из кэша обогащения не расходуют токены
+	result, err = c.CreateBatch(ctx, batch("Анна", "Вера"), BatchPartial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created != 1 || result.Items[1].Status != "failed" {
+		t.Fatalf("ожидалась одна запись из кэша и отказ для нового имени, получено %+v", result)
+	}
+
+	// Пакет отклоняется целиком, если токенов не хватило ни на одно имя или режим all_or_nothing
+	tests := []struct {
+		mode  BatchMode
+		names []string
+	}{
+		{BatchPartial, []string{"Вера"}},
+		{BatchAllOrNothing, []string{"Анна", "Вера"}},
+	}
+	for _, tt := range tests {
+		before := ts.repo.count()
+		_, err := c.CreateBatch(ctx, batch(tt.names...), tt.mode)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.RetryAfter < time.Minute {
+			t.Fatalf("%s %v: ожидалась 429 с Retry-After, получено %v", tt.mode, tt.names, err)
+		}
+		if ts.repo.count() != before {
+			t.Fatalf("%s %v: отклонённый пакет создал записи", tt.mode, tt.names)
+		}
+	}
+}
+
+func TestRetryIdempotentRequests(t *testing.T) {
+	ts := newTestServer(t)
+	c := newTestClient(t, ts)
+	ctx := context.Background()
+
+	t.Run("GET", func(t *testing.T) {
+		person := ts.repo.add(models.Person{Name: "Анна"})
+		ts.received()
+		ts.failNext(2)
+		got, err := c.Get(ctx, person.ID, nil)
+		if err != nil {
+			t.Fatalf("GET не повторён после 503: %v", err)
+		}
+		if got.ID != person.ID {
+			t.Fatalf("получена запись %d вместо %d", got.ID, person.ID)
+		}
+		if attempts := len(ts.received()); attempts != 3 {
+			t.Fatalf("ожидалось 3 попытки, выполнено %d", attempts)
+		}
+	})
+
+	t.Run("GET без повторов после исчерпания попыток", func(t *testing.T) {
+		ts.received()
+		ts.failNext(3)
+		_, err := c.Get(ctx, 1, nil)
+		if !errors.Is(err, ErrServer) {
+			t.Fatalf("ожидалась ErrServer, получено %v", err)
+		}
+		if attempts := len(ts.received()); attempts != 3 {
+			t.Fatalf("ожидалось 3 попытки, выполнено %d", attempts)
+		}
+	})
+
+	t.Run("POST с Idempotency-Key", func(t *testing.T) {
+		before := ts.repo.count()
+		ts.received()
+		ts.failNext(1)
+		created, err := c.Create(ctx, PersonInput{Name: "Ольга"})
+		if err != nil {
+			t.Fatalf("создание с Idempotency-Key не повторено после 503: %v", err)
+		}
+		requests := ts.received()
+		if len(requests) != 2 {
+			t.Fatalf("ожидалось 2 попытки, выполнено %d", len(requests))
+		}
+		key := requests[0].Header.Get("Idempotency-Key")
+		if key == "" || requests[1].Header.Get("Idempotency-Key") != key {
+			t.Fatalf("повтор отправлен с другим Idempotency-Key: %q и %q", key, requests[1].Header.Get("Idempotency-Key"))
+		}
+		// Первая попытка выполнена сервером, повтор получил сохранённый ответ
+		if got := ts.repo.count() - before; got != 1 {
+			t.Fatalf("повтор создал %d записей вместо одной", got)
+		}
+		if _, err := ts.repo.GetByID(ctx, created.ID, false, nil); err != nil {
+			t.Fatalf("возвращена не созданная запись %d: %v", created.ID, err)
+		}
+	})
+
+	t.Run("POST без Idempotency-Key", func(t *testing.T) {
+		deletedAt := time.Now()
+		person := ts.repo.add(models.Person{Name: "Борис", DeletedAt: &deletedAt})
+		ts.received()
+		ts.failNext(1)
+		_, err := c.Restore(ctx, person.ID)
+		if !errors.Is(err, ErrServer) {
+			t.Fatalf("ожидалась ErrServer без повтора, получено %v", err)
+		}
+		requests := ts.received()
+		if len(requests) != 1 {
+			t.Fatalf("запрос без Idempotency-Key повторён: %d попыток", len(requests))
+		}
+		if requests[0].Header.Get("Idempotency-Key") != "" {
+			t.Fatal("клиент добавил Idempotency-Key к восстановлению")
+		}
+	})
+
+	t.Run("PATCH", func(t *testing.T) {
+		person := ts.repo.add(models.Person{Name: "Вера"})
+		ts.received()
+		ts.failNext(1)
+		_, err := c.Patch(ctx, person.ID, PersonUpdate{Age: ptr(40)})
+		if !errors.Is(err, ErrServer) {
+			t.Fatalf("ожидалась ErrServer без повтора, получено %v", err)
+		}
+		if attempts := len(ts.received()); attempts != 1 {
+			t.Fatalf("PATCH повторён: %d попыток", attempts)
+		}
+	})
+}
+
+func TestListAll(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+	var want []int
+	for i := range 5 {
+		person := ts.repo.add(models.Person{Name: "Сергей", Surname: ptr("Смирнов")})
+		want = append(want, person.ID)
+		if i%2 == 0 {
+			ts.repo.add(models.Person{Name: "Сергей", Surname: ptr("Кузнецов")})
+		}
+	}
+
+	t.Run("все страницы", func(t *testing.T) {
+		c := newTestClient(t, ts)
+		ts.received()
+		var got []int
+		for person, err := range c.ListAll(ctx, ListOptions{Filter: Filter{Surname: "Смирнов"}, Limit: 2}) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, person.ID)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("ожидались записи %v, получены %v", want, got)
+		}
+		// Страницы по 2 записи: 2, 2 и неполная 1
+		requests := ts.received()
+		if len(requests) != 3 {
+			t.Fatalf("ожидалось 3 запроса страниц, выполнено %d", len(requests))
+		}
+		for i, r := range requests {
+			if offset := r.URL.Query().Get("offset"); i > 0 && offset != []string{"", "2", "4"}[i] {
+				t.Fatalf("страница %d запрошена со смещением %q", i+1, offset)
+			}
+		}
+	})
+
+	t.Run("прерывание перебора", func(t *testing.T) {
+		c := newTestClient(t, ts)
+		ts.received()
+		count := 0
+		for _, err := range c.ListAll(ctx, ListOptions{Filter: Filter{Surname: "Смирнов"}, Limit: 2}) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			count++
+			if count == 3 {
+				break
+			}
+		}
+		if requests := len(ts.received()); requests != 2 {
+			t.Fatalf("после прерывания на третьей записи выполнено %d запросов вместо 2", requests)
+		}
+	})
+
+	t.Run("ошибка страницы", func(t *testing.T) {
+		c := newTestClient(t, ts, WithRetry(RetryPolicy{MaxAttempts: 1}))
+		ts.received()
+		ts.failNext(1)
+		var errs []error
+		for person, err := range c.ListAll(ctx, ListOptions{Limit: 2}) {
+			if person != nil {
+				t.Fatalf("получена запись %d при ошибке страницы", person.ID)
+			}
+			errs = append(errs, err)
+		}
+		if len(errs) != 1 || !errors.Is(errs[0], ErrServer) {
+			t.Fatalf("ожидалась одна ErrServer, получено %v", errs)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBody ограничивает размер тела ответа с ошибкой, читаемого клиентом.
+const maxErrorBody = 64 << 10
+
+// Ошибки, с которыми можно сравнивать *APIError через errors.Is.
+var (
+	ErrBadRequest       = &sentinel{"некорректный запрос"}
+	ErrUnauthorized     = &sentinel{"требуется аутентификация"}
+	ErrForbidden        = &sentinel{"недостаточно прав"}
+	ErrNotFound         = &sentinel{"запись не найдена"}
+	ErrNotAcceptable    = &sentinel{"неподдерживаемый формат ответа"}
+	ErrConflict         = &sentinel{"конфликт"}
+	ErrDuplicate        = &sentinel{"найдены дубликаты"}
+	ErrTooLarge         = &sentinel{"запрос слишком большой"}
+	ErrUnsupportedMedia = &sentinel{"неподдерживаемый тип содержимого"}
+	ErrUnprocessable    = &sentinel{"запрос не может быть выполнен"}
+	ErrRateLimited      = &sentinel{"превышен лимит запросов"}
+	ErrServer           = &sentinel{"ошибка сервера"}
+)
+
+// sentinel — ошибка-категория для errors.Is.
+type sentinel struct {
+	msg string
+}
+
+func (s *sentinel) Error() string {
+	return s.msg
+}
+
+// APIError описывает ответ сервиса со статусом не 2xx.
+// Сервис возвращает ошибки в виде {"error": "..."}; при найденных дубликатах добавляется поле duplicates.
+type APIError struct {
+	// StatusCode — HTTP-статус ответа.
+	StatusCode int
+	// Message — текст ошибки из поля error или тело ответа, если оно не JSON.
+	Message string
+	// Duplicates содержит ID записей-дубликатов при отказе в создании записи.
+	Duplicates []int
+	// RetryAfter — пауза из заголовка Retry-After, если сервер её указал.
+	RetryAfter time.Duration
+	// Body — исходное тело ответа.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("person-service: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Is сопоставляет ошибку с категориями ErrNotFound, ErrConflict и другими по статусу ответа.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrNotAcceptable:
+		return e.StatusCode == http.StatusNotAcceptable
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrDuplicate:
+		return e.StatusCode == http.StatusConflict && len(e.Duplicates) > 0
+	case ErrTooLarge:
+		return e.StatusCode == http.StatusRequestEntityTooLarge
+	case ErrUnsupportedMedia:
+		return e.StatusCode == http.StatusUnsupportedMediaType
+	case ErrUnprocessable:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// readAPIError читает ответ с ошибкой и закрывает его тело.
+func readAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	var payload struct {
+		Error      string `json:"error"`
+		Duplicates []int  `json:"duplicates"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error != "" {
+		apiErr.Message = payload.Error
+		apiErr.Duplicates = payload.Duplicates
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+// parseRetryAfter разбирает Retry-After в секундах или в формате HTTP-даты.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
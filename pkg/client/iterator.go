@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"iter"
+)
+
+// defaultPageSize — размер страницы итератора, если ListOptions.Limit не задан.
+const defaultPageSize = 100
+
+// ListAll перебирает все записи под фильтром, запрашивая страницы по opts.Limit записей начиная с opts.Offset.
+// Перебор останавливается на первой ошибке, которая передаётся вторым значением:
+//
+//	for person, err := range c.ListAll(ctx, client.ListOptions{Filter: client.Filter{Surname: "Иванов"}}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// Страницы запрашиваются по смещению, поэтому записи, созданные или удалённые во время перебора,
+// могут быть пропущены или получены дважды.
+func (c *Client) ListAll(ctx context.Context, opts ListOptions) iter.Seq2[*Person, error] {
+	if opts.Limit <= 0 {
+		opts.Limit = defaultPageSize
+	}
+	return func(yield func(*Person, error) bool) {
+		for {
+			page, err := c.List(ctx, opts)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, person := range page {
+				if !yield(person, nil) {
+					return
+				}
+			}
+			if len(page) < opts.Limit {
+				return
+			}
+			opts.Offset += len(page)
+		}
+	}
+}
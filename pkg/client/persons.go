@@ -0,0 +1,328 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// personsPath — путь к коллекции записей.
+const personsPath = "/api/v1/persons"
+
+// personPath возвращает путь к записи с указанным ID.
+func personPath(id int) string {
+	return personsPath + "/" + strconv.Itoa(id)
+}
+
+// Create создаёт запись с обогащением возраста, пола и национальности.
+// Если сервис отклонил запись как дубликат, ошибка соответствует ErrDuplicate, а APIError.Duplicates содержит ID похожих записей.
+func (c *Client) Create(ctx context.Context, input PersonInput, opts ...RequestOption) (*Person, error) {
+	req := newRequest(http.MethodPost, personsPath, nil)
+	if err := req.setJSON(input); err != nil {
+		return nil, err
+	}
+	req.withIdempotencyKey(opts)
+
+	var person Person
+	if _, err := c.doJSON(ctx, req, &person); err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// CreateBatch создаёт записи пакетом.
+// Если ни одна запись не создана, вместе с ошибкой ErrUnprocessable возвращаются результаты по элементам.
+func (c *Client) CreateBatch(ctx context.Context, inputs []PersonInput, mode BatchMode, opts ...RequestOption) (*BatchResult, error) {
+	query := url.Values{}
+	if mode != "" {
+		query.Set("mode", string(mode))
+	}
+	req := newRequest(http.MethodPost, personsPath+":batch", query)
+	if err := req.setJSON(inputs); err != nil {
+		return nil, err
+	}
+	req.withIdempotencyKey(opts)
+
+	var result BatchResult
+	if _, err := c.doJSON(ctx, req, &result); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity &&
+			json.Unmarshal(apiErr.Body, &result) == nil && result.Items != nil {
+			return &result, err
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Import импортирует записи из CSV или XLSX и возвращает отчёт по строкам.
+// Импорт не повторяется автоматически, так как сервис не хранит его результат.
+func (c *Client) Import(ctx context.Context, file io.Reader, opts ImportOptions) (*ImportReport, error) {
+	body, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+	query := url.Values{}
+	query.Set("report", "json")
+	if opts.Format != "" {
+		query.Set("format", opts.Format)
+	}
+	if opts.Encoding != "" {
+		query.Set("encoding", opts.Encoding)
+	}
+	if opts.Mapping != "" {
+		query.Set("mapping", opts.Mapping)
+	}
+	if opts.Enrich {
+		query.Set("enrich", "true")
+	}
+
+	req := newRequest(http.MethodPost, personsPath+":import", query)
+	req.body = body
+	req.contentType = "text/csv"
+	if opts.Format == "xlsx" {
+		req.contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	var report ImportReport
+	if _, err := c.doJSON(ctx, req, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Export открывает поток выгрузки записей в выбранном формате; поток закрывает вызывающий.
+func (c *Client) Export(ctx context.Context, opts ExportOptions) (io.ReadCloser, error) {
+	query := url.Values{}
+	opts.Filter.apply(query)
+	if opts.Format != "" {
+		query.Set("format", opts.Format)
+	}
+	if opts.IncludeDeleted {
+		query.Set("include_deleted", "true")
+	}
+	req := newRequest(http.MethodGet, personsPath+"/export", query)
+	req.header.Set("Accept", "*/*")
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// List возвращает страницу записей под фильтром.
+func (c *Client) List(ctx context.Context, opts ListOptions) ([]*Person, error) {
+	query := url.Values{}
+	opts.Filter.apply(query)
+	opts.ReadOptions.apply(query)
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query.Set("offset", strconv.Itoa(opts.Offset))
+	}
+
+	// Пустой список сервис возвращает объектом с сообщением, а не массивом
+	var raw json.RawMessage
+	req := newRequest(http.MethodGet, personsPath, query)
+	if _, err := c.doJSON(ctx, req, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 || raw[0] != '[' {
+		return []*Person{}, nil
+	}
+	var persons []*Person
+	if err := json.Unmarshal(raw, &persons); err != nil {
+		return nil, fmt.Errorf("GET %s: не удалось декодировать ответ: %w", personsPath, err)
+	}
+	return persons, nil
+}
+
+// Get возвращает запись по ID; opts может быть nil.
+func (c *Client) Get(ctx context.Context, id int, opts *GetOptions) (*Person, error) {
+	query := url.Values{}
+	if opts != nil {
+		opts.ReadOptions.apply(query)
+		if !opts.AsOf.IsZero() {
+			query.Set("as_of", opts.AsOf.Format(time.RFC3339))
+		}
+	}
+	var person Person
+	if _, err := c.doJSON(ctx, newRequest(http.MethodGet, personPath(id), query), &person); err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// Replace полностью заменяет запись; created сообщает, что сервис создал отсутствовавшую запись.
+func (c *Client) Replace(ctx context.Context, id int, input PersonReplace) (person *Person, created bool, err error) {
+	req := newRequest(http.MethodPut, personPath(id), nil)
+	if err := req.setJSON(input); err != nil {
+		return nil, false, err
+	}
+	person = &Person{}
+	resp, err := c.doJSON(ctx, req, person)
+	if err != nil {
+		return nil, false, err
+	}
+	return person, resp.StatusCode == http.StatusCreated, nil
+}
+
+// Patch изменяет заданные поля записи.
+func (c *Client) Patch(ctx context.Context, id int, update PersonUpdate) (*Person, error) {
+	req := newRequest(http.MethodPatch, personPath(id), nil)
+	if err := req.setJSON(update); err != nil {
+		return nil, err
+	}
+	return c.patch(ctx, req)
+}
+
+// MergePatch применяет JSON Merge Patch (RFC 7396): null в значении очищает поле.
+func (c *Client) MergePatch(ctx context.Context, id int, patch map[string]any) (*Person, error) {
+	req := newRequest(http.MethodPatch, personPath(id), nil)
+	if err := req.setJSON(patch); err != nil {
+		return nil, err
+	}
+	req.contentType = "application/merge-patch+json"
+	return c.patch(ctx, req)
+}
+
+// JSONPatch применяет операции JSON Patch (RFC 6902).
+// Если операция test не прошла, ошибка соответствует ErrConflict.
+func (c *Client) JSONPatch(ctx context.Context, id int, ops []PatchOperation) (*Person, error) {
+	req := newRequest(http.MethodPatch, personPath(id), nil)
+	if err := req.setJSON(ops); err != nil {
+		return nil, err
+	}
+	req.contentType = "application/json-patch+json"
+	return c.patch(ctx, req)
+}
+
+// patch выполняет PATCH записи и декодирует результат.
+func (c *Client) patch(ctx context.Context, req *request) (*Person, error) {
+	var person Person
+	if _, err := c.doJSON(ctx, req, &person); err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// Delete помечает запись удалённой.
+func (c *Client) Delete(ctx context.Context, id int) error {
+	_, err := c.doJSON(ctx, newRequest(http.MethodDelete, personPath(id), nil), nil)
+	return err
+}
+
+// Restore восстанавливает удалённую запись.
+func (c *Client) Restore(ctx context.Context, id int) (*Person, error) {
+	var person Person
+	if _, err := c.doJSON(ctx, newRequest(http.MethodPost, personPath(id)+"/restore", nil), &person); err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// History возвращает изменения записи, начиная с последних; limit 0 означает значение сервиса по умолчанию.
+func (c *Client) History(ctx context.Context, id, limit, offset int) ([]HistoryEntry, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		query.Set("offset", strconv.Itoa(offset))
+	}
+	var entries []HistoryEntry
+	if _, err := c.doJSON(ctx, newRequest(http.MethodGet, personPath(id)+"/history", query), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Duplicates возвращает записи, похожие на указанную, с оценкой не ниже minScore.
+// Нулевые limit и minScore означают значения сервиса по умолчанию.
+func (c *Client) Duplicates(ctx context.Context, id, limit int, minScore float64) ([]DuplicateCandidate, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if minScore > 0 {
+		query.Set("min_score", strconv.FormatFloat(minScore, 'f', -1, 64))
+	}
+	var candidates []DuplicateCandidate
+	if _, err := c.doJSON(ctx, newRequest(http.MethodGet, personPath(id)+"/duplicates", query), &candidates); err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// Merge сливает записи-источники в целевую и возвращает целевую запись.
+func (c *Client) Merge(ctx context.Context, merge MergeRequest, opts ...RequestOption) (*Person, error) {
+	req := newRequest(http.MethodPost, personsPath+"/merge", nil)
+	if err := req.setJSON(merge); err != nil {
+		return nil, err
+	}
+	req.withIdempotencyKey(opts)
+
+	var person Person
+	if _, err := c.doJSON(ctx, req, &person); err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// PreviewBulkPatch возвращает число записей под фильтром, которые изменит BulkPatch.
+func (c *Client) PreviewBulkPatch(ctx context.Context, filter Filter, update PersonUpdate) (*BulkResult, error) {
+	return c.bulk(ctx, http.MethodPatch, filter, &update, true, 0, nil)
+}
+
+// BulkPatch изменяет все записи под фильтром.
+// expected — число записей из PreviewBulkPatch; если оно изменилось, ошибка соответствует ErrConflict.
+func (c *Client) BulkPatch(ctx context.Context, filter Filter, update PersonUpdate, expected int, opts ...RequestOption) (*BulkResult, error) {
+	return c.bulk(ctx, http.MethodPatch, filter, &update, false, expected, opts)
+}
+
+// PreviewBulkDelete возвращает число записей под фильтром, которые удалит BulkDelete.
+func (c *Client) PreviewBulkDelete(ctx context.Context, filter Filter) (*BulkResult, error) {
+	return c.bulk(ctx, http.MethodDelete, filter, nil, true, 0, nil)
+}
+
+// BulkDelete помечает удалёнными все записи под фильтром.
+// expected — число записей из PreviewBulkDelete; если оно изменилось, ошибка соответствует ErrConflict.
+func (c *Client) BulkDelete(ctx context.Context, filter Filter, expected int, opts ...RequestOption) (*BulkResult, error) {
+	return c.bulk(ctx, http.MethodDelete, filter, nil, false, expected, opts)
+}
+
+// bulk выполняет массовую операцию или её предпросмотр.
+func (c *Client) bulk(ctx context.Context, method string, filter Filter, update *PersonUpdate, dryRun bool, expected int, opts []RequestOption) (*BulkResult, error) {
+	query := url.Values{}
+	filter.apply(query)
+	query.Set("dry_run", strconv.FormatBool(dryRun))
+	if !dryRun {
+		query.Set("expected_count", strconv.Itoa(expected))
+	}
+
+	req := newRequest(method, personsPath, query)
+	if update != nil {
+		if err := req.setJSON(update); err != nil {
+			return nil, err
+		}
+	}
+	if dryRun {
+		req.idempotent = true
+	} else {
+		req.withIdempotencyKey(opts)
+	}
+
+	var result BulkResult
+	if _, err := c.doJSON(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,310 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"person-service/internal/api"
+	"person-service/internal/auth"
+	"person-service/internal/config"
+	"person-service/internal/health"
+	"person-service/internal/models"
+	"person-service/internal/ratelimit"
+	"person-service/internal/repository"
+	"person-service/internal/service"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// memoryRepository хранит записи в памяти; методы, не нужные тестам клиента, не реализованы.
+type memoryRepository struct {
+	repository.PersonRepository
+	mu      sync.Mutex
+	persons map[int]*models.Person
+	nextID  int
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{persons: map[int]*models.Person{}}
+}
+
+// add сохраняет запись в обход API и возвращает её копию.
+func (r *memoryRepository) add(person models.Person) *models.Person {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	person.ID = r.nextID
+	person.CreatedAt = time.Now()
+	person.Version = 1
+	r.persons[person.ID] = &person
+	copied := person
+	return &copied
+}
+
+// count возвращает число сохранённых записей.
+func (r *memoryRepository) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.persons)
+}
+
+func (r *memoryRepository) Create(_ context.Context, person *models.Person) error {
+	created := r.add(*person)
+	person.ID, person.CreatedAt, person.Version = created.ID, created.CreatedAt, created.Version
+	return nil
+}
+
+func (r *memoryRepository) CreateBatch(_ context.Context, persons []*models.Person, _ bool) ([]error, error) {
+	for _, person := range persons {
+		created := r.add(*person)
+		person.ID, person.CreatedAt, person.Version = created.ID, created.CreatedAt, created.Version
+	}
+	return make([]error, len(persons)), nil
+}
+
+func (r *memoryRepository) GetByID(_ context.Context, id int, includeDeleted bool, _ []string) (*models.Person, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	person, ok := r.persons[id]
+	if !ok || (person.DeletedAt != nil && !includeDeleted) {
+		return nil, &repository.NotFoundError{ID: id}
+	}
+	copied := *person
+	return &copied, nil
+}
+
+func (r *memoryRepository) List(_ context.Context, limit, offset int, filters map[string]string, _ []string) ([]*models.Person, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]int, 0, len(r.persons))
+	for id, person := range r.persons {
+		if person.DeletedAt != nil {
+			continue
+		}
+		if surname, ok := filters["surname"]; ok && (person.Surname == nil || !strings.Contains(*person.Surname, surname)) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	persons := []*models.Person{}
+	for _, id := range ids[min(offset, len(ids)):min(offset+limit, len(ids))] {
+		copied := *r.persons[id]
+		persons = append(persons, &copied)
+	}
+	return persons, nil
+}
+
+func (r *memoryRepository) ApplyPatch(_ context.Context, id int, _ models.HistoryAction, apply func(person *models.Person) error) (*models.Person, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	person, ok := r.persons[id]
+	if !ok || person.DeletedAt != nil {
+		return nil, &repository.NotFoundError{ID: id}
+	}
+	updated := *person
+	if err := apply(&updated); err != nil {
+		return nil, err
+	}
+	updated.Version++
+	r.persons[id] = &updated
+	copied := updated
+	return &copied, nil
+}
+
+func (r *memoryRepository) Restore(_ context.Context, id int) (*models.Person, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	person, ok := r.persons[id]
+	if !ok || person.DeletedAt == nil {
+		return nil, &repository.NotFoundError{ID: id}
+	}
+	person.DeletedAt = nil
+	person.Version++
+	copied := *person
+	return &copied, nil
+}
+
+// FindSimilar считает похожими неудалённые записи с тем же именем и фамилией.
+func (r *memoryRepository) FindSimilar(_ context.Context, person *models.Person, excludeID, limit int) ([]*models.DuplicateCandidate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	candidates := []*models.DuplicateCandidate{}
+	for id, existing := range r.persons {
+		if id == excludeID || existing.DeletedAt != nil || len(candidates) == limit {
+			continue
+		}
+		if existing.Name == person.Name && existing.Surname != nil && person.Surname != nil && *existing.Surname == *person.Surname {
+			copied := *existing
+			candidates = append(candidates, &models.DuplicateCandidate{Person: &copied, Similarity: 1})
+		}
+	}
+	return candidates, nil
+}
+
+// memoryIdempotency хранит ключи идемпотентности в памяти.
+type memoryIdempotency struct {
+	mu      sync.Mutex
+	records map[string]*memoryIdempotencyRecord
+}
+
+type memoryIdempotencyRecord struct {
+	models.IdempotencyRecord
+	lockedUntil time.Time
+}
+
+func newMemoryIdempotency() *memoryIdempotency {
+	return &memoryIdempotency{records: map[string]*memoryIdempotencyRecord{}}
+}
+
+func (m *memoryIdempotency) Reserve(_ context.Context, key, requestHash string, now, lockedUntil, expiresAt time.Time) (*models.IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.records[key]; ok && existing.ExpiresAt.After(now) && (existing.Completed() || existing.lockedUntil.After(now)) {
+		record := existing.IdempotencyRecord
+		return &record, false, nil
+	}
+	m.records[key] = &memoryIdempotencyRecord{
+		IdempotencyRecord: models.IdempotencyRecord{Key: key, RequestHash: requestHash, CreatedAt: now, ExpiresAt: expiresAt},
+		lockedUntil:       lockedUntil,
+	}
+	return nil, true, nil
+}
+
+func (m *memoryIdempotency) Complete(_ context.Context, key string, reservedAt time.Time, statusCode int, headers map[string]string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.records[key]
+	if !ok || !record.CreatedAt.Equal(reservedAt) {
+		return fmt.Errorf("ключ %s не зарезервирован", key)
+	}
+	record.StatusCode, record.Headers, record.Body = statusCode, headers, body
+	return nil
+}
+
+func (m *memoryIdempotency) Extend(_ context.Context, key string, reservedAt, lockedUntil time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if record, ok := m.records[key]; ok && record.CreatedAt.Equal(reservedAt) && !record.Completed() {
+		record.lockedUntil = lockedUntil
+	}
+	return nil
+}
+
+func (m *memoryIdempotency) Release(_ context.Context, key string, reservedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if record, ok := m.records[key]; ok && record.CreatedAt.Equal(reservedAt) {
+		delete(m.records, key)
+	}
+	return nil
+}
+
+func (m *memoryIdempotency) PurgeExpired(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+// testServer запускает api.NewServer на репозиториях в памяти. Ответ на запрос можно подменить на 503
+// после того, как сервер его выполнил, — как при обрыве связи с балансировщиком.
+type testServer struct {
+	*httptest.Server
+	repo *memoryRepository
+
+	mu       sync.Mutex
+	failures int
+	requests []*http.Request
+}
+
+// serverOptions изменяет настройки сервиса перед запуском тестового сервера.
+type serverOptions func(cfg *config.Config)
+
+// unlimited — правило ограничения частоты, которое не мешает тестам.
+var unlimited = config.RateLimitRule{Requests: 1000, Period: time.Second, Burst: 1000}
+
+func newTestServer(t *testing.T, opts ...serverOptions) *testServer {
+	t.Helper()
+	logger := zap.NewNop()
+
+	// Внешние API обогащения отвечают одним документом с возрастом, полом и национальностью
+	enrichment := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"age": 35, "gender": "male", "country": [{"country_id": "RU"}]}`)
+	}))
+	t.Cleanup(enrichment.Close)
+
+	cfg := &config.Config{
+		Server: config.Server{BulkMaxRows: 100, BatchMaxItems: 100, RequestMaxBytes: 1 << 20},
+		APIs:   config.APIs{Agify: enrichment.URL, Genderize: enrichment.URL, Nationalize: enrichment.URL},
+		Auth:   config.Auth{Disabled: true},
+		RateLimit: config.RateLimit{
+			Read: unlimited, Write: unlimited, Create: unlimited, Bulk: unlimited,
+		},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	policy, err := auth.NewPolicy(nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := newMemoryRepository()
+	svc := service.NewPersonService(repo, logger, &cfg.APIs, &cfg.Duplicates, &cfg.Enrichment, policy)
+	idempotencyService := service.NewIdempotencyService(newMemoryIdempotency(), logger, time.Hour, time.Minute)
+	limiter := ratelimit.NewLimiter(&cfg.RateLimit, ratelimit.NewMemoryStore(), logger)
+	server := api.NewServer(cfg, svc, idempotencyService, nil, policy, limiter, health.NewChecker(time.Second), logger)
+
+	ts := &testServer{repo: repo}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.mu.Lock()
+		ts.requests = append(ts.requests, r.Clone(context.Background()))
+		fail := ts.failures > 0
+		if fail {
+			ts.failures--
+		}
+		ts.mu.Unlock()
+
+		if !fail {
+			server.Router.ServeHTTP(w, r)
+			return
+		}
+		server.Router.ServeHTTP(httptest.NewRecorder(), r)
+		http.Error(w, `{"error": "Сервис временно недоступен"}`, http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// failNext подменяет ответы на следующие n запросов на 503.
+func (ts *testServer) failNext(n int) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.failures = n
+}
+
+// received возвращает запросы, полученные сервером, и сбрасывает их список.
+func (ts *testServer) received() []*http.Request {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	requests := ts.requests
+	ts.requests = nil
+	return requests
+}
+
+// newTestClient создаёт клиент тестового сервера с короткими паузами между повторами.
+func newTestClient(t *testing.T, ts *testServer, opts ...Option) *Client {
+	t.Helper()
+	opts = append([]Option{WithRetry(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond})}, opts...)
+	c, err := New(ts.URL, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
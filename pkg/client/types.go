@@ -0,0 +1,252 @@
+package client
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Gender — пол человека.
+type Gender string
+
+const (
+	GenderMale   Gender = "male"
+	GenderFemale Gender = "female"
+)
+
+// Person — запись о человеке.
+// При выборке части полей через Fields незапрошенные поля остаются нулевыми.
+type Person struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Surname     *string    `json:"surname"`
+	Patronymic  *string    `json:"patronymic"`
+	Age         *int       `json:"age"`
+	Gender      *Gender    `json:"gender"`
+	Nationality *string    `json:"nationality"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	Version     int        `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	// History заполняется при раскрытии history: последние изменения записи.
+	History []HistoryEntry `json:"history,omitempty"`
+	// Enrichment заполняется при раскрытии enrichment: последнее обогащение записи.
+	Enrichment *HistoryEntry `json:"enrichment,omitempty"`
+}
+
+// PersonInput — данные для создания записи; возраст, пол и национальность сервис получает сам.
+type PersonInput struct {
+	Name       string  `json:"name"`
+	Surname    *string `json:"surname,omitempty"`
+	Patronymic *string `json:"patronymic,omitempty"`
+}
+
+// PersonReplace — данные для полной замены записи; незаданные поля очищаются.
+type PersonReplace struct {
+	Name        string  `json:"name"`
+	Surname     *string `json:"surname"`
+	Patronymic  *string `json:"patronymic"`
+	Age         *int    `json:"age"`
+	Gender      *Gender `json:"gender"`
+	Nationality *string `json:"nationality"`
+}
+
+// PersonUpdate — данные для частичного обновления; изменяются только заданные поля.
+type PersonUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Surname     *string `json:"surname,omitempty"`
+	Patronymic  *string `json:"patronymic,omitempty"`
+	Age         *int    `json:"age,omitempty"`
+	Gender      *Gender `json:"gender,omitempty"`
+	Nationality *string `json:"nationality,omitempty"`
+}
+
+// PatchOperation — операция JSON Patch (RFC 6902): test, replace или remove.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// HistoryEntry — изменение записи. Before и After содержат только изменённые поля.
+type HistoryEntry struct {
+	ID        int64           `json:"id"`
+	PersonID  int             `json:"person_id"`
+	Action    string          `json:"action"`
+	Actor     *string         `json:"actor"`
+	RequestID *string         `json:"request_id"`
+	Source    string          `json:"source"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	Version   int             `json:"version"`
+	ChangedAt time.Time       `json:"changed_at"`
+}
+
+// BatchMode — поведение пакетного создания при ошибке элемента.
+type BatchMode string
+
+const (
+	// BatchAllOrNothing отменяет весь пакет при ошибке любого элемента.
+	BatchAllOrNothing BatchMode = "all_or_nothing"
+	// BatchPartial сохраняет корректные элементы.
+	BatchPartial BatchMode = "partial"
+)
+
+// BatchItemResult — результат одного элемента пакета: created, failed или skipped.
+type BatchItemResult struct {
+	Index  int     `json:"index"`
+	Status string  `json:"status"`
+	Person *Person `json:"person,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// BatchResult — результаты пакетного создания в порядке входных данных.
+type BatchResult struct {
+	Mode    BatchMode         `json:"mode"`
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Items   []BatchItemResult `json:"items"`
+}
+
+// BulkResult — результат массового изменения или удаления по фильтру.
+// При предпросмотре Matched содержит число записей, которые будут затронуты.
+type BulkResult struct {
+	DryRun   bool `json:"dry_run"`
+	Matched  int  `json:"matched"`
+	Affected int  `json:"affected"`
+	MaxRows  int  `json:"max_rows"`
+}
+
+// DuplicateCandidate — похожая запись и оценка сходства от 0 до 1.
+type DuplicateCandidate struct {
+	Person        *Person  `json:"person"`
+	Score         float64  `json:"score"`
+	Similarity    float64  `json:"similarity"`
+	MatchedFields []string `json:"matched_fields"`
+}
+
+// SurvivorshipRule — правило выбора значения поля при слиянии записей.
+type SurvivorshipRule string
+
+const (
+	RuleFirstNonEmpty SurvivorshipRule = "first_non_empty"
+	RuleTarget        SurvivorshipRule = "target"
+	RuleMostRecent    SurvivorshipRule = "most_recent"
+	RuleLongest       SurvivorshipRule = "longest"
+	RuleMax           SurvivorshipRule = "max"
+	RuleMin           SurvivorshipRule = "min"
+)
+
+// MergeRequest — записи для слияния и правила выбора значений по именам полей.
+type MergeRequest struct {
+	TargetID  int                         `json:"target_id"`
+	SourceIDs []int                       `json:"source_ids"`
+	Rules     map[string]SurvivorshipRule `json:"rules,omitempty"`
+}
+
+// ImportReport — результат импорта файла.
+type ImportReport struct {
+	Rows    int `json:"rows"`
+	Created int `json:"created"`
+	Failed  int `json:"failed"`
+	Records []struct {
+		Row int `json:"row"`
+		ID  int `json:"id"`
+	} `json:"records"`
+	Errors []struct {
+		Row   int    `json:"row"`
+		Error string `json:"error"`
+	} `json:"errors"`
+}
+
+// Filter — фильтр списка, выгрузки и массовых операций.
+// Строковые поля сравниваются по вхождению без учёта регистра; пустые поля не фильтруют.
+type Filter struct {
+	Name        string
+	Surname     string
+	Patronymic  string
+	Age         *int
+	Gender      Gender
+	Nationality string
+}
+
+// apply добавляет фильтр в параметры запроса.
+func (f Filter) apply(query url.Values) {
+	for key, value := range map[string]string{
+		"name":        f.Name,
+		"surname":     f.Surname,
+		"patronymic":  f.Patronymic,
+		"gender":      string(f.Gender),
+		"nationality": f.Nationality,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	if f.Age != nil {
+		query.Set("age", strconv.Itoa(*f.Age))
+	}
+}
+
+// ReadOptions задаёт состав ответа при чтении записей.
+type ReadOptions struct {
+	// IncludeDeleted включает удалённые записи.
+	IncludeDeleted bool
+	// Fields ограничивает поля ответа, например id, name, surname.
+	Fields []string
+	// Expand раскрывает связанные данные: history, enrichment.
+	Expand []string
+}
+
+// apply добавляет параметры чтения в запрос.
+func (o ReadOptions) apply(query url.Values) {
+	if o.IncludeDeleted {
+		query.Set("include_deleted", "true")
+	}
+	if len(o.Fields) > 0 {
+		query.Set("fields", strings.Join(o.Fields, ","))
+	}
+	if len(o.Expand) > 0 {
+		query.Set("expand", strings.Join(o.Expand, ","))
+	}
+}
+
+// ListOptions задаёт страницу и фильтр списка.
+type ListOptions struct {
+	Filter
+	ReadOptions
+	// Limit — размер страницы, по умолчанию 10.
+	Limit int
+	// Offset — число пропускаемых записей.
+	Offset int
+}
+
+// GetOptions задаёт параметры чтения записи.
+type GetOptions struct {
+	ReadOptions
+	// AsOf возвращает состояние записи на указанный момент.
+	AsOf time.Time
+}
+
+// ImportOptions задаёт параметры импорта файла.
+type ImportOptions struct {
+	// Format — csv или xlsx; обязателен, так как файл отправляется телом запроса без имени.
+	Format string
+	// Encoding — кодировка CSV: auto, utf-8 или windows-1251.
+	Encoding string
+	// Mapping сопоставляет поля колонкам, например "name=Имя,surname=2".
+	Mapping string
+	// Enrich включает обогащение через внешние API.
+	Enrich bool
+}
+
+// ExportOptions задаёт формат и фильтр выгрузки.
+type ExportOptions struct {
+	Filter
+	// Format — csv (по умолчанию), ndjson или parquet.
+	Format string
+	// IncludeDeleted включает удалённые записи.
+	IncludeDeleted bool
+}
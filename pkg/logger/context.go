@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// loggerKey — ключ контекста с логгером запроса.
+type loggerKey struct{}
+
+// WithContext возвращает контекст с логгером запроса l, например с полем request_id.
+func WithContext(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext возвращает логгер запроса из ctx или, если его нет, base. К логгеру добавляются поля
+// trace_id и span_id активного span, чтобы строки логов одного запроса можно было найти по трассировке.
+func FromContext(ctx context.Context, base *zap.Logger) *zap.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		base = l
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return base
+	}
+	return base.With(zap.String("trace_id", sc.TraceID().String()), zap.String("span_id", sc.SpanID().String()))
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"person-service/migrations"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// ErrSchemaOutdated возвращается, если схема базы данных старше версии, на которую рассчитан код.
+var ErrSchemaOutdated = errors.New("схема базы данных устарела")
+
+// Migrator применяет миграции схемы из migrations/, встроенные в бинарный файл.
+// Изменения схемы выполняются под advisory lock Postgres, поэтому экземпляры,
+// запущенные одновременно, применяют миграции по очереди, а не параллельно.
+type Migrator struct {
+	provider *goose.Provider
+}
+
+// NewMigrator создаёт Migrator, выполняющий миграции через соединения пула p.
+func NewMigrator(p *Pool) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось создать блокировку миграций: %w", err)
+	}
+	db := stdlib.OpenDBFromPool(p.Pool)
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations.FS, goose.WithSessionLocker(locker))
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("не удалось загрузить миграции: %w", err)
+	}
+	return &Migrator{provider: provider}, nil
+}
+
+// Up применяет все неприменённые миграции и возвращает их результаты.
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	results, err := m.provider.Up(ctx)
+	if err != nil {
+		return results, fmt.Errorf("не удалось применить миграции: %w", err)
+	}
+	return results, nil
+}
+
+// Down откатывает последнюю применённую миграцию.
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	result, err := m.provider.Down(ctx)
+	if err != nil {
+		return result, fmt.Errorf("не удалось откатить миграцию: %w", err)
+	}
+	return result, nil
+}
+
+// Status возвращает состояние всех миграций в порядке версий.
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	status, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить состояние миграций: %w", err)
+	}
+	return status, nil
+}
+
+// Version возвращает версию схемы базы данных и версию последней встроенной миграции.
+func (m *Migrator) Version(ctx context.Context) (current, latest int64, err error) {
+	current, latest, err = m.provider.GetVersions(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("не удалось получить версию схемы: %w", err)
+	}
+	return current, latest, nil
+}
+
+// Check возвращает ErrSchemaOutdated, если применены не все встроенные миграции.
+// Более новая схема допустима: во время обновления её уже применили экземпляры новой версии.
+func (m *Migrator) Check(ctx context.Context) error {
+	current, latest, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if current < latest {
+		return fmt.Errorf("%w: версия %d, требуется %d; выполните person-service migrate up", ErrSchemaOutdated, current, latest)
+	}
+	return nil
+}
+
+// Close освобождает соединение Migrator; пул остаётся открытым.
+func (m *Migrator) Close() error {
+	return m.provider.Close()
+}
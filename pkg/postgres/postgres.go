@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"person-service/internal/config"
+	"person-service/internal/tenant"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
 )
 
 // Pool представляет пул соединений к Postgres.
@@ -14,7 +17,8 @@ type Pool struct {
 }
 
 // NewPostgres создаёт новый пул соединений к Postgres на основе конфигурации.
-func NewPostgres(ctx context.Context, cfg *config.Database) (*Pool, error) {
+// Запросы пишутся на уровне Debug в логгер запроса из контекста или, без него, в logger.
+func NewPostgres(ctx context.Context, cfg *config.Database, logger *zap.Logger) (*Pool, error) {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name)
 
@@ -25,6 +29,17 @@ func NewPostgres(ctx context.Context, cfg *config.Database) (*Pool, error) {
 
 	config.MaxConns = 10
 	config.MinConns = 2
+	config.ConnConfig.Tracer = queryTracer{logger: logger}
+
+	// Арендатор запроса передаётся в app.tenant_id, который проверяют политики RLS.
+	// Без арендатора в контексте (фоновые задачи) значение пустое, и политики не ограничивают строки.
+	if cfg.RowLevelSecurity {
+		config.BeforeAcquire = func(ctx context.Context, conn *pgx.Conn) bool {
+			tenantID, _ := tenant.FromContext(ctx)
+			_, err := conn.Exec(ctx, queryNamePrefix+"SetTenant\nSELECT set_config('app.tenant_id', $1, false)", tenantID)
+			return err == nil
+		}
+	}
 
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"person-service/internal/metrics"
+	"person-service/internal/tracing"
+	"person-service/pkg/logger"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// queryNamePrefix начинает первую строку именованного запроса из queries.sql.
+const queryNamePrefix = "-- name: "
+
+// queryStartKey — ключ контекста с началом выполнения запроса.
+type queryStartKey struct{}
+
+// queryStart — имя запроса, момент начала его выполнения и span запроса.
+type queryStart struct {
+	name string
+	at   time.Time
+	span trace.Span
+}
+
+// queryTracer измеряет длительность запросов к Postgres для метрик, создаёт span для каждого запроса
+// и на уровне Debug пишет запрос в лог запроса из контекста, чтобы запросы репозиториев были видны рядом
+// со строками обработчика и PersonService.
+type queryTracer struct {
+	logger *zap.Logger
+}
+
+// TraceQueryStart запоминает имя запроса и время начала и начинает span "db <имя запроса>".
+func (t queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	name := queryName(data.SQL)
+	ctx, span := tracing.Start(ctx, "db "+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(name), semconv.DBQueryText(data.SQL)))
+	return context.WithValue(ctx, queryStartKey{}, queryStart{name: name, at: time.Now(), span: span})
+}
+
+// TraceQueryEnd записывает длительность запроса и ошибку, если она была, и завершает span.
+func (t queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	duration := time.Since(start.at)
+	metrics.DBQueryDuration.WithLabelValues(start.name).Observe(duration.Seconds())
+	if data.Err != nil {
+		metrics.DBQueryErrors.WithLabelValues(start.name).Inc()
+	}
+	if t.logger.Core().Enabled(zapcore.DebugLevel) {
+		log := logger.FromContext(ctx, t.logger)
+		if data.Err != nil {
+			log.Debug("Ошибка запроса к Postgres", zap.String("query", start.name), zap.Duration("duration", duration), logger.ErrorKV("error", data.Err))
+		} else {
+			log.Debug("Запрос к Postgres выполнен", zap.String("query", start.name), zap.Duration("duration", duration), zap.Int64("rows", data.CommandTag.RowsAffected()))
+		}
+	}
+	err := data.Err
+	tracing.End(start.span, &err)
+}
+
+// queryName возвращает имя запроса из первой строки "-- name: X" или, для служебных запросов
+// вроде begin и commit, первое слово запроса в нижнем регистре.
+func queryName(sql string) string {
+	if rest, ok := strings.CutPrefix(sql, queryNamePrefix); ok {
+		name, _, _ := strings.Cut(rest, "\n")
+		return strings.TrimSpace(name)
+	}
+	word, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	return strings.ToLower(word)
+}
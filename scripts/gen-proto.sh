@@ -0,0 +1,9 @@
+#!/bin/sh
+# Генерирует Go-код gRPC API из proto/ в pkg/pb/.
+# Требуются protoc, protoc-gen-go и protoc-gen-go-grpc в PATH.
+set -e
+cd "$(dirname "$0")/.."
+protoc -I proto \
+  --go_out=. --go_opt=module=person-service \
+  --go-grpc_out=. --go-grpc_opt=module=person-service \
+  proto/person/v1/person.proto